@@ -26,8 +26,8 @@ import (
 // Config holds the different cryptographc informations necessary to run the
 // randomness beacon.
 type Config struct {
-	// XXX Think of removing uncessary access to keypair - only given for index
-	Private  *key.Pair
+	// Public is the identity of this node in the group
+	Public   *key.Identity
 	Share    *key.Share
 	Group    *key.Group
 	Scheme   sign.ThresholdScheme
@@ -57,6 +57,12 @@ type Handler struct {
 	// the signature of this node for the current round. acts like a cache to
 	// avoid resigning it for each request.
 	currentPartial *partialSig
+	// the packet of this node for the current round, sent again to the nodes
+	// coming back after being skipped as down
+	roundPacket *proto.BeaconPacket
+	// the nodes of the group to contact, replaced when a node announces new
+	// addresses
+	nodes []*key.Identity
 
 	index int
 
@@ -73,10 +79,10 @@ type Handler struct {
 // NewHandler returns a fresh handler ready to serve and create randomness
 // beacon
 func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Handler, error) {
-	if conf.Private == nil || conf.Share == nil || conf.Group == nil {
+	if conf.Public == nil || conf.Share == nil || conf.Group == nil {
 		return nil, errors.New("beacon: invalid configuration")
 	}
-	idx, exists := conf.Group.Index(conf.Private.Public)
+	idx, exists := conf.Group.Index(conf.Public)
 	if !exists {
 		return nil, errors.New("beacon: keypair not included in the given group")
 	}
@@ -87,12 +93,13 @@ func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Han
 	//conf.WaitTime = 0 * time.Millisecond
 
 	c.SetTimeout(1 * time.Second)
-	addr := conf.Private.Public.Address()
+	addr := conf.Public.Address()
 	logger := l.With("index", idx)
 	handler := &Handler{
 		conf:    conf,
 		client:  c,
 		group:   conf.Group,
+		nodes:   conf.Group.Nodes,
 		share:   conf.Share,
 		pub:     conf.Share.PubPoly(),
 		index:   idx,
@@ -100,7 +107,7 @@ func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Han
 		store:   s,
 		close:   make(chan bool),
 		l:       logger,
-		manager: newRoundManager(l, conf.Group.Threshold, conf.Scheme),
+		manager: newRoundManager(l, conf.Group.Threshold, conf.Scheme, conf.Group.Unchained),
 	}
 	// genesis block at round 0, next block at round 1
 	// THIS is to change when one network wants to build on top of another
@@ -143,7 +150,7 @@ func (h *Handler) ProcessBeacon(c context.Context, p *proto.BeaconPacket) (*prot
 		return nil, fmt.Errorf("invalid previous round: %d > current %d", p.GetPreviousRound(), currentRound)
 	}
 
-	msg := Message(p.GetPreviousSig(), p.GetPreviousRound(), p.GetRound())
+	msg := MessageFor(h.conf.Group.Unchained, p.GetPreviousSig(), p.GetPreviousRound(), p.GetRound())
 	// verify if request is valid
 	if err := h.conf.Scheme.VerifyPartial(h.pub, msg, p.GetPartialSig()); err != nil {
 		//shortPub := h.pub.Eval(1).V.String()[14:19]
@@ -157,9 +164,47 @@ func (h *Handler) ProcessBeacon(c context.Context, p *proto.BeaconPacket) (*prot
 		return nil, errors.New("same index as this node")
 	}
 	h.manager.NewBeacon(p)
+	h.peerAlive(idx, currentRound)
 	return new(proto.Empty), nil
 }
 
+// peerAlive resets the health of the node at the given index if the client
+// skipped it as down, and sends it the packet of the current round it missed.
+func (h *Handler) peerAlive(idx int, round uint64) {
+	reporter, ok := h.client.(net.HealthReporter)
+	if !ok {
+		return
+	}
+	id := h.currentNodes()[idx]
+	if reporter.PeerStatus(id.Address()).State != net.PeerDown {
+		return
+	}
+	reporter.PeerAlive(id.Address())
+	h.Lock()
+	packet := h.roundPacket
+	h.Unlock()
+	if packet == nil || packet.GetRound() != round {
+		return
+	}
+	h.l.Debug("beacon_round", round, "back_up", id.Address())
+	go h.client.NewBeacon(id, packet)
+}
+
+// UpdateNodes replaces the identities of the nodes the handler contacts, for
+// example after a node of the group announced new addresses. The nodes must
+// have the same keys, in the same order, as the nodes of the group.
+func (h *Handler) UpdateNodes(nodes []*key.Identity) {
+	h.Lock()
+	defer h.Unlock()
+	h.nodes = nodes
+}
+
+func (h *Handler) currentNodes() []*key.Identity {
+	h.Lock()
+	defer h.Unlock()
+	return h.nodes
+}
+
 // Store returns the store associated with this beacon handler
 func (h *Handler) Store() Store {
 	return h.store
@@ -221,7 +266,24 @@ func (h *Handler) Start() error {
 // it sync its local chain with other nodes to be able to participate in the
 // next upcoming round.
 func (h *Handler) Catchup() {
-	ids := shuffleNodes(h.conf.Group.Nodes)
+	ids := shuffleNodes(h.currentNodes())
+	if h.conf.Group.Unchained {
+		// rounds don't depend on the previous ones so the node participates
+		// in the next round right away and syncs its chain in the background
+		lastBeacon, err := h.store.Last()
+		if err != nil {
+			h.l.Error("catchup", "no_last_beacon", "err", err)
+			return
+		}
+		go func() {
+			if _, err := h.Sync(ids); err != nil {
+				h.l.Error("syncing", err)
+			}
+		}()
+		nextRound, nextTime := NextRound(h.conf.Clock.Now().Unix(), h.conf.Group.Period, h.conf.Group.GenesisTime)
+		h.run(lastBeacon.Signature, lastBeacon.Round, nextRound, nextTime)
+		return
+	}
 	// we sync with the nodes of the current network
 	prevBeacon, err := h.Sync(ids)
 	if err != nil {
@@ -250,7 +312,7 @@ func (h *Handler) Transition(prevNodes []*key.Identity) error {
 	tTime = tTime - int64(h.conf.Group.Period.Seconds())
 	tRound = tRound - 1
 	if tTime != targetTime {
-		fmt.Printf("node %d - %s : next time %d vs transition time %d\n", h.index, h.conf.Private.Public.Address(), tTime, targetTime)
+		fmt.Printf("node %d - %s : next time %d vs transition time %d\n", h.index, h.conf.Public.Address(), tTime, targetTime)
 		h.l.Fatal("transition_time", "invalid")
 		return nil
 	}
@@ -352,7 +414,7 @@ func (h *Handler) run(initSig []byte, initRound, nextRound uint64, startTime int
 	now := h.conf.Clock.Now().Unix()
 	sleepTime := startTime - now
 	h.l.Info("run_round", nextRound, "waiting_for", sleepTime, "period", h.conf.Group.Period.String())
-	//fmt.Printf("node %d - %s | pointer: %p (genesis %d) - current time %d / now %d -> startTime %d - sleeping for %d ... (clock %p) - initRound: %d, nextRound %d\n", h.index, h.conf.Private.Public.Address(), h, h.conf.Group.GenesisTime, h.conf.Clock.Now().Unix(), now, startTime, sleepTime, h.conf.Clock, initRound, nextRound)
+	//fmt.Printf("node %d - %s | pointer: %p (genesis %d) - current time %d / now %d -> startTime %d - sleeping for %d ... (clock %p) - initRound: %d, nextRound %d\n", h.index, h.conf.Public.Address(), h, h.conf.Group.GenesisTime, h.conf.Clock.Now().Unix(), now, startTime, sleepTime, h.conf.Clock, initRound, nextRound)
 	h.conf.Clock.Sleep(time.Duration(sleepTime) * time.Second)
 	//fmt.Printf("\n%d: node %d finished sleeping - time %d - starttime should be %d - clock pointer %p\n", time.Now().Unix(), h.index, h.conf.Clock.Now().Unix(), startTime, h.conf.Clock)
 	// start for this round already
@@ -429,14 +491,14 @@ func (h *Handler) run(initSig []byte, initRound, nextRound uint64, startTime int
 func (h *Handler) runRound(currentRound, prevRound uint64, prevSig []byte, winCh chan *Beacon, closeCh chan bool) {
 	incomings := h.manager.NewRound(prevRound, currentRound)
 	// we sign for the new current round
-	msg := Message(prevSig, prevRound, currentRound)
+	msg := MessageFor(h.conf.Group.Unchained, prevSig, prevRound, currentRound)
 	currSig, err := h.conf.Scheme.Sign(h.share.PrivateShare(), msg)
 	if err != nil {
 		h.l.Fatal("beacon_round", fmt.Sprintf("creating signature: %s", err), "round", currentRound)
 		return
 	}
 	shortPub := h.pub.Eval(1).V.String()[14:19]
-	h.l.Debug("start_round", currentRound, "time", h.conf.Clock.Now(), "from_sig", shortSigStr(prevSig), "from_round", prevRound, "msg_sign", shortSigStr(msg), "short_pub", shortPub, "handler", fmt.Sprintf("%p", h), "addr", h.conf.Private.Public.Address())
+	h.l.Debug("start_round", currentRound, "time", h.conf.Clock.Now(), "from_sig", shortSigStr(prevSig), "from_round", prevRound, "msg_sign", shortSigStr(msg), "short_pub", shortPub, "handler", fmt.Sprintf("%p", h), "addr", h.conf.Public.Address())
 	packet := &proto.BeaconPacket{
 		Round:         currentRound,
 		PreviousRound: prevRound,
@@ -444,14 +506,17 @@ func (h *Handler) runRound(currentRound, prevRound uint64, prevSig []byte, winCh
 		PartialSig:    currSig,
 	}
 	h.manager.NewBeacon(packet)
+	h.Lock()
+	h.roundPacket = packet
+	h.Unlock()
 
 	// NOTE: sleep a while to not ask nodes too fast - they may have a slight bias
 	// in time
 	time.Sleep(h.conf.WaitTime)
 	// send all requests in parallel
 	h.client.SetTimeout(1 * time.Second)
-	for _, id := range h.conf.Group.Nodes {
-		if h.addr == id.Address() {
+	for _, id := range h.currentNodes() {
+		if id.Equal(h.conf.Public) {
 			continue
 		}
 		// this go routine sends the packet to one node. It will always
@@ -459,6 +524,11 @@ func (h *Handler) runRound(currentRound, prevRound uint64, prevSig []byte, winCh
 		go func(i *key.Identity) {
 			h.l.Debug("beacon_round", currentRound, "send_to", i.Address())
 			_, err := h.client.NewBeacon(i, packet)
+			if net.IsPeerDown(err) {
+				// the client retries the node once its backoff elapsed
+				h.l.Debug("beacon_round", currentRound, "skip_down", i.Address())
+				return
+			}
 			if err != nil {
 				h.l.Error("beacon_round", currentRound, "err_request", err, "from", i.Address())
 				if strings.Contains(err.Error(), errOutOfRound) {
@@ -484,7 +554,7 @@ func (h *Handler) runRound(currentRound, prevRound uint64, prevSig []byte, winCh
 		}
 	}
 	h.l.Debug("beacon_round", currentRound, "got_all_sig", fmt.Sprintf("%d/%d", len(partials), h.conf.Group.Threshold))
-	//fmt.Printf("\n%d - %s got ALL signatures #1\n\n", h.index, h.conf.Private.Public.Address())
+	//fmt.Printf("\n%d - %s got ALL signatures #1\n\n", h.index, h.conf.Public.Address())
 	finalSig, err := h.conf.Scheme.Recover(h.pub, msg, partials, h.group.Threshold, h.group.Len())
 	if err != nil {
 		h.l.Error("beacon_round", currentRound, "no final beacon", err)
@@ -512,7 +582,7 @@ func (h *Handler) runRound(currentRound, prevRound uint64, prevSig []byte, winCh
 	//slog.Infof("beacon: %s round %d finished: %x", h.addr, round, finalSig)
 	shortSig := shortSigStr(finalSig)
 	shortPrevSig := shortSigStr(prevSig)
-	shortRand := shortSigStr(beacon.Randomness())
+	shortRand := shortSigStr(beacon.RandomnessWith(h.conf.Group.RandomnessHashFunc()))
 	h.l.Info("done_round", currentRound, "signature", shortSig, "randomness", shortRand, "previous_sig", shortPrevSig)
 	select {
 	case <-closeCh:
@@ -563,15 +633,17 @@ func (h *Handler) syncFrom(to []*key.Identity, initRound uint64, initSignature [
 				cancel()
 				break
 			}
-			// we want answers consistent from our round that we have
+			// we want answers consistent from our round that we have, unless
+			// the rounds are independent of each other
 			prevSig := syncReply.GetPreviousSig()
 			prevRound := syncReply.GetPreviousRound()
-			if currentRound != prevRound || !bytes.Equal(prevSig, currentSig) {
+			chained := !h.conf.Group.Unchained
+			if chained && (currentRound != prevRound || !bytes.Equal(prevSig, currentSig)) {
 				h.l.Error("sync_round", currentRound, "from", id.Address(), "want_prevRound", currentRound, "got_prevRound", prevRound, "want_prevSig", shortSigStr(currentSig), "got_prevSig", shortSigStr(prevSig), "got_sig", shortSigStr(syncReply.GetSignature()), "round", syncReply.GetRound())
 				cancel()
 				break
 			}
-			msg := Message(prevSig, prevRound, syncReply.GetRound())
+			msg := MessageFor(h.conf.Group.Unchained, prevSig, prevRound, syncReply.GetRound())
 			if err := h.conf.Scheme.VerifyRecovered(h.pub.Commit(), msg, syncReply.GetSignature()); err != nil {
 				h.l.Error("sync_round", currentRound, "invalid_sig", err, "from", id.Address())
 				cancel()
@@ -631,7 +703,7 @@ func (h *Handler) StopAt(stopTime int64) error {
 	}
 	duration := time.Duration(stopTime-now) * time.Second
 	h.l.Debug("stop_at", stopTime, "sleep_for", duration.Seconds())
-	//fmt.Printf(" || STOP now is %d, stopTime is %d -> will sleep %d - beacon address %p - %s\n", now, stopTime, int64(duration.Seconds()), h, h.conf.Private.Public.Address())
+	//fmt.Printf(" || STOP now is %d, stopTime is %d -> will sleep %d - beacon address %p - %s\n", now, stopTime, int64(duration.Seconds()), h, h.conf.Public.Address())
 	h.conf.Clock.Sleep(duration)
 	h.Stop()
 	//fmt.Printf(" || STOP beacon address %p\n", h)
@@ -106,6 +106,7 @@ type node struct {
 }
 
 type BeaconTest struct {
+	prefix  string
 	paths   []string
 	n       int
 	thr     int
@@ -120,14 +121,22 @@ type BeaconTest struct {
 }
 
 func NewBeaconTest(n, thr int, period time.Duration, genesisTime int64) *BeaconTest {
-	paths := createBoltStores(prefixBeaconTest, n)
+	return newBeaconTest(prefixBeaconTest, n, thr, period, genesisTime, false)
+}
+
+// newBeaconTest creates the stores under their own prefix so the asynchronous
+// cleanup of a previous test does not remove them
+func newBeaconTest(prefix string, n, thr int, period time.Duration, genesisTime int64, unchained bool) *BeaconTest {
+	paths := createBoltStores(prefix, n)
 	shares, public := dkgShares(n, thr)
 	privs, group := test.BatchIdentities(n)
 	group.Threshold = thr
 	group.Period = period
 	group.GenesisTime = genesisTime
+	group.Unchained = unchained
 
 	bt := &BeaconTest{
+		prefix:  prefix,
 		n:       n,
 		privs:   privs,
 		thr:     thr,
@@ -180,7 +189,7 @@ func (b *BeaconTest) CreateNode(i int) {
 		node.clock = clock.NewFakeClockAt(b.time.Now())
 		conf := &Config{
 			Group:    b.group,
-			Private:  p,
+			Public:   p.Public,
 			Share:    share,
 			Scheme:   key.Scheme,
 			Clock:    node.clock,
@@ -288,7 +297,7 @@ func (b *BeaconTest) StopAll() {
 }
 
 func (b *BeaconTest) CleanUp() {
-	deleteBoltStores(prefixBeaconTest)
+	deleteBoltStores(b.prefix)
 	b.StopAll()
 }
 
@@ -299,6 +308,8 @@ func checkErr(e error) {
 }
 
 func createBoltStores(prefix string, n int) []string {
+	// the stores of a previous run may remain if it did not clean up
+	deleteBoltStores(prefix)
 	paths := make([]string, n, n)
 	for i := 0; i < n; i++ {
 		paths[i] = path.Join(prefix, fmt.Sprintf("drand-%d", i))
@@ -463,3 +474,54 @@ func TestBeaconThreshold(t *testing.T) {
 	// expect lastnode to have catch up
 	makeRounds(nRounds, n)
 }
+
+func TestBeaconUnchained(t *testing.T) {
+	n := 3
+	thr := n/2 + 1
+	period := 2 * time.Second
+
+	offsetGenesis := 2 * time.Second
+	var genesisTime int64 = clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+
+	bt := newBeaconTest(prefixBeaconTest+"Unchained", n, thr, period, genesisTime, true)
+	defer bt.CleanUp()
+	var currentRound uint64 = 0
+	var counter = &sync.WaitGroup{}
+	myCallBack := func(b *Beacon) {
+		// the signature only covers the round
+		msg := UnchainedMessage(b.Round)
+		require.NoError(t, key.Scheme.VerifyRecovered(bt.dpublic, msg, b.Signature))
+		chained := Message(b.PreviousSig, b.PreviousRound, b.Round)
+		require.Error(t, key.Scheme.VerifyRecovered(bt.dpublic, chained, b.Signature))
+		if b.Round == currentRound {
+			counter.Done()
+		}
+	}
+	makeRounds := func(r int, howMany int) {
+		for i := 0; i < r; i++ {
+			currentRound++
+			counter.Add(howMany)
+			bt.MoveTime(period)
+			checkWait(counter)
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	for i := 0; i < n-1; i++ {
+		bt.CallbackFor(i, myCallBack)
+		bt.ServeBeacon(i)
+	}
+	bt.StartBeacons(n - 1)
+	currentRound = 1
+	counter.Add(n - 1)
+	bt.MoveTime(offsetGenesis)
+	checkWait(counter)
+	makeRounds(1, n-1)
+
+	// the last node joins without the history of the chain
+	bt.CallbackFor(n-1, myCallBack)
+	bt.ServeBeacon(n - 1)
+	bt.StartBeacon(n-1, true)
+	time.Sleep(100 * time.Millisecond)
+	makeRounds(2, n)
+}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"math"
 	"time"
+
+	"github.com/drand/drand/key"
 )
 
 // Beacon holds the randomness as well as the info to verify it.
@@ -40,15 +44,28 @@ func (b *Beacon) Unmarshal(buff []byte) error {
 	return json.Unmarshal(buff, b)
 }
 
-// Randomness returns the hashed signature. It is an example that uses sha256,
-// but it could use blake2b for example.
+// Randomness returns the signature hashed with sha256, the default hash of
+// the chains.
 func (b *Beacon) Randomness() []byte {
 	return RandomnessFromSignature(b.Signature)
 }
 
+// RandomnessWith returns the signature hashed with the given hash function,
+// the randomness hash of the group.
+func (b *Beacon) RandomnessWith(newHash func() hash.Hash) []byte {
+	return RandomnessWith(newHash, b.Signature)
+}
+
+// RandomnessFromSignature returns the signature hashed with sha256
 func RandomnessFromSignature(sig []byte) []byte {
-	out := sha256.Sum256(sig)
-	return out[:]
+	return RandomnessWith(sha256.New, sig)
+}
+
+// RandomnessWith returns the signature hashed with the given hash function
+func RandomnessWith(newHash func() hash.Hash, sig []byte) []byte {
+	h := newHash()
+	h.Write(sig)
+	return h.Sum(nil)
 }
 
 func (b *Beacon) String() string {
@@ -68,6 +85,35 @@ func Message(prevSig []byte, prevRound, currRound uint64) []byte {
 	return h.Sum(nil)
 }
 
+// UnchainedMessage returns the message to sign or to verify alongside a
+// beacon signature of an unchained group. It only depends on the round, so
+// the message of any future round is known in advance.
+// H ( currRound )
+func UnchainedMessage(currRound uint64) []byte {
+	h := sha256.New()
+	h.Write(roundToBytes(currRound))
+	return h.Sum(nil)
+}
+
+// MessageFor returns the message of the round according to the mode of the
+// group: unchained or chained to the previous signature.
+func MessageFor(unchained bool, prevSig []byte, prevRound, currRound uint64) []byte {
+	if unchained {
+		return UnchainedMessage(currRound)
+	}
+	return Message(prevSig, prevRound, currRound)
+}
+
+// Verify returns an error if the beacon is not signed with the distributed key
+// of the group, over the message of the mode of the group.
+func (b *Beacon) Verify(g *key.Group) error {
+	if g.PublicKey == nil {
+		return errors.New("beacon: group without distributed key")
+	}
+	msg := MessageFor(g.Unchained, b.PreviousSig, b.PreviousRound, b.Round)
+	return g.Suite().Scheme.VerifyRecovered(g.PublicKey.Key(), msg, b.Signature)
+}
+
 // TimeOfRound is returning the time the current round should happen
 func TimeOfRound(period time.Duration, genesis int64, round uint64) int64 {
 	if round == 0 {
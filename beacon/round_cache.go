@@ -14,16 +14,19 @@ type roundManager struct {
 	stop      chan bool
 	expected  int
 	sign      sign.ThresholdScheme
+	// partials of unchained rounds don't depend on the previous round
+	unchained bool
 	l         log.Logger
 }
 
-func newRoundManager(l log.Logger, thr int, s sign.ThresholdScheme) *roundManager {
+func newRoundManager(l log.Logger, thr int, s sign.ThresholdScheme, unchained bool) *roundManager {
 	r := &roundManager{
 		newRound:  make(chan roundBundle, 1),
 		newBeacon: make(chan *drand.BeaconPacket, thr),
 		stop:      make(chan bool, 1),
 		expected:  thr,
 		sign:      s,
+		unchained: unchained,
 		l:         l,
 	}
 	go r.run()
@@ -39,7 +42,7 @@ func (r *roundManager) run() {
 
 	checkPartial := func(p *drand.BeaconPacket) bool {
 		nowPrevious := p.GetPreviousRound() == currRound.lastRound
-		if !nowPrevious {
+		if !nowPrevious && !r.unchained {
 			msgs := []string{"check_for", "sync"}
 			if p.GetPreviousRound() < currRound.lastRound {
 				msgs[0] = "late_node_diff"
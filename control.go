@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/drand/drand/core"
 	"github.com/drand/drand/key"
@@ -18,50 +20,79 @@ import (
 // shareCmd decides whether the command is for a DKG or for a resharing and
 // dispatch to the respective sub-commands.
 func shareCmd(c *cli.Context) error {
-	if !c.Args().Present() {
-		fatal("drand: needs at least one group.toml file argument")
+	if c.IsSet(connectFlag.Name) {
+		return connectCmd(c)
 	}
-	groupPath := c.Args().First()
-	groupPath, err := filepath.Abs(groupPath)
+	group, err := groupInfo(c)
 	if err != nil {
-		fatal("can't open group path absolute path from %s", c.Args().First())
+		fatal("drand: %s", err)
+	}
+
+	if c.Bool(dryRunFlag.Name) {
+		if !c.Bool(reshareFlag.Name) {
+			fatal("drand: dry run is only available for resharing, use --reshare")
+		}
+		return reshareDryRun(c, group)
 	}
-	testEmptyGroup(groupPath)
 
 	if c.IsSet(oldGroupFlag.Name) {
 		testEmptyGroup(c.String(oldGroupFlag.Name))
 		fmt.Println("drand: old group file given for resharing protocol")
-		return initReshare(c, groupPath)
+		return initReshare(c, group)
+	}
+
+	if c.Bool(reshareFlag.Name) {
+		return initReshare(c, group)
 	}
 
 	conf := contextToConfig(c)
 	fs := key.NewFileStore(conf.ConfigFolder())
 	_, errG := fs.LoadGroup()
 	_, errS := fs.LoadShare()
+	if errS == key.ErrPassphrase {
+		// the share is present but encrypted
+		errS = nil
+	}
 	_, errD := fs.LoadDistPublic()
 	// XXX place that logic inside core/ directly with only one method
 	freshRun := errG != nil || errS != nil || errD != nil
 	if freshRun {
 		fmt.Println("drand: no current distributed key -> running DKG protocol.")
-		err = initDKG(c, groupPath)
+		err = initDKG(c, group)
 	} else {
 		fmt.Println("drand: found distributed key -> running resharing protocol.")
-		err = initReshare(c, groupPath)
+		err = initReshare(c, group)
 	}
 	return err
 }
 
+// groupInfo returns the location of the group to use for the sharing
+// protocol, either from the group url flag or from the group file given as
+// argument.
+func groupInfo(c *cli.Context) (*control.GroupInfo, error) {
+	hash := c.String(groupHashFlag.Name)
+	if c.IsSet(groupURLFlag.Name) {
+		if c.Args().Present() {
+			return nil, errors.New("group file argument and group url flag are mutually exclusive")
+		}
+		return net.GroupFromURL(c.String(groupURLFlag.Name), hash), nil
+	}
+	if !c.Args().Present() {
+		return nil, errors.New("needs at least one group.toml file argument or the group url flag")
+	}
+	groupPath, err := filepath.Abs(c.Args().First())
+	if err != nil {
+		return nil, fmt.Errorf("can't open group path absolute path from %s", c.Args().First())
+	}
+	testEmptyGroup(groupPath)
+	return net.GroupFromPath(groupPath, hash), nil
+}
+
 // initDKG indicates to the daemon to start the DKG protocol, as a leader or
 // not. The method waits until the DKG protocol finishes or an error occured.
 // If the DKG protocol finishes successfully, the beacon randomness loop starts.
-func initDKG(c *cli.Context, groupPath string) error {
-	// still trying to load it ourself now for the moment
-	// just to test if it's a valid thing or not
-	conf := contextToConfig(c)
-	client, err := net.NewControlClient(conf.ControlPort())
-	if err != nil {
-		fatal("drand: error creating control client: %s", err)
-	}
+func initDKG(c *cli.Context, group *control.GroupInfo) error {
+	client := controlClient(c)
 
 	if c.IsSet(userEntropyOnlyFlag.Name) && !c.IsSet(sourceFlag.Name) {
 		fmt.Print("drand: userEntropyOnly needs to be used with the source flag, which is not specified here. userEntropyOnly flag is ignored.")
@@ -71,7 +102,7 @@ func initDKG(c *cli.Context, groupPath string) error {
 	fmt.Print("drand: waiting the end of DKG protocol ... " +
 		"(you can CTRL-C to not quit waiting)")
 
-	_, err = client.InitDKG(groupPath, c.Bool(leaderFlag.Name), c.String(timeoutFlag.Name), entropyInfo)
+	_, err := client.InitDKG(group, c.Bool(leaderFlag.Name), c.String(timeoutFlag.Name), entropyInfo)
 	if err != nil {
 		fmt.Println("init dkg", err)
 		fatal("drand: initdkg %s", err)
@@ -88,26 +119,63 @@ func initDKG(c *cli.Context, groupPath string) error {
 // NOTE: If the contacted node is not present in the new list of nodes, the
 // waiting *can* be infinite in some cases. It's an issue that is low priority
 // though.
-func initReshare(c *cli.Context, newGroupPath string) error {
+func initReshare(c *cli.Context, newGroup *control.GroupInfo) error {
 	var isLeader = c.Bool(leaderFlag.Name)
-	var oldGroupPath string
+	var oldGroup *control.GroupInfo
 
 	if c.IsSet(oldGroupFlag.Name) {
-		oldGroupPath = c.String(oldGroupFlag.Name)
-	}
-	if oldGroupPath == "" {
+		oldGroup = net.GroupFromPath(c.String(oldGroupFlag.Name), "")
+	} else {
 		fmt.Print("drand: old group path not specified. Using daemon's own group if possible.")
 	}
 
 	client := controlClient(c)
 	fmt.Println("drand: initiating resharing protocol. Waiting to the end ...")
-	_, err := client.InitReshare(oldGroupPath, newGroupPath, isLeader, c.String(timeoutFlag.Name))
+	_, err := client.InitReshare(oldGroup, newGroup, isLeader, c.String(timeoutFlag.Name))
 	if err != nil {
 		fatal("drand: error resharing: %s", err)
 	}
 	return nil
 }
 
+// reshareDryRun asks the daemon to check the resharing to the new group and
+// prints the changes and problems found, without running the resharing.
+func reshareDryRun(c *cli.Context, newGroup *control.GroupInfo) error {
+	var oldGroup *control.GroupInfo
+	if c.IsSet(oldGroupFlag.Name) {
+		oldGroup = net.GroupFromPath(c.String(oldGroupFlag.Name), "")
+	}
+	client := controlClient(c)
+	resp, err := client.ReshareDryRun(oldGroup, newGroup, c.String(timeoutFlag.Name))
+	if err != nil {
+		fatal("drand: error checking resharing: %s", err)
+	}
+	printNodes := func(title string, nodes []*control.Node) {
+		fmt.Printf("%s (%d):\n", title, len(nodes))
+		for _, n := range nodes {
+			fmt.Printf("\t- %s (tls: %v) %s\n", n.Address, n.TLS, n.Key)
+		}
+	}
+	printNodes("staying nodes", resp.Staying)
+	printNodes("leaving nodes", resp.Leaving)
+	printNodes("joining nodes", resp.Joining)
+	fmt.Printf("threshold: %d -> %d\n", resp.OldThreshold, resp.NewThreshold)
+	if resp.TransitionTime != 0 {
+		fmt.Printf("transition: round %d at %s\n", resp.TransitionRound, time.Unix(resp.TransitionTime, 0))
+	}
+	for _, w := range resp.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+	for _, p := range resp.Problems {
+		fmt.Printf("problem: %s\n", p)
+	}
+	if len(resp.Problems) > 0 {
+		fatal("drand: resharing can not happen safely, %d problem(s) found", len(resp.Problems))
+	}
+	fmt.Println("drand: no problem found for the resharing")
+	return nil
+}
+
 func getShare(c *cli.Context) error {
 	client := controlClient(c)
 	resp, err := client.Share()
@@ -127,6 +195,33 @@ func pingpongCmd(c *cli.Context) error {
 	return nil
 }
 
+func updateAddressCmd(c *cli.Context) error {
+	in := &control.UpdateAddressRequest{
+		Address:   c.String(addressFlag.Name),
+		Addresses: c.StringSlice(addressesFlag.Name),
+	}
+	if c.IsSet(operatorFlag.Name) || c.IsSet(contactFlag.Name) || c.IsSet(regionFlag.Name) {
+		in.Metadata = &control.NodeMetadata{
+			Operator: c.String(operatorFlag.Name),
+			Contact:  c.String(contactFlag.Name),
+			Region:   c.String(regionFlag.Name),
+		}
+	}
+	if in.Address == "" && len(in.Addresses) == 0 && in.Metadata == nil {
+		fatal("drand: nothing to update")
+	}
+	client := controlClient(c)
+	resp, err := client.UpdateAddress(in)
+	if err != nil {
+		fatal("drand: can't update address: %s", err)
+	}
+	for _, addr := range resp.GetFailed() {
+		fmt.Printf("drand: announcement to %s failed\n", addr)
+	}
+	fmt.Println("drand: address updated")
+	return nil
+}
+
 func showGroupCmd(c *cli.Context) error {
 	client := controlClient(c)
 	r, err := client.GroupFile()
@@ -189,6 +284,16 @@ func showShareCmd(c *cli.Context) error {
 	return nil
 }
 
+func showPeersCmd(c *cli.Context) error {
+	client := controlClient(c)
+	resp, err := client.PeerStatus()
+	if err != nil {
+		fatal("drand: could not request the status of the peers: %s", err)
+	}
+	printJSON(resp)
+	return nil
+}
+
 func controlPort(c *cli.Context) string {
 	port := c.String(controlFlag.Name)
 	if port == "" {
@@ -198,9 +303,26 @@ func controlPort(c *cli.Context) string {
 	return port
 }
 
+// controlClient returns a client of the control service of the daemon: over
+// the Unix socket in the config folder by default, or over the control port if
+// one is given, presenting the control token of the config folder if any.
 func controlClient(c *cli.Context) *net.ControlClient {
-	port := controlPort(c)
-	client, err := net.NewControlClient(port)
+	folder := c.String(folderFlag.Name)
+	if folder == "" {
+		folder = core.DefaultConfigFolder()
+	}
+	var client *net.ControlClient
+	var err error
+	if !c.IsSet(controlFlag.Name) {
+		client, err = net.NewUnixControlClient(filepath.Join(folder, core.DefaultControlSocket))
+	} else if tokenPath := filepath.Join(folder, core.ControlTokenFile); fileExists(tokenPath) {
+		var token string
+		if token, err = core.ReadControlToken(tokenPath); err == nil {
+			client, err = net.NewControlClientWithToken(controlPort(c), token)
+		}
+	} else {
+		client, err = net.NewControlClient(controlPort(c))
+	}
 	if err != nil {
 		fatal("drand: can't instantiate control client: %s", err)
 	}
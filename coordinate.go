@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drand/drand/coordinator"
+	"github.com/drand/drand/core"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	"github.com/urfave/cli/v2"
+)
+
+// coordinatorPollPeriod is the period at which a participant polls the
+// coordinator while waiting for the other participants.
+const coordinatorPollPeriod = 2 * time.Second
+
+// coordinatorDaemonPollPeriod is the period at which a participant checks
+// whether its daemon is ready to run the DKG, before telling the coordinator.
+const coordinatorDaemonPollPeriod = 100 * time.Millisecond
+
+// coordinateCmd runs the coordinator service until it is killed.
+func coordinateCmd(c *cli.Context) error {
+	if !c.IsSet(listenFlag.Name) {
+		fatal("drand: coordinator needs a listen address")
+	}
+	if !c.IsSet(expectedFlag.Name) {
+		fatal("drand: coordinator needs the number of expected participants")
+	}
+	conf := &coordinator.Config{
+		Secret:    c.String(secretFlag.Name),
+		Expected:  c.Int(expectedFlag.Name),
+		Threshold: c.Int(thresholdFlag.Name),
+		Period:    core.DefaultBeaconPeriod,
+		Genesis:   c.Int64(genesisFlag.Name),
+	}
+	if c.IsSet(periodFlag.Name) {
+		period, err := time.ParseDuration(c.String(periodFlag.Name))
+		if err != nil {
+			fatal("drand: invalid period time given %s", err)
+		}
+		conf.Period = period
+	}
+	if c.IsSet(startInFlag.Name) {
+		startIn, err := time.ParseDuration(c.String(startInFlag.Name))
+		if err != nil {
+			fatal("drand: invalid start-in duration given %s", err)
+		}
+		conf.StartIn = startIn
+	}
+	if conf.Genesis != 0 && conf.Genesis <= time.Now().Unix() {
+		fatal("drand: genesis time in the past")
+	}
+	logger := log.NewLogger(log.LogInfo)
+	co, err := coordinator.NewCoordinator(conf, logger)
+	if err != nil {
+		fatal("drand: %s", err)
+	}
+	addr := c.String(listenFlag.Name)
+	fmt.Printf("drand: coordinator listening on %s, waiting for %d participants\n", addr, conf.Expected)
+	if c.Bool(insecureFlag.Name) {
+		if c.IsSet(tlsCertFlag.Name) || c.IsSet(tlsKeyFlag.Name) {
+			fatal("drand: option 'tls-disable' used with 'tls-cert' or 'tls-key': combination is not valid")
+		}
+		return http.ListenAndServe(addr, co)
+	}
+	if !c.IsSet(tlsCertFlag.Name) || !c.IsSet(tlsKeyFlag.Name) {
+		fatal("drand: coordinator needs 'tls-cert' and 'tls-key' unless 'tls-disable' is used")
+	}
+	return http.ListenAndServeTLS(addr, c.String(tlsCertFlag.Name), c.String(tlsKeyFlag.Name), co)
+}
+
+// connectCmd registers the identity of the local node to the coordinator,
+// waits for the group to be complete and then runs the DKG with it. The hash of
+// the group file must be given by the operator with the group hash flag, after
+// checking it with the operator of the coordinator: without it, the command
+// only prints the group and its hash.
+func connectCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	fs := keyStore(c, conf)
+	id, err := fs.LoadIdentity()
+	if err != nil {
+		fatal("drand: can't load identity: %s", err)
+	}
+	client := coordinator.NewClient(c.String(connectFlag.Name), c.String(secretFlag.Name))
+	if err := client.Register(id); err != nil {
+		fatal("drand: can't register to coordinator: %s", err)
+	}
+	fmt.Println("drand: registered to coordinator, waiting for the other participants ...")
+	group, err := client.WaitGroup(coordinatorPollPeriod)
+	if err != nil {
+		fatal("drand: can't fetch group from coordinator: %s", err)
+	}
+	status, err := client.Status()
+	if err != nil {
+		fatal("drand: can't fetch coordinator status: %s", err)
+	}
+	if !c.IsSet(groupHashFlag.Name) {
+		fmt.Printf("drand: group complete:\n%s\n", group)
+		fmt.Printf("drand: group file hash %s announced by the coordinator. Check it "+
+			"with the operator of the coordinator and run the command again with "+
+			"--%s to run the DKG.\n", status.GroupHash, groupHashFlag.Name)
+		return nil
+	}
+	// the daemon checks the group file it fetches against the hash of the
+	// operator, this is only to fail early
+	hash := c.String(groupHashFlag.Name)
+	if !strings.EqualFold(hash, status.GroupHash) {
+		fatal("drand: coordinator announces group file hash %s, expected %s", status.GroupHash, hash)
+	}
+	groupInfo := net.GroupFromURL(client.GroupURL(), hash)
+	if c.Bool(leaderFlag.Name) {
+		if err := client.Ready(id); err != nil {
+			fatal("drand: can't signal readiness to coordinator: %s", err)
+		}
+		fmt.Println("drand: waiting for all participants to be ready ...")
+		if err := client.WaitReady(coordinatorPollPeriod); err != nil {
+			fatal("drand: error waiting for participants: %s", err)
+		}
+		return initDKG(c, groupInfo)
+	}
+	// the participant is only ready once its daemon waits for the packets of
+	// the leader
+	done := make(chan error, 1)
+	go func() { done <- initDKG(c, groupInfo) }()
+	ctrl := controlClient(c)
+	for {
+		running, err := ctrl.DKGRunning()
+		if err != nil {
+			fatal("drand: can't reach daemon: %s", err)
+		}
+		if running {
+			break
+		}
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(coordinatorDaemonPollPeriod):
+		}
+	}
+	if err := client.Ready(id); err != nil {
+		fatal("drand: can't signal readiness to coordinator: %s", err)
+	}
+	return <-done
+}
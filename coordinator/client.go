@@ -0,0 +1,146 @@
+package coordinator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/key"
+)
+
+// ErrNotReady is returned when the coordinator has not built the group yet
+var ErrNotReady = errors.New("coordinator: group not complete")
+
+// Client talks to a coordinator on behalf of a participant.
+type Client struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewClient returns a client for the coordinator reachable at the given base
+// url, e.g. "https://coordinator.example.com:8080".
+func NewClient(url, secret string) *Client {
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GroupURL returns the url serving the group built by the coordinator.
+func (c *Client) GroupURL() string {
+	return c.url + GroupPath
+}
+
+// Register sends the identity of the participant to the coordinator.
+func (c *Client) Register(id *key.Identity) error {
+	req := &RegisterRequest{
+		Secret:   c.secret,
+		Identity: id.TOML().(*key.PublicTOML),
+	}
+	return c.post(RegisterPath, req)
+}
+
+// Ready signals to the coordinator that the participant is ready to run the
+// DKG.
+func (c *Client) Ready(id *key.Identity) error {
+	req := &ReadyRequest{
+		Secret: c.secret,
+		Key:    key.PointToString(id.Key),
+	}
+	return c.post(ReadyPath, req)
+}
+
+// Group returns the group built by the coordinator or ErrNotReady if not all
+// participants are registered yet.
+func (c *Client) Group() (*key.Group, error) {
+	resp, err := c.client.Get(c.url + GroupPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return nil, ErrNotReady
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	gt := &key.GroupTOML{}
+	if _, err := toml.DecodeReader(resp.Body, gt); err != nil {
+		return nil, fmt.Errorf("coordinator: decoding group: %s", err)
+	}
+	g := &key.Group{}
+	if err := g.FromTOML(gt); err != nil {
+		return nil, fmt.Errorf("coordinator: decoding group: %s", err)
+	}
+	return g, nil
+}
+
+// Status returns the current status of the coordinator.
+func (c *Client) Status() (*Status, error) {
+	resp, err := c.client.Get(c.url + StatusPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	status := new(Status)
+	return status, json.NewDecoder(resp.Body).Decode(status)
+}
+
+// WaitGroup polls the coordinator every period until the group is built.
+func (c *Client) WaitGroup(period time.Duration) (*key.Group, error) {
+	for {
+		g, err := c.Group()
+		if err != ErrNotReady {
+			return g, err
+		}
+		time.Sleep(period)
+	}
+}
+
+// WaitReady polls the coordinator every period until all participants are
+// ready to run the DKG.
+func (c *Client) WaitReady(period time.Duration) error {
+	for {
+		s, err := c.Status()
+		if err != nil {
+			return err
+		}
+		if s.GroupHash != "" && s.Ready == s.Expected {
+			return nil
+		}
+		time.Sleep(period)
+	}
+}
+
+func (c *Client) post(path string, v interface{}) error {
+	buff, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Post(c.url+path, "application/json", bytes.NewReader(buff))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp)
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("coordinator: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,288 @@
+// Package coordinator implements a small HTTP service that assembles the group
+// file of a new drand network. Participants register their identity with a
+// shared secret and, once the expected number of participants is reached, the
+// coordinator builds the group that every participant then fetches to run the
+// DKG.
+package coordinator
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	vss "github.com/drand/kyber/share/vss/pedersen"
+	clock "github.com/jonboulle/clockwork"
+)
+
+const (
+	// RegisterPath is the path on which participants register their identity
+	RegisterPath = "/register"
+	// ReadyPath is the path on which participants signal they are ready to run
+	// the DKG
+	ReadyPath = "/ready"
+	// GroupPath is the path serving the TOML encoded group once it is built
+	GroupPath = "/group"
+	// StatusPath is the path serving the current status of the coordinator
+	StatusPath = "/status"
+)
+
+// maxRequestSize is the maximum size of a request sent to the coordinator
+const maxRequestSize = 64 << 10
+
+// Config holds the parameters of the group the coordinator builds.
+type Config struct {
+	// Secret shared with all participants, required to register
+	Secret string
+	// Expected is the number of participants of the group
+	Expected int
+	// Threshold of the group - the default threshold if zero
+	Threshold int
+	// Period of the group
+	Period time.Duration
+	// Genesis is the genesis time of the group. If zero, the genesis time is
+	// set to StartIn after the group is built.
+	Genesis int64
+	// StartIn is the duration between the time the group is built and the
+	// genesis time when Genesis is zero.
+	StartIn time.Duration
+	// Clock used to compute the genesis time - real clock if nil
+	Clock clock.Clock
+}
+
+// RegisterRequest is sent by a participant to register its identity.
+type RegisterRequest struct {
+	Secret   string
+	Identity *key.PublicTOML
+}
+
+// ReadyRequest is sent by a participant that is ready to run the DKG.
+type ReadyRequest struct {
+	Secret string
+	// Key is the hex encoded public key of the participant
+	Key string
+}
+
+// Status is the current state of the coordinator
+type Status struct {
+	Expected   int
+	Registered int
+	Ready      int
+	// GroupHash is the hash of the group file served by the coordinator, as
+	// computed by key.FileHash, empty until the group is built
+	GroupHash string
+}
+
+// Coordinator collects the identities of the participants and builds the
+// group once all expected participants are registered. It implements
+// http.Handler.
+type Coordinator struct {
+	sync.Mutex
+	c     *Config
+	ids   []*key.Identity
+	ready map[string]bool
+	group *key.Group
+	// TOML encoded group served to the participants
+	file []byte
+	hash string
+	mux  *http.ServeMux
+	l    log.Logger
+}
+
+// NewCoordinator returns a coordinator building a group from the given config.
+// The config is copied so the caller's one is left untouched.
+func NewCoordinator(conf *Config, l log.Logger) (*Coordinator, error) {
+	c := *conf
+	if c.Secret == "" {
+		return nil, errors.New("coordinator: empty secret")
+	}
+	// same minimum as the daemon accepts for a group
+	if c.Expected < 4 {
+		return nil, errors.New("coordinator: at least 4 participants are required")
+	}
+	if c.Threshold == 0 {
+		c.Threshold = key.DefaultThreshold(c.Expected)
+	}
+	if c.Threshold < vss.MinimumT(c.Expected) || c.Threshold > c.Expected {
+		return nil, fmt.Errorf("coordinator: invalid threshold %d for %d participants", c.Threshold, c.Expected)
+	}
+	if c.Period <= 0 {
+		return nil, errors.New("coordinator: invalid period")
+	}
+	if c.Genesis == 0 && c.StartIn <= 0 {
+		return nil, errors.New("coordinator: genesis time or start-in duration required")
+	}
+	if c.Clock == nil {
+		c.Clock = clock.NewRealClock()
+	}
+	co := &Coordinator{
+		c:     &c,
+		ready: make(map[string]bool),
+		mux:   http.NewServeMux(),
+		l:     l.With("module", "coordinator"),
+	}
+	co.mux.HandleFunc(RegisterPath, co.register)
+	co.mux.HandleFunc(ReadyPath, co.setReady)
+	co.mux.HandleFunc(GroupPath, co.serveGroup)
+	co.mux.HandleFunc(StatusPath, co.serveStatus)
+	return co, nil
+}
+
+// ServeHTTP implements the http.Handler interface
+func (co *Coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	co.mux.ServeHTTP(w, r)
+}
+
+// Group returns the group built by the coordinator, nil if not all
+// participants are registered yet.
+func (co *Coordinator) Group() *key.Group {
+	co.Lock()
+	defer co.Unlock()
+	return co.group
+}
+
+func (co *Coordinator) register(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	req := new(RegisterRequest)
+	if !co.decode(w, r, req, &req.Secret) {
+		return
+	}
+	id := new(key.Identity)
+	if req.Identity == nil {
+		http.Error(w, "missing identity", http.StatusBadRequest)
+		return
+	}
+	if err := id.FromTOML(req.Identity); err != nil {
+		http.Error(w, "invalid identity", http.StatusBadRequest)
+		return
+	}
+	if err := id.ValidSignature(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	co.Lock()
+	defer co.Unlock()
+	for i, existing := range co.ids {
+		sameKey := existing.Key.Equal(id.Key)
+		if !sameKey && existing.Address() != id.Address() {
+			continue
+		}
+		if co.group != nil && sameKey && existing.Address() == id.Address() {
+			// a participant registering again to fetch the group
+			return
+		}
+		if co.group != nil || !sameKey {
+			http.Error(w, "identity already registered", http.StatusConflict)
+			return
+		}
+		// the same participant can update its address before the group is
+		// built
+		co.ids[i] = id
+		co.l.Info("updated", id.Address())
+		return
+	}
+	if co.group != nil {
+		http.Error(w, "group already complete", http.StatusConflict)
+		return
+	}
+	co.ids = append(co.ids, id)
+	co.l.Info("registered", id.Address(), "total", len(co.ids), "expected", co.c.Expected)
+	if len(co.ids) == co.c.Expected {
+		co.buildGroup()
+	}
+}
+
+// buildGroup must be called with the lock held
+func (co *Coordinator) buildGroup() {
+	genesis := co.c.Genesis
+	if genesis == 0 {
+		genesis = co.c.Clock.Now().Add(co.c.StartIn).Unix()
+	}
+	group := key.NewGroup(co.ids, co.c.Threshold, genesis)
+	group.Period = co.c.Period
+	var buff bytes.Buffer
+	if err := toml.NewEncoder(&buff).Encode(group.TOML()); err != nil {
+		co.l.Error("group_encoding", err)
+		return
+	}
+	hash := key.FileHash(buff.Bytes())
+	co.group = group
+	co.file = buff.Bytes()
+	co.hash = hash
+	co.l.Info("group", "built", "hash", hash, "genesis", genesis)
+}
+
+func (co *Coordinator) setReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	req := new(ReadyRequest)
+	if !co.decode(w, r, req, &req.Secret) {
+		return
+	}
+	co.Lock()
+	defer co.Unlock()
+	if co.group == nil {
+		http.Error(w, "group not complete", http.StatusConflict)
+		return
+	}
+	for _, id := range co.group.Nodes {
+		if key.PointToString(id.Key) == req.Key {
+			co.ready[req.Key] = true
+			return
+		}
+	}
+	http.Error(w, "unknown participant", http.StatusNotFound)
+}
+
+func (co *Coordinator) serveGroup(w http.ResponseWriter, r *http.Request) {
+	co.Lock()
+	file := co.file
+	co.Unlock()
+	if file == nil {
+		http.Error(w, "group not complete", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/toml")
+	w.Write(file)
+}
+
+func (co *Coordinator) serveStatus(w http.ResponseWriter, r *http.Request) {
+	co.Lock()
+	status := &Status{
+		Expected:   co.c.Expected,
+		Registered: len(co.ids),
+		Ready:      len(co.ready),
+		GroupHash:  co.hash,
+	}
+	co.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// decode reads the JSON request into v and checks the secret it contains. It
+// writes the error to the client and returns false if the request is invalid.
+func (co *Coordinator) decode(w http.ResponseWriter, r *http.Request, v interface{}, secret *string) bool {
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(v); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(*secret), []byte(co.c.Secret)) != 1 {
+		co.l.Info("invalid_secret", r.RemoteAddr)
+		http.Error(w, "invalid secret", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
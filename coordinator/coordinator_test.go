@@ -0,0 +1,101 @@
+package coordinator
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/test"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinator(t *testing.T) {
+	n := 5
+	thr := 3
+	clk := clock.NewFakeClock()
+	conf := &Config{
+		Secret:    "secret",
+		Expected:  n,
+		Threshold: thr,
+		Period:    30 * time.Second,
+		StartIn:   time.Minute,
+		Clock:     clk,
+	}
+	co, err := NewCoordinator(conf, log.DefaultLogger)
+	require.NoError(t, err)
+	srv := httptest.NewServer(co)
+	defer srv.Close()
+
+	ids := test.ListFromPrivates(test.GenerateIDs(n))
+	client := NewClient(srv.URL, "secret")
+
+	// wrong secret is refused
+	require.Error(t, NewClient(srv.URL, "wrong").Register(ids[0]))
+	// identity without proof of possession is refused
+	unsigned := *ids[0]
+	unsigned.Signature = nil
+	require.Error(t, client.Register(&unsigned))
+
+	for _, id := range ids[:n-1] {
+		require.NoError(t, client.Register(id))
+	}
+	// registering twice the same identity is fine before the group is built
+	require.NoError(t, client.Register(ids[0]))
+	_, err = client.Group()
+	require.Equal(t, ErrNotReady, err)
+	require.Error(t, client.Ready(ids[0]))
+
+	require.NoError(t, client.Register(ids[n-1]))
+	group, err := client.Group()
+	require.NoError(t, err)
+	require.Equal(t, n, group.Len())
+	require.Equal(t, thr, group.Threshold)
+	require.Equal(t, conf.Period, group.Period)
+	require.Equal(t, clk.Now().Add(time.Minute).Unix(), group.GenesisTime)
+	for _, id := range ids {
+		require.True(t, group.Contains(id))
+	}
+	var buff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&buff).Encode(group.TOML()))
+	hash := key.FileHash(buff.Bytes())
+
+	// a participant can register again to fetch the group
+	require.NoError(t, client.Register(ids[0]))
+	// no new participant once the group is built
+	extra := test.ListFromPrivates(test.GenerateIDs(1))
+	require.Error(t, client.Register(extra[0]))
+	require.Error(t, client.Ready(extra[0]))
+
+	for _, id := range ids[:n-1] {
+		require.NoError(t, client.Ready(id))
+	}
+	status, err := client.Status()
+	require.NoError(t, err)
+	require.Equal(t, &Status{Expected: n, Registered: n, Ready: n - 1, GroupHash: hash}, status)
+
+	require.NoError(t, client.Ready(ids[n-1]))
+	require.NoError(t, client.WaitReady(10*time.Millisecond))
+}
+
+func TestCoordinatorConfig(t *testing.T) {
+	_, err := NewCoordinator(&Config{Expected: 5, Period: time.Second, StartIn: time.Second}, log.DefaultLogger)
+	require.Error(t, err)
+	_, err = NewCoordinator(&Config{Secret: "s", Expected: 5, Threshold: 2, Period: time.Second, StartIn: time.Second}, log.DefaultLogger)
+	require.Error(t, err)
+	_, err = NewCoordinator(&Config{Secret: "s", Expected: 5, Period: time.Second}, log.DefaultLogger)
+	require.Error(t, err)
+	_, err = NewCoordinator(&Config{Secret: "s", Expected: 3, Period: time.Second, Genesis: 10}, log.DefaultLogger)
+	require.Error(t, err)
+	conf := &Config{Secret: "s", Expected: 5, Period: time.Second, Genesis: 10}
+	co, err := NewCoordinator(conf, log.DefaultLogger)
+	require.NoError(t, err)
+	require.Equal(t, key.DefaultThreshold(5), co.c.Threshold)
+	// the config of the caller is left untouched
+	require.Equal(t, 0, conf.Threshold)
+	require.Nil(t, conf.Clock)
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/protobuf/drand"
+)
+
+// UpdateAddress changes the addresses and metadata of the node, signs its new
+// identity and announces it to the other nodes of the group.
+func (d *Drand) UpdateAddress(ctx context.Context, in *drand.UpdateAddressRequest) (*drand.UpdateAddressResponse, error) {
+	d.state.Lock()
+	pub := *d.identity
+	if in.GetAddress() != "" {
+		pub.Addr = in.GetAddress()
+	}
+	if len(in.GetAddresses()) > 0 {
+		pub.Addresses = in.GetAddresses()
+	}
+	if in.GetMetadata() != nil {
+		pub.Metadata = metadataFromProto(in.GetMetadata())
+	}
+	if d.opts.mutualTLS {
+		// the update carries the key of the current certificate
+		hash, err := d.tlsKeyHash()
+		if err != nil {
+			d.state.Unlock()
+			return nil, fmt.Errorf("drand: can't read TLS certificate: %s", err)
+		}
+		pub.TLSKeyHash = hash
+	}
+	if err := pub.SignWith(d.longterm); err != nil {
+		d.state.Unlock()
+		return nil, fmt.Errorf("drand: can't sign identity: %s", err)
+	}
+	update, err := key.NewAddressUpdate(&pub, d.opts.clock.Now().Unix(), d.longterm)
+	if err != nil {
+		d.state.Unlock()
+		return nil, fmt.Errorf("drand: can't sign address update: %s", err)
+	}
+	if err := d.store.SaveIdentity(&pub); err != nil {
+		d.state.Unlock()
+		return nil, fmt.Errorf("drand: can't save identity: %s", err)
+	}
+	d.identity = &pub
+	var nodes []*key.Identity
+	if d.group != nil {
+		if err := d.applyUpdate(update); err != nil {
+			d.state.Unlock()
+			return nil, err
+		}
+		nodes = d.group.Nodes
+	}
+	d.state.Unlock()
+
+	d.log.Info("address_update", pub.Address(), "addresses", pub.Addresses)
+	announcement := toAnnouncement(update)
+	resp := new(drand.UpdateAddressResponse)
+	for _, id := range nodes {
+		if id.Key.Equal(pub.Key) {
+			continue
+		}
+		if _, err := d.gateway.ProtocolClient.AnnounceAddress(id, announcement); err != nil {
+			d.log.Error("address_update", "announce", "to", id.Address(), "err", err)
+			resp.Failed = append(resp.Failed, id.Address())
+		}
+	}
+	return resp, nil
+}
+
+// AnnounceAddress receives the new addresses and metadata of a node of the
+// group and updates the group accordingly if the announcement is valid.
+func (d *Drand) AnnounceAddress(ctx context.Context, in *drand.AddressAnnouncement) (*drand.Empty, error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.group == nil {
+		return nil, errors.New("drand: no group setup yet")
+	}
+	update, err := fromAnnouncement(in, d.group.Suite())
+	if err != nil {
+		return nil, err
+	}
+	if err := update.Verify(); err != nil {
+		return nil, err
+	}
+	now := d.opts.clock.Now()
+	age := now.Sub(time.Unix(update.Timestamp, 0))
+	if age > MaxAnnouncementAge || age < -MaxAnnouncementAge {
+		return nil, fmt.Errorf("drand: address announcement too far from current time (%s)", age)
+	}
+	if err := d.applyUpdate(update); err != nil {
+		return nil, err
+	}
+	d.log.Info("address_announce", update.Identity.Address(), "addresses", update.Identity.Addresses)
+	return new(drand.Empty), nil
+}
+
+// applyUpdate saves a copy of the group with the update applied and swaps it
+// with the current group, so the readers of the current group never see a
+// partially updated identity. The timestamp of the update is saved with the
+// group to reject replays, even after a restart. It must be called with the
+// state lock held.
+func (d *Drand) applyUpdate(update *key.AddressUpdate) error {
+	group, err := update.Apply(d.group)
+	if err != nil {
+		return err
+	}
+	if err := d.store.SaveGroup(group); err != nil {
+		return fmt.Errorf("drand: can't save group: %s", err)
+	}
+	d.group = group
+	if d.beacon != nil {
+		d.beacon.UpdateNodes(group.Nodes)
+	}
+	return nil
+}
+
+func toAnnouncement(u *key.AddressUpdate) *drand.AddressAnnouncement {
+	return &drand.AddressAnnouncement{
+		Key:               key.PointToString(u.Identity.Key),
+		Address:           u.Identity.Address(),
+		Addresses:         u.Identity.Addresses,
+		TLS:               u.Identity.IsTLS(),
+		Metadata:          metadataToProto(u.Identity.Metadata),
+		IdentitySignature: u.Identity.Signature,
+		Timestamp:         u.Timestamp,
+		Signature:         u.Signature,
+		TlsKeyHash:        u.Identity.TLSKeyHash,
+	}
+}
+
+func fromAnnouncement(a *drand.AddressAnnouncement, s *key.Suite) (*key.AddressUpdate, error) {
+	k, err := key.StringToPoint(s.KeyGroup, a.GetKey())
+	if err != nil {
+		return nil, fmt.Errorf("drand: invalid key in announcement: %s", err)
+	}
+	id := &key.Identity{
+		Key:       k,
+		Addr:      a.GetAddress(),
+		TLS:       a.GetTLS(),
+		Signature: a.GetIdentitySignature(),
+		Addresses: a.GetAddresses(),
+		Metadata:  metadataFromProto(a.GetMetadata()),
+	}
+	if len(a.GetTlsKeyHash()) > 0 {
+		id.TLSKeyHash = a.GetTlsKeyHash()
+	}
+	return &key.AddressUpdate{
+		Identity:  id,
+		Timestamp: a.GetTimestamp(),
+		Signature: a.GetSignature(),
+	}, nil
+}
+
+func metadataToProto(m *key.Metadata) *drand.NodeMetadata {
+	if m == nil {
+		return nil
+	}
+	return &drand.NodeMetadata{
+		Operator: m.Operator,
+		Contact:  m.Contact,
+		Region:   m.Region,
+	}
+}
+
+func metadataFromProto(m *drand.NodeMetadata) *key.Metadata {
+	if m == nil {
+		return nil
+	}
+	return &key.Metadata{
+		Operator: m.GetOperator(),
+		Contact:  m.GetContact(),
+		Region:   m.GetRegion(),
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrandUpdateAddress(t *testing.T) {
+	n := 3
+	drands, group, dir, _ := BatchNewDrand(n, true)
+	defer CloseAllDrands(drands)
+	defer os.RemoveAll(dir)
+	for _, d := range drands {
+		g := *group
+		d.group = &g
+		require.NoError(t, d.store.SaveGroup(d.group))
+	}
+
+	moved := drands[0]
+	resp, err := moved.UpdateAddress(context.Background(), &drand.UpdateAddressRequest{
+		Addresses: []string{"10.0.0.1:4444"},
+		Metadata:  &drand.NodeMetadata{Operator: "op", Region: "eu"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetFailed())
+	require.NoError(t, moved.identity.ValidSignature())
+	// the new identity is saved without touching the private key
+	savedID, err := moved.store.LoadIdentity()
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1:4444"}, savedID.Addresses)
+	require.NoError(t, savedID.ValidSignature())
+
+	for _, d := range drands {
+		idx, found := d.group.Index(moved.identity)
+		require.True(t, found)
+		id := d.group.Nodes[idx]
+		require.Equal(t, []string{"10.0.0.1:4444"}, id.Addresses)
+		require.Equal(t, "eu", id.Metadata.Region)
+		require.NoError(t, id.ValidSignature())
+		saved, err := d.store.LoadGroup()
+		require.NoError(t, err)
+		require.Equal(t, "op", saved.Nodes[idx].Metadata.Operator)
+	}
+
+	// replaying an announcement is rejected
+	now := moved.opts.clock.Now().Unix()
+	update, err := key.NewAddressUpdate(moved.identity, now-1, moved.longterm)
+	require.NoError(t, err)
+	_, err = drands[1].AnnounceAddress(context.Background(), toAnnouncement(update))
+	require.Error(t, err)
+	// even after a restart, since the timestamp is saved with the group
+	saved, err := drands[1].store.LoadGroup()
+	require.NoError(t, err)
+	drands[1].group = saved
+	_, err = drands[1].AnnounceAddress(context.Background(), toAnnouncement(update))
+	require.Error(t, err)
+	// as well as an old one
+	update, err = key.NewAddressUpdate(moved.identity, now-2*int64(MaxAnnouncementAge.Seconds()), moved.longterm)
+	require.NoError(t, err)
+	_, err = drands[2].AnnounceAddress(context.Background(), toAnnouncement(update))
+	require.Error(t, err)
+}
@@ -1,10 +1,18 @@
 package core
 
 import (
-	"github.com/drand/drand/beacon"
+	"context"
+	"errors"
+	"fmt"
 	"sync"
+
+	"github.com/drand/drand/beacon"
 )
 
+// streamBuffer is the number of new beacons kept for a stream while the
+// stored beacons are sent to it
+const streamBuffer = 100
+
 type callbackManager struct {
 	sync.Mutex
 	callbacks map[string]func(*beacon.Beacon)
@@ -67,3 +75,57 @@ func (s *callbackManager) runWorker() {
 		}
 	}
 }
+
+// streamBeacons sends the beacons of the store from the given round, if not
+// 0, then the new beacons given to the callback registered under the id until
+// sending fails or the context is done. The new beacons are kept while the
+// stored ones are sent, so the stream has no hole and no duplicate; a stream
+// falling streamBuffer beacons behind is ended.
+func (s *callbackManager) streamBeacons(ctx context.Context, id string, store beacon.Store, from uint64, send func(*beacon.Beacon) error) error {
+	newBeacons := make(chan *beacon.Beacon, streamBuffer)
+	overflow := make(chan bool, 1)
+	// the streams of a client share its address
+	id = fmt.Sprintf("%s/%p", id, newBeacons)
+	s.AddCallback(id, func(b *beacon.Beacon) {
+		select {
+		case newBeacons <- b:
+		default:
+			select {
+			case overflow <- true:
+			default:
+			}
+		}
+	})
+	defer s.DelCallback(id)
+	var last uint64
+	if from != 0 {
+		var err error
+		store.Cursor(func(c beacon.Cursor) {
+			for b := c.Seek(from); b != nil && ctx.Err() == nil; b = c.Next() {
+				if err = send(b); err != nil {
+					return
+				}
+				last = b.Round
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case b := <-newBeacons:
+			if b.Round <= last {
+				continue
+			}
+			if err := send(b); err != nil {
+				return err
+			}
+			last = b.Round
+		case <-overflow:
+			return errors.New("stream too slow to follow the beacons")
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
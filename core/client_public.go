@@ -2,9 +2,12 @@ package core
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 
 	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/ecies"
@@ -19,6 +22,10 @@ import (
 // XXX: This API should go away. Do not extend any further.
 type Client struct {
 	client net.PublicClient
+	// hash deriving the randomness of the chain, sha256 by default
+	randHash func() hash.Hash
+	// true if the beacons of the chain only sign their round
+	unchained bool
 }
 
 // NewGrpcClient returns a Client able to talk to drand instances using gRPC
@@ -48,6 +55,34 @@ func NewRESTClientFromCert(c *net.CertManager) *Client {
 	return &Client{client: net.NewRestClientFromCertManager(c)}
 }
 
+// SetRandomnessHash sets the hash function deriving the randomness from the
+// signatures, as given by the RandomnessHash of the group of the chain.
+func (c *Client) SetRandomnessHash(name string) error {
+	h, err := key.RandomnessHash(name)
+	if err != nil {
+		return err
+	}
+	c.randHash = h
+	return nil
+}
+
+// SetUnchained sets whether the beacons of the chain only sign their round,
+// as given by the Unchained flag of the group of the chain. The beacons are
+// verified against the chained message by default.
+func (c *Client) SetUnchained(unchained bool) {
+	c.unchained = unchained
+}
+
+// SetChain sets the mode of the chain and the hash function deriving its
+// randomness from the group returned by a node of the chain.
+func (c *Client) SetChain(g *drand.GroupResponse) error {
+	if err := c.SetRandomnessHash(g.GetRandomnessHash()); err != nil {
+		return err
+	}
+	c.SetUnchained(g.GetUnchained())
+	return nil
+}
+
 // LastPublic returns the last randomness beacon from the server associated. It
 // returns it if the randomness is valid. Secure indicates that the request
 // must be made over a TLS protected channel.
@@ -70,18 +105,52 @@ func (c *Client) Public(addr string, pub *key.DistPublic, secure bool, round int
 	return resp, c.verify(pub.Key(), resp)
 }
 
+// Watch streams the randomness of the server associated, starting from the
+// given round when it is not 0 and following the new beacons. The channel is
+// closed at the first beacon that is not valid, once the verification error is
+// sent on the error channel, which is closed when the stream ends.
+func (c *Client) Watch(ctx context.Context, addr string, pub *key.DistPublic, secure bool, round int) (chan *drand.PublicRandResponse, chan error, error) {
+	ch, err := c.client.PublicRandStream(ctx, &peerAddr{addr, secure}, &drand.PublicRandRequest{Round: uint64(round)})
+	if err != nil {
+		return nil, nil, err
+	}
+	outCh := make(chan *drand.PublicRandResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(outCh)
+		for resp := range ch {
+			if err := c.verify(pub.Key(), resp); err != nil {
+				errCh <- fmt.Errorf("invalid beacon for round %d: %s", resp.GetRound(), err)
+				return
+			}
+			select {
+			case outCh <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return outCh, errCh, nil
+}
+
 // Private retrieves a private random value from the server. It does that by
 // generating an ephemeral key pair, sends it encrypted to the remote server,
 // and decrypts the response, the randomness. Client will attempt a TLS
 // connection to the address in the identity if id.IsTLS() returns true
 func (c *Client) Private(id *key.Identity) ([]byte, error) {
-	ephScalar := key.KeyGroup.Scalar()
-	ephPoint := key.KeyGroup.Point().Mul(ephScalar, nil)
+	suite, err := key.SuiteOf(id.Key)
+	if err != nil {
+		return nil, err
+	}
+	g := suite.KeyGroup
+	ephScalar := g.Scalar()
+	ephPoint := g.Point().Mul(ephScalar, nil)
 	ephBuff, err := ephPoint.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	obj, err := ecies.Encrypt(key.KeyGroup, ecies.DefaultHash, id.Key, ephBuff)
+	obj, err := ecies.Encrypt(g, ecies.DefaultHash, id.Key, ephBuff)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +158,7 @@ func (c *Client) Private(id *key.Identity) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ecies.Decrypt(key.KeyGroup, ecies.DefaultHash, ephScalar, resp.GetResponse())
+	return ecies.Decrypt(g, ecies.DefaultHash, ephScalar, resp.GetResponse())
 }
 
 // DistKey returns the distributed key the node at this address is holding.
@@ -107,16 +176,24 @@ func (c *Client) verify(public kyber.Point, resp *drand.PublicRandResponse) erro
 	prevSig := resp.GetPreviousSignature()
 	prevRound := resp.GetPreviousRound()
 	round := resp.GetRound()
-	msg := beacon.Message(prevSig, prevRound, round)
 	rand := resp.GetRandomness()
 	if rand == nil {
 		return errors.New("drand: no randomness found")
 	}
-	ver := key.Scheme.VerifyRecovered(public, msg, resp.GetSignature())
-	if ver != nil {
-		return ver
+	// the distributed key tells the suite of the chain
+	suite, err := key.SuiteOf(public)
+	if err != nil {
+		return err
+	}
+	msg := beacon.MessageFor(c.unchained, prevSig, prevRound, round)
+	if err := suite.Scheme.VerifyRecovered(public, msg, resp.GetSignature()); err != nil {
+		return err
+	}
+	randHash := c.randHash
+	if randHash == nil {
+		randHash = sha256.New
 	}
-	expect := beacon.RandomnessFromSignature(resp.GetSignature())
+	expect := beacon.RandomnessWith(randHash, resp.GetSignature())
 	if !bytes.Equal(expect, rand) {
 		exp := hex.EncodeToString(expect)[10:14]
 		got := hex.EncodeToString(rand)[10:14]
@@ -1,9 +1,20 @@
 package core
 
 import (
+	"context"
+	gnet "net"
 	"os"
+	"path"
 	"testing"
 
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +23,7 @@ func TestClientPrivate(t *testing.T) {
 	defer CloseAllDrands(drands)
 	defer os.RemoveAll(dir)
 
-	pub := drands[0].priv.Public
+	pub := drands[0].identity
 	client := NewGrpcClientFromCert(drands[0].opts.certmanager)
 	buff, err := client.Private(pub)
 	require.Nil(t, err)
@@ -25,3 +36,107 @@ func TestClientPrivate(t *testing.T) {
 	require.NotNil(t, buff)
 	require.Len(t, buff, 32)
 }
+
+func TestClientPrivateExternalKey(t *testing.T) {
+	drands, _, dir, _ := BatchNewDrand(1, true)
+	defer CloseAllDrands(drands)
+	defer os.RemoveAll(dir)
+
+	socket := path.Join(dir, "key.sock")
+	l, err := gnet.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+	pair, err := drands[0].store.LoadKeyPair()
+	require.NoError(t, err)
+	go key.ServeExternalKey(l, pair)
+	drands[0].longterm = key.NewExternalKey(socket)
+
+	client := NewGrpcClient()
+	buff, err := client.Private(drands[0].identity)
+	require.Nil(t, err)
+	require.Len(t, buff, 32)
+}
+
+func TestClientPublicListener(t *testing.T) {
+	publicAddr := "127.0.0.1:" + test.FreePort()
+	drands, _, dir, _ := BatchNewDrand(1, true, WithPublicListenAddress(publicAddr))
+	defer CloseAllDrands(drands)
+	defer os.RemoveAll(dir)
+
+	// the public methods are only served on the public address
+	client := NewGrpcClient()
+	public := *drands[0].identity
+	public.Addr = publicAddr
+	buff, err := client.Private(&public)
+	require.NoError(t, err)
+	require.Len(t, buff, 32)
+	buff, err = NewRESTClient().Private(&public)
+	require.NoError(t, err)
+	require.Len(t, buff, 32)
+
+	_, err = client.Private(drands[0].identity)
+	require.Error(t, err)
+}
+
+func TestClientVerifyMode(t *testing.T) {
+	n, thr := 3, 2
+	priPoly := share.NewPriPoly(key.KeyGroup, thr, nil, random.New())
+	pubPoly := priPoly.Commit(key.KeyGroup.Point().Base())
+	sign := func(msg []byte) []byte {
+		var partials [][]byte
+		for _, s := range priPoly.Shares(n)[:thr] {
+			partial, err := key.Scheme.Sign(s, msg)
+			require.NoError(t, err)
+			partials = append(partials, partial)
+		}
+		sig, err := key.Scheme.Recover(pubPoly, msg, partials, thr, n)
+		require.NoError(t, err)
+		return sig
+	}
+	response := func(unchained bool) *drand.PublicRandResponse {
+		prevSig := []byte("previous signature")
+		sig := sign(beacon.MessageFor(unchained, prevSig, 9, 10))
+		return &drand.PublicRandResponse{
+			PreviousSignature: prevSig,
+			PreviousRound:     9,
+			Round:             10,
+			Signature:         sig,
+			Randomness:        beacon.RandomnessFromSignature(sig),
+		}
+	}
+	chained, unchained := response(false), response(true)
+
+	// a client only accepts the beacons of the mode of the chain
+	client := NewGrpcClient()
+	require.NoError(t, client.verify(pubPoly.Commit(), chained))
+	require.Error(t, client.verify(pubPoly.Commit(), unchained))
+	require.NoError(t, client.SetChain(&drand.GroupResponse{Unchained: true}))
+	require.NoError(t, client.verify(pubPoly.Commit(), unchained))
+	require.Error(t, client.verify(pubPoly.Commit(), chained))
+
+	// a stream ends at the first invalid beacon with the verification error
+	watcher := &Client{client: &streamClient{beacons: []*drand.PublicRandResponse{unchained, chained}}}
+	watcher.SetUnchained(true)
+	dist := &key.DistPublic{Coefficients: []kyber.Point{pubPoly.Commit()}}
+	ch, errCh, err := watcher.Watch(context.Background(), "127.0.0.1:0", dist, false, 0)
+	require.NoError(t, err)
+	require.Equal(t, unchained, <-ch)
+	_, ok := <-ch
+	require.False(t, ok)
+	require.Error(t, <-errCh)
+}
+
+// streamClient streams the given beacons
+type streamClient struct {
+	net.PublicClient
+	beacons []*drand.PublicRandResponse
+}
+
+func (s *streamClient) PublicRandStream(ctx context.Context, p net.Peer, in *drand.PublicRandRequest, opts ...net.CallOption) (chan *drand.PublicRandResponse, error) {
+	ch := make(chan *drand.PublicRandResponse, len(s.beacons))
+	for _, b := range s.beacons {
+		ch <- b
+	}
+	close(ch)
+	return ch, nil
+}
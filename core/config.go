@@ -23,19 +23,40 @@ type Config struct {
 	dbFolder     string
 	listenAddr   string
 	controlPort  string
-	grpcOpts     []grpc.DialOption
-	callOpts     []grpc.CallOption
-	dkgTimeout   time.Duration
-	boltOpts     *bolt.Options
-	beaconCbs    []func(*beacon.Beacon)
-	dkgCallback  func(*key.Share)
-	insecure     bool
-	certPath     string
-	keyPath      string
-	certmanager  *net.CertManager
-	logger       log.Logger
-	clock        clock.Clock
-	wait         time.Duration
+	// controlSocket is the path of the Unix socket of the control service,
+	// in the config folder by default
+	controlSocket string
+	controlToken  bool
+	grpcOpts      []grpc.DialOption
+	callOpts      []grpc.CallOption
+	dkgTimeout    time.Duration
+	dkgEcho       bool
+	boltOpts      *bolt.Options
+	beaconCbs     []func(*beacon.Beacon)
+	dkgCallback   func(*key.Share)
+	insecure      bool
+	certPath      string
+	keyPath       string
+	mutualTLS     bool
+	certmanager   *net.CertManager
+	logger        log.Logger
+	clock         clock.Clock
+	wait          time.Duration
+	keySocket     string
+
+	// public methods on their own listener when publicListenAddr is set
+	publicListenAddr string
+	publicCertPath   string
+	publicKeyPath    string
+	publicInsecure   bool
+	// certificates of the public listener obtained through ACME
+	acme *net.ACMEConfig
+	// limits of the public API, none when nil
+	rateLimit *net.RateLimitConfig
+	// origins allowed to call the REST API, any origin when nil
+	corsOrigins []string
+	// addresses of the nodes and relays the beacons are gossiped to
+	gossipPeers []string
 }
 
 // NewConfig returns the config to pass to drand with the default options set
@@ -89,12 +110,37 @@ func (d *Config) ListenAddress(defaultAddr string) string {
 	return defaultAddr
 }
 
+// PublicListenAddress returns the address the public methods listen on: the
+// one given by WithPublicListenAddress or the given listen address of the node.
+func (d *Config) PublicListenAddress(listenAddr string) string {
+	if d.publicListenAddr != "" {
+		return d.publicListenAddr
+	}
+	return listenAddr
+}
+
 // ControlPort returns the port used for control port communications
 // which can be the default one or the port setup thanks to WithControlPort
 func (d *Config) ControlPort() string {
 	return d.controlPort
 }
 
+// ControlSocket returns the path of the Unix socket serving the control
+// service, the one setup thanks to WithControlSocket or the default one in the
+// config folder.
+func (d *Config) ControlSocket() string {
+	if d.controlSocket != "" {
+		return d.controlSocket
+	}
+	return path.Join(d.configFolder, DefaultControlSocket)
+}
+
+// ControlTokenPath returns the path of the file storing the token required by
+// the control port when set up with WithControlToken.
+func (d *Config) ControlTokenPath() string {
+	return path.Join(d.configFolder, ControlTokenFile)
+}
+
 // Logger returns the logger associated with this config.
 func (d *Config) Logger() log.Logger {
 	return d.logger
@@ -134,6 +180,14 @@ func WithDkgTimeout(t time.Duration) ConfigOption {
 	}
 }
 
+// WithDkgEchoBroadcast enables the echo broadcast of the DKG responses and
+// justifications. All nodes of the group must enable it.
+func WithDkgEchoBroadcast() ConfigOption {
+	return func(d *Config) {
+		d.dkgEcho = true
+	}
+}
+
 // WithBoltOptions applies boltdb specific options when storing random beacons.
 func WithBoltOptions(opts *bolt.Options) ConfigOption {
 	return func(d *Config) {
@@ -190,6 +244,16 @@ func WithTLS(certPath, keyPath string) ConfigOption {
 	}
 }
 
+// WithMutualTLS requires the nodes calling the protocol methods to present a
+// TLS certificate, trusted by this node, valid for the host of a node of the
+// current or next group and bound to the identity of this node. The node binds
+// its own certificate to its identity when it starts.
+func WithMutualTLS() ConfigOption {
+	return func(d *Config) {
+		d.mutualTLS = true
+	}
+}
+
 // WithTrustedCerts saves the certificates at the given paths and forces drand
 // to trust them. Mostly useful for testing.
 func WithTrustedCerts(certPaths ...string) ConfigOption {
@@ -214,6 +278,74 @@ func WithListenAddress(addr string) ConfigOption {
 	}
 }
 
+// WithPublicListenAddress serves the public gRPC and REST API on their own
+// listener bound to the given address, the listen address of the node only
+// serving the protocol methods then. It allows to expose the public API, for
+// example through a CDN, while keeping the protocol port firewalled.
+func WithPublicListenAddress(addr string) ConfigOption {
+	return func(d *Config) {
+		d.publicListenAddr = addr
+	}
+}
+
+// WithPublicTLS registers the certificate and private key of the public
+// listener, the ones of the node by default.
+func WithPublicTLS(certPath, keyPath string) ConfigOption {
+	return func(d *Config) {
+		d.publicCertPath = certPath
+		d.publicKeyPath = keyPath
+	}
+}
+
+// WithPublicInsecure serves the public listener over non-encrypted TCP
+// connections, for example behind a proxy terminating TLS.
+func WithPublicInsecure() ConfigOption {
+	return func(d *Config) {
+		d.publicInsecure = true
+	}
+}
+
+// WithPublicACME obtains and renews the certificate of the public listener
+// from an ACME certificate authority, Let's Encrypt by default, for the given
+// domains. The account and the certificates are stored in the config folder.
+// The public listener must be reachable on port 443 of the domains.
+func WithPublicACME(domains []string, directoryURL, email string) ConfigOption {
+	return func(d *Config) {
+		d.acme = &net.ACMEConfig{
+			Domains:      domains,
+			DirectoryURL: directoryURL,
+			Email:        email,
+		}
+	}
+}
+
+// WithRateLimit bounds the use of the public gRPC and REST API, per IP
+// address and for all the clients together. The clients exceeding the limits
+// get ResourceExhausted errors, or 429 Too Many Requests on the REST API. The
+// protocol methods between the nodes are not limited.
+func WithRateLimit(conf net.RateLimitConfig) ConfigOption {
+	return func(d *Config) {
+		d.rateLimit = &conf
+	}
+}
+
+// WithCORS only allows the given origins to call the REST API from a browser,
+// instead of any origin.
+func WithCORS(origins ...string) ConfigOption {
+	return func(d *Config) {
+		d.corsOrigins = origins
+	}
+}
+
+// WithGossipPeers forwards the beacons of the group to the nodes or relays at
+// the given addresses, which verify them before forwarding them to their own
+// peers. The peers are contacted over TLS unless the node runs without.
+func WithGossipPeers(addrs ...string) ConfigOption {
+	return func(d *Config) {
+		d.gossipPeers = addrs
+	}
+}
+
 // WithControlPort specifies which port on localhost the ListenerControl should
 // bind to.
 func WithControlPort(port string) ConfigOption {
@@ -222,6 +354,23 @@ func WithControlPort(port string) ConfigOption {
 	}
 }
 
+// WithControlSocket specifies the path of the Unix socket the control service
+// listens on, only accessible by the user running drand.
+func WithControlSocket(socketPath string) ConfigOption {
+	return func(d *Config) {
+		d.controlSocket = socketPath
+	}
+}
+
+// WithControlToken requires the clients of the control port to present the
+// token stored in the config folder, readable only by the user running drand.
+// The token is created at startup if it does not exist yet.
+func WithControlToken() ConfigOption {
+	return func(d *Config) {
+		d.controlToken = true
+	}
+}
+
 // WithLogLevel sets the logging verbosity to the given level.
 func WithLogLevel(level int) ConfigOption {
 	return func(d *Config) {
@@ -241,3 +390,14 @@ func getPeriod(g *key.Group) time.Duration {
 	}
 	return g.Period
 }
+
+// WithExternalKey delegates the signatures and decryptions made with the
+// longterm key to the external process listening on the given unix socket.
+// The public key of the process must match the identity of the node. The DKG
+// and the resharing need the private key itself and are refused with an
+// external key: they must be run without this option.
+func WithExternalKey(socket string) ConfigOption {
+	return func(d *Config) {
+		d.keySocket = socket
+	}
+}
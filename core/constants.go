@@ -1,7 +1,6 @@
 package core
 
 import (
-	"crypto/sha256"
 	"path"
 	"time"
 
@@ -29,6 +28,22 @@ const DefaultBeaconPeriod time.Duration = 1 * time.Minute
 // DefaultControlPort is the default port the functionnality control port communicate on.
 const DefaultControlPort = "8888"
 
+// DefaultControlSocket is the name of the Unix socket of the control service
+// in the config folder.
+const DefaultControlSocket = "control.sock"
+
+// ControlTokenFile is the name of the file storing the token of the control
+// port in the config folder.
+const ControlTokenFile = "control_token"
+
+// DefaultCertReloadPeriod is the period at which the TLS certificate files are
+// checked for changes.
+var DefaultCertReloadPeriod = 30 * time.Second
+
+// DefaultACMEFolder is the name of the folder, in the config folder, storing
+// the account and certificates obtained through ACME.
+const DefaultACMEFolder = "acme"
+
 // DefaultDKGTimeout is the time the DKG timeouts by default. See
 // kyber/share/dkg/pedersen for more information.
 const DefaultDKGTimeout = "1m"
@@ -36,10 +51,21 @@ const DefaultDKGTimeout = "1m"
 // DefaultDialTimeout is the timeout given to gRPC when dialling a remote server
 var DefaultDialTimeout = 10 * time.Second
 
-// RandomnessHash is the hash function used to produce the final randomness from
-// the signature
-var RandomnessHash = sha256.New
-
 // DefaultWaitTime is the time beacon nodes wait before asking other nodes for
 // partial signature. Because time shifts can happen
 var DefaultWaitTime = 300 * time.Millisecond
+
+// DefaultGroupFetchTimeout is the timeout used when fetching a group file from
+// an URL.
+var DefaultGroupFetchTimeout = 10 * time.Second
+
+// MaxGroupFileSize is the maximum size of a group file fetched from an URL.
+const MaxGroupFileSize = 1 << 20
+
+// DefaultDKGRecordFile is the name of the file, in the group folder, holding
+// the entropy receipts of the last DKG.
+const DefaultDKGRecordFile = "dkg_record.toml"
+
+// MaxAnnouncementAge is the maximum age of an address announcement accepted
+// by a node
+const MaxAnnouncementAge = 10 * time.Minute
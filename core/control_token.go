@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/drand/drand/entropy"
+	"github.com/drand/drand/fs"
+)
+
+// controlTokenSize is the number of random bytes of the control token
+const controlTokenSize = 32
+
+// ReadControlToken returns the token of the control port stored at the given
+// path. It refuses a token file readable by other users than its owner.
+func ReadControlToken(tokenPath string) (string, error) {
+	fi, err := os.Stat(tokenPath)
+	if err != nil {
+		return "", err
+	}
+	if fi.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("control: token file %s is accessible by other users (%s)", tokenPath, fi.Mode().Perm())
+	}
+	buff, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buff)), nil
+}
+
+// loadOrCreateControlToken returns the token stored at the given path,
+// creating a random one if the file does not exist.
+func loadOrCreateControlToken(tokenPath string) (string, error) {
+	if exists, _ := fs.Exists(tokenPath); exists {
+		return ReadControlToken(tokenPath)
+	}
+	random, err := entropy.GetRandom(nil, controlTokenSize)
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(random)
+	fd, err := fs.CreateSecureFile(tokenPath)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	if _, err := fd.WriteString(token + "\n"); err != nil {
+		return "", err
+	}
+	return token, nil
+}
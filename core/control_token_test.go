@@ -0,0 +1,37 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-token")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenPath := path.Join(dir, ControlTokenFile)
+
+	token, err := loadOrCreateControlToken(tokenPath)
+	require.NoError(t, err)
+	require.Len(t, token, 2*controlTokenSize)
+	fi, err := os.Stat(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// the token is kept across restarts
+	again, err := loadOrCreateControlToken(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, token, again)
+	read, err := ReadControlToken(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, token, read)
+
+	// a token readable by other users is refused
+	require.NoError(t, os.Chmod(tokenPath, 0644))
+	_, err = ReadControlToken(tokenPath)
+	require.Error(t, err)
+}
@@ -3,12 +3,14 @@ package core
 import (
 	"errors"
 	"fmt"
+	"path"
 	"sync"
 	"time"
 
 	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/dkg"
 	"github.com/drand/drand/fs"
+	"github.com/drand/drand/gossip"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/log"
 	"github.com/drand/drand/net"
@@ -21,7 +23,11 @@ import (
 // signature requests.
 type Drand struct {
 	opts *Config
-	priv *key.Pair
+	// public identity of the node
+	identity *key.Identity
+	// operations with the longterm key, the key pair of the store or an
+	// external key process
+	longterm key.Longterm
 	// current group this drand node is using
 	group *key.Group
 	index int
@@ -29,12 +35,20 @@ type Drand struct {
 	store   key.Store
 	gateway net.Gateway
 	control net.ControlListener
+	// control service on the Unix socket
+	controlSocket net.ControlListener
+	// TLS key pairs of the listeners, reloaded when their files change
+	keyPairs []*net.KeyPairReloader
 
 	// handle all callbacks when a new beacon is found
 	callbacks *callbackManager
 
 	dkg    *dkg.Handler
 	beacon *beacon.Handler
+	// propagates the beacons to the gossip peers, nil without peers
+	gossip *gossip.Handler
+	// finished dkg still collecting the entropy reveals of the other nodes
+	revealDKG *dkg.Handler
 	// dkg private share. can be nil if dkg not finished yet.
 	share *key.Share
 	// dkg public key. Can be nil if dkg not finished yet.
@@ -66,16 +80,40 @@ func NewDrand(s key.Store, c *Config) (*Drand, error) {
 	return d, nil
 }
 
-// initDrand inits the drand struct by loading the private key, and by creating the
+// initDrand inits the drand struct by loading the longterm key, and by creating the
 // gateway with the correct options.
 func initDrand(s key.Store, c *Config) (*Drand, error) {
 	logger := c.Logger()
 	if c.insecure == false && (c.certPath == "" || c.keyPath == "") {
 		return nil, errors.New("config: need to set WithInsecure if no certificate and private key path given")
 	}
-	priv, err := s.LoadKeyPair()
-	if err != nil {
-		return nil, err
+	if c.insecure && c.mutualTLS {
+		return nil, errors.New("config: mutual TLS can not be used with WithInsecure")
+	}
+	// with an external key, the private key never leaves the key process and
+	// the store only holds the public identity
+	var identity *key.Identity
+	var longterm key.Longterm
+	if c.keySocket != "" {
+		ext := key.NewExternalKey(c.keySocket)
+		pub, err := ext.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if identity, err = s.LoadIdentity(); err != nil {
+			return nil, err
+		}
+		if !pub.Equal(identity.Key) {
+			return nil, errors.New("drand: external key does not match the identity key")
+		}
+		logger.Info("longterm_key", "external", "socket", c.keySocket)
+		longterm = ext
+	} else {
+		priv, err := s.LoadKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		identity, longterm = priv.Public, priv
 	}
 
 	// trick to always set the listening address by default based on the
@@ -83,31 +121,72 @@ func initDrand(s key.Store, c *Config) (*Drand, error) {
 	// default set here..
 	d := &Drand{
 		store:     s,
-		priv:      priv,
+		identity:  identity,
+		longterm:  longterm,
 		opts:      c,
 		log:       logger,
 		exitCh:    make(chan bool, 1),
 		callbacks: newCallbackManager(),
 	}
+	if c.mutualTLS {
+		if err := d.bindTLSKey(); err != nil {
+			return nil, err
+		}
+	}
 	// every new beacon will be passed through the opts callbacks
 	d.callbacks.AddCallback(callbackID, d.opts.callbacks)
 
-	a := c.ListenAddress(priv.Public.Address())
-	if c.insecure {
-		d.log.Info("network", "tls-disable")
-		d.gateway = net.NewGrpcGatewayInsecure(a, d, d.opts.grpcOpts...)
-	} else {
-		d.log.Info("network", "tls-enabled")
-		d.gateway = net.NewGrpcGatewayFromCertManager(a, c.certPath, c.keyPath, c.certmanager, d, d.opts.grpcOpts...)
+	a := c.ListenAddress(identity.Address())
+	var err error
+	d.gateway, err = d.newGateway(a)
+	if err != nil {
+		return nil, fmt.Errorf("drand: can't set up the network: %s", err)
+	}
+	d.controlSocket, err = net.NewUnixGrpcControlListener(d, c.ControlSocket())
+	if err != nil {
+		return nil, fmt.Errorf("drand: can't listen on control socket: %s", err)
 	}
 	p := c.ControlPort()
-	d.control = net.NewTCPGrpcControlListener(d, p)
+	if c.controlToken {
+		token, err := loadOrCreateControlToken(c.ControlTokenPath())
+		if err != nil {
+			return nil, fmt.Errorf("drand: can't load control token: %s", err)
+		}
+		d.control = net.NewTCPGrpcControlListenerWithToken(d, p, token)
+	} else {
+		d.control = net.NewTCPGrpcControlListener(d, p)
+	}
 	go d.control.Start()
-	d.log.Info("network_listen", a, "control_port", c.ControlPort())
+	go d.controlSocket.Start()
+	d.log.Info("network_listen", a, "public_listen", c.PublicListenAddress(a), "control_port", c.ControlPort(), "control_socket", c.ControlSocket())
 	d.gateway.StartAll()
 	return d, nil
 }
 
+// protocolPeers returns the nodes allowed to call the protocol methods with
+// mutual TLS: the nodes of the current group and of the group being set up by
+// a DKG or a resharing.
+func (d *Drand) protocolPeers() []net.Peer {
+	d.state.Lock()
+	defer d.state.Unlock()
+	var peers []net.Peer
+	add := func(g *key.Group) {
+		if g == nil {
+			return
+		}
+		for _, id := range g.Nodes {
+			peers = append(peers, id)
+		}
+	}
+	add(d.group)
+	add(d.nextGroup)
+	if d.nextConf != nil {
+		add(d.nextConf.NewNodes)
+		add(d.nextConf.OldNodes)
+	}
+	return peers
+}
+
 // LoadDrand restores a drand instance that is ready to serve randomness, with a
 // pre-existing distributed share.
 func LoadDrand(s key.Store, c *Config) (*Drand, error) {
@@ -127,7 +206,7 @@ func LoadDrand(s key.Store, c *Config) (*Drand, error) {
 	if err != nil {
 		return nil, err
 	}
-	d.log.Debug("serving", d.priv.Public.Address())
+	d.log.Debug("serving", d.identity.Address())
 	d.dkgDone = true
 	return d, nil
 }
@@ -176,16 +255,45 @@ func (d *Drand) WaitDKG(conf *dkg.Config) error {
 	d.group.GenesisTime = conf.NewNodes.GenesisTime
 	d.group.TransitionTime = conf.NewNodes.TransitionTime
 	d.group.GenesisSeed = conf.NewNodes.GetGenesisSeed()
+	d.group.Unchained = conf.NewNodes.Unchained
+	d.group.RandomnessHash = conf.NewNodes.RandomnessHash
 
 	d.log.Debug("dkg_end", time.Now(), "certified", d.group.Len())
 	d.store.SaveGroup(d.group)
 	d.opts.applyDkgCallback(d.share)
 	d.dkgDone = true
+	d.revealDKG = d.dkg
+	go d.saveDKGRecord(d.dkg, conf.Timeout)
 	d.dkg = nil
 	d.nextConf = nil
 	return nil
 }
 
+// saveDKGRecord waits for the entropy reveals of the dealers, at most the
+// given timeout, and saves the resulting record of the DKG.
+func (d *Drand) saveDKGRecord(h *dkg.Handler, timeout time.Duration) {
+	select {
+	case <-h.WaitReveals():
+	case <-d.opts.clock.After(timeout):
+		d.log.Info("dkg_record", "missing reveals")
+	}
+	d.state.Lock()
+	if d.revealDKG == h {
+		d.revealDKG = nil
+	}
+	d.state.Unlock()
+	record := h.Record()
+	if len(record.Receipts) == 0 {
+		return
+	}
+	p := path.Join(d.opts.ConfigFolder(), key.GroupFolderName, DefaultDKGRecordFile)
+	if err := key.Save(p, record, false); err != nil {
+		d.log.Error("dkg_record", err)
+		return
+	}
+	d.log.Info("dkg_record", "saved", "path", p)
+}
+
 // createDKG create the new dkg handler according to the nextConf field. If the
 // dkg is not nil, it does not do anything.
 func (d *Drand) createDKG(conf *dkg.Config) error {
@@ -240,11 +348,10 @@ func (d *Drand) transition(oldGroup *key.Group, oldPresent, newPresent bool) {
 			d.log.Fatal("new_beacon", err)
 		}
 		var found bool
-		d.index, found = d.group.Index(d.priv.Public)
+		d.index, found = d.group.Index(d.identity)
 		if !found {
 			d.log.Fatal("transition_index", "absent")
 		}
-		d.beacon.AddCallback(d.callbacks.NewBeacon)
 		return d.beacon
 	}
 	// the node should stop a bit before the new round to avoid starting it at
@@ -253,7 +360,7 @@ func (d *Drand) transition(oldGroup *key.Group, oldPresent, newPresent bool) {
 	// case to go that fast
 	timeToStop := d.group.TransitionTime - 1
 	if !newPresent {
-		//fmt.Printf(" OLD NODE STOPping %s\n", d.priv.Public.Address())
+		//fmt.Printf(" OLD NODE STOPping %s\n", d.identity.Address())
 		// an old node is leaving the network
 		if err := d.beacon.StopAt(timeToStop); err != nil {
 			d.log.Error("leaving_group", err)
@@ -272,14 +379,14 @@ func (d *Drand) transition(oldGroup *key.Group, oldPresent, newPresent bool) {
 		currentBeacon.StopAt(timeToStop)
 		nbeacon := replaceBeacon()
 		//lbeacon, _ := nbeacon.Store().Last()
-		//fmt.Printf(" TRANSITION OLD NODE done: node %s - %p : current %d : will stop at %d --> pub %s \n", d.priv.Public.Address(), nbeacon, d.opts.clock.Now().Unix(), timeToStop, share.PubPoly().Eval(1).V.String()[14:19])
+		//fmt.Printf(" TRANSITION OLD NODE done: node %s - %p : current %d : will stop at %d --> pub %s \n", d.identity.Address(), nbeacon, d.opts.clock.Now().Unix(), timeToStop, share.PubPoly().Eval(1).V.String()[14:19])
 		nbeacon.Transition(oldGroup.Nodes)
 		d.log.Info("transition_old", "done")
 	} else {
 		// tell the new node that has "nothing" stored to sync in the meantime
 		// and then to start at the time of the new network
 		newBeacon := replaceBeacon()
-		//fmt.Printf(" TRANSITION NEW NODE: node %d: %s - %p calling transition pub %s\n\n", d.index, d.priv.Public.Address(), d.beacon, d.share.PubPoly().Eval(1).V.String()[14:19])
+		//fmt.Printf(" TRANSITION NEW NODE: node %d: %s - %p calling transition pub %s\n\n", d.index, d.identity.Address(), d.beacon, d.share.PubPoly().Eval(1).V.String()[14:19])
 		if err := newBeacon.Transition(oldGroup.Nodes); err != nil {
 			d.log.Error("sync_before", err)
 		}
@@ -304,6 +411,10 @@ func (d *Drand) Stop() {
 	d.state.Lock()
 	d.gateway.StopAll()
 	d.control.Stop()
+	d.controlSocket.Stop()
+	for _, k := range d.keyPairs {
+		k.Stop()
+	}
 	d.state.Unlock()
 	d.exitCh <- true
 }
@@ -328,13 +439,37 @@ func (d *Drand) newBeacon() (*beacon.Handler, error) {
 		return nil, err
 	}
 	conf := &beacon.Config{
-		Group:   d.group,
-		Private: d.priv,
-		Share:   d.share,
-		Scheme:  key.Scheme,
-		Clock:   d.opts.clock,
+		Group:  d.group,
+		Public: d.identity,
+		Share:  d.share,
+		Scheme: d.group.Suite().Scheme,
+		Clock:  d.opts.clock,
+	}
+	h, err := beacon.NewHandler(d.gateway.ProtocolClient, store, conf, d.log)
+	if err != nil {
+		return nil, err
+	}
+	// the streams of the public API and the callbacks of the config follow
+	// the beacons of the current handler
+	h.AddCallback(d.callbacks.NewBeacon)
+	if len(d.opts.gossipPeers) == 0 {
+		return h, nil
+	}
+	// the group of the beacons to propagate changes with the beacon handler
+	peers := make([]net.Peer, len(d.opts.gossipPeers))
+	for i, addr := range d.opts.gossipPeers {
+		peers[i] = &peerAddr{addr, !d.opts.insecure}
+	}
+	d.gossip, err = gossip.NewHandler(d.gateway.ProtocolClient, &gossip.Config{
+		Group: d.group,
+		Peers: peers,
+		Clock: d.opts.clock,
+	}, d.log)
+	if err != nil {
+		return nil, err
 	}
-	return beacon.NewHandler(d.gateway.ProtocolClient, store, conf, d.log)
+	h.AddCallback(d.gossip.Publish)
+	return h, nil
 }
 
 func (d *Drand) beaconCallback(b *beacon.Beacon) {
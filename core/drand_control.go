@@ -8,12 +8,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/drand/drand/dkg"
 	"github.com/drand/drand/entropy"
 	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
 	"github.com/drand/drand/protobuf/drand"
 	control "github.com/drand/drand/protobuf/drand"
 	vss "github.com/drand/kyber/share/vss/pedersen"
@@ -43,19 +48,25 @@ func (d *Drand) InitDKG(c context.Context, in *control.InitDKGPacket) (*control.
 		d.log.Error("genesis", "invalid", "given", group.GenesisTime, "now", d.opts.clock.Now().Unix())
 		return nil, errors.New("control: group with genesis time in the past")
 	}
-	index, found := group.Index(d.priv.Public)
+	if err := group.ValidSignatures(); err != nil {
+		d.state.Unlock()
+		return nil, fmt.Errorf("drand: invalid identity in group: %s", err)
+	}
+	index, found := group.Index(d.identity)
 	if !found {
 		d.state.Unlock()
 		return nil, errors.New("drand: public key not found in group")
 	}
 	reader, user := extractEntropy(in.Entropy)
 	dkgConfig := &dkg.Config{
-		Suite:          key.KeyGroup.(dkg.Suite),
+		Suite:          group.Suite().KeyGroup.(dkg.Suite),
 		NewNodes:       group,
-		Key:            d.priv,
+		Public:         d.identity,
+		Longterm:       d.longterm,
 		Reader:         reader,
 		UserReaderOnly: user,
 		Clock:          d.opts.clock,
+		EchoBroadcast:  d.opts.dkgEcho,
 	}
 	d.nextConf = dkgConfig
 	if err := setTimeout(d.nextConf, in.Timeout); err != nil {
@@ -89,25 +100,11 @@ func (d *Drand) InitDKG(c context.Context, in *control.InitDKGPacket) (*control.
 // received node is stated as a leader and is present in the old group.
 // This function waits for the resharing DKG protocol to finish.
 func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*control.Empty, error) {
-	var oldGroup, newGroup *key.Group
-	var err error
-
-	if newGroup, err = extractGroup(in.New); err != nil {
+	oldGroup, newGroup, err := d.reshareGroups(in)
+	if err != nil {
 		return nil, err
 	}
 
-	d.state.Lock()
-	if oldGroup, err = extractGroup(in.Old); err != nil {
-		// try to get the current group
-		if d.group == nil {
-			d.state.Unlock()
-			return nil, errors.New("drand: can't init-reshare if no old group provided")
-		}
-		d.log.With("module", "control").Debug("init_reshare", "old group equal current group")
-		oldGroup = d.group
-	}
-	d.state.Unlock()
-
 	if oldGroup.GenesisTime != newGroup.GenesisTime {
 		return nil, errors.New("control: old and new group have different genesis time")
 	}
@@ -124,13 +121,34 @@ func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*
 		return nil, errors.New("control: group with transition time in the past")
 	}
 
-	oldIdx, oldPresent := oldGroup.Index(d.priv.Public)
-	newIdx, newPresent := newGroup.Index(d.priv.Public)
+	// the nodes that rotated their key run the resharing with their new key
+	rotatedGroup, err := key.ApplyRotations(oldGroup, newGroup)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := d.rotatedKey(newGroup)
+	if err != nil {
+		return nil, err
+	}
+
 	var dkgConf *dkg.Config
+	var oldIdx, newIdx int
+	var oldPresent, newPresent bool
 	err = func() error {
 		d.state.Lock()
 		defer d.state.Unlock()
 
+		// the resharing runs with the rotated key, if any, but the node keeps
+		// its current key until the resharing succeeds
+		var identity = d.identity
+		var longterm = d.longterm
+		if rotated != nil {
+			identity = rotated.Public
+			longterm = rotated
+		}
+		oldIdx, oldPresent = rotatedGroup.Index(identity)
+		newIdx, newPresent = newGroup.Index(identity)
+
 		if oldPresent {
 			if d.group == nil {
 				return errors.New("control: present in old group but no dkg here")
@@ -152,11 +170,13 @@ func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*
 
 		// prepare dkg config to run the protocol
 		dkgConf = &dkg.Config{
-			OldNodes: oldGroup,
-			NewNodes: newGroup,
-			Key:      d.priv,
-			Suite:    key.KeyGroup.(dkg.Suite),
-			Clock:    d.opts.clock,
+			OldNodes:      rotatedGroup,
+			NewNodes:      newGroup,
+			Public:        identity,
+			Longterm:      longterm,
+			Suite:         newGroup.Suite().KeyGroup.(dkg.Suite),
+			Clock:         d.opts.clock,
+			EchoBroadcast: d.opts.dkgEcho,
 		}
 
 		// gives the share to the dkg if we are a current node
@@ -199,11 +219,105 @@ func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*
 		return nil, err
 	}
 	d.log.Info("dkg_reshare", "finished")
+	if rotated != nil {
+		if err := d.commitKey(rotated); err != nil {
+			return nil, err
+		}
+	}
 	fmt.Printf("going to TRANSITION: oidx %d nidx %d oldpresent %v newpresent %v\n", oldIdx, newIdx, oldPresent, newPresent)
-	go d.transition(oldGroup, oldPresent, newPresent)
+	go d.transition(rotatedGroup, oldPresent, newPresent)
 	return &control.Empty{}, nil
 }
 
+// ReshareDryRun returns the differences between the old and new group of the
+// resharing request as well as the problems that would prevent the resharing
+// to happen safely, without running the resharing.
+func (d *Drand) ReshareDryRun(c context.Context, in *control.InitResharePacket) (*control.ReshareDryRunResponse, error) {
+	oldGroup, newGroup, err := d.reshareGroups(in)
+	if err != nil {
+		return nil, err
+	}
+	conf := new(dkg.Config)
+	if err := setTimeout(conf, in.Timeout); err != nil {
+		return nil, fmt.Errorf("drand: invalid timeout: %s", err)
+	}
+	resp := checkReshare(oldGroup, newGroup, d.opts.clock.Now().Unix(), conf.Timeout)
+
+	// stateful verifications, as done by InitReshare
+	if _, oldPresent := oldGroup.Index(d.identity); oldPresent {
+		d.state.Lock()
+		defer d.state.Unlock()
+		if d.group == nil || !d.dkgDone || d.share == nil {
+			resp.Problems = append(resp.Problems, "this node is present in the old group but has no share")
+		} else if !sameGroup(d.group, oldGroup) {
+			resp.Problems = append(resp.Problems, "old group is not the same as the group of this node")
+		}
+	}
+	return resp, nil
+}
+
+// rotatedKey returns the pending key pair of this node if the new group
+// contains a rotation of its current key, nil otherwise. The new key pair must
+// have been saved in the store by generate-keypair --rotate. The node only
+// switches to it once the resharing succeeded, with commitKey.
+func (d *Drand) rotatedKey(newGroup *key.Group) (*key.Pair, error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	for _, id := range newGroup.Nodes {
+		if id.Rotation == nil || !id.Rotation.OldKey.Equal(d.identity.Key) {
+			continue
+		}
+		if d.opts.keySocket != "" {
+			return nil, errors.New("drand: can't rotate a key held by an external process")
+		}
+		pair, err := d.store.LoadPendingKeyPair()
+		if err != nil {
+			return nil, fmt.Errorf("drand: can't load rotated key: %s", err)
+		}
+		if !pair.Public.Key.Equal(id.Key) {
+			return nil, errors.New("drand: new group rotates this node to a key absent from the store")
+		}
+		return pair, nil
+	}
+	return nil, nil
+}
+
+// commitKey switches to the rotated key pair once the resharing to the group
+// containing it succeeded.
+func (d *Drand) commitKey(pair *key.Pair) error {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if err := d.store.CommitPendingKeyPair(); err != nil {
+		return fmt.Errorf("drand: can't save rotated key: %s", err)
+	}
+	d.log.Info("key_rotation", pair.Public.Key.String())
+	d.identity = pair.Public
+	d.longterm = pair
+	return nil
+}
+
+// reshareGroups returns the old and new group of a resharing request. The old
+// group is the current group of the node if the request does not specify it.
+func (d *Drand) reshareGroups(in *control.InitResharePacket) (*key.Group, *key.Group, error) {
+	newGroup, err := extractGroup(in.New)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.state.Lock()
+	defer d.state.Unlock()
+	oldGroup, err := extractGroup(in.Old)
+	if err != nil {
+		// try to get the current group
+		if d.group == nil {
+			return nil, nil, errors.New("drand: can't init-reshare if no old group provided")
+		}
+		d.log.With("module", "control").Debug("init_reshare", "old group equal current group")
+		oldGroup = d.group
+	}
+	return oldGroup, newGroup, nil
+}
+
 func (d *Drand) startResharingAsLeader(dkgConf *dkg.Config, oidx int) {
 	d.log.With("module", "control").Debug("leader_reshare", "start signalling")
 	d.state.Lock()
@@ -216,7 +330,7 @@ func (d *Drand) startResharingAsLeader(dkgConf *dkg.Config, oidx int) {
 		}
 		id := p
 		// XXX find way to just have a small RPC timeout if one is down.
-		//fmt.Printf("drand leader %s -> signal to %s\n", d.priv.Public.Addr, id.Addr)
+		//fmt.Printf("drand leader %s -> signal to %s\n", d.identity.Addr, id.Addr)
 		if _, err := d.gateway.ProtocolClient.Reshare(id, msg); err != nil {
 			//if _, err := d.gateway.InternalClient.Reshare(id, msg, grpc.FailFast(true)); err != nil {
 			d.log.With("module", "control").Error("leader_reshare", err)
@@ -248,14 +362,17 @@ func (d *Drand) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyR
 		return nil, err
 	}
 	return &drand.DistKeyResponse{
-		Key: buff,
+		Key:    buff,
+		Scheme: pt.Suite().Name,
 	}, nil
 }
 
 // PingPong simply responds with an empty packet, proving that this drand node
 // is up and alive.
 func (d *Drand) PingPong(c context.Context, in *control.Ping) (*control.Pong, error) {
-	return &control.Pong{}, nil
+	d.state.Lock()
+	defer d.state.Unlock()
+	return &control.Pong{DkgRunning: d.dkg != nil && !d.dkgDone}, nil
 }
 
 // Share is a functionality of Control Service defined in protobuf/control that requests the private share of the drand node running locally
@@ -276,11 +393,11 @@ func (d *Drand) Share(ctx context.Context, in *control.ShareRequest) (*control.S
 func (d *Drand) PublicKey(ctx context.Context, in *control.PublicKeyRequest) (*control.PublicKeyResponse, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
-	key, err := d.store.LoadKeyPair()
+	id, err := d.store.LoadIdentity()
 	if err != nil {
 		return nil, err
 	}
-	protoKey, err := key.Public.Key.MarshalBinary()
+	protoKey, err := id.Key.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -291,6 +408,9 @@ func (d *Drand) PublicKey(ctx context.Context, in *control.PublicKeyRequest) (*c
 func (d *Drand) PrivateKey(ctx context.Context, in *control.PrivateKeyRequest) (*control.PrivateKeyResponse, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
+	if d.opts.keySocket != "" {
+		return nil, errors.New("drand: the private key is held by an external process")
+	}
 	key, err := d.store.LoadKeyPair()
 	if err != nil {
 		return nil, err
@@ -335,22 +455,75 @@ func (d *Drand) GroupFile(ctx context.Context, in *control.GroupTOMLRequest) (*c
 	return &drand.GroupTOMLResponse{GroupToml: groupStr}, nil
 }
 
+// PeerStatus replies with the health of the connections to the nodes of the
+// current and next groups
+func (d *Drand) PeerStatus(ctx context.Context, in *control.PeerStatusRequest) (*control.PeerStatusResponse, error) {
+	reporter, ok := d.gateway.ProtocolClient.(net.HealthReporter)
+	if !ok {
+		return nil, errors.New("drand: protocol client does not track the health of the nodes")
+	}
+	unix := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+	resp := new(control.PeerStatusResponse)
+	seen := map[string]bool{d.identity.Address(): true}
+	for _, p := range d.protocolPeers() {
+		if seen[p.Address()] {
+			continue
+		}
+		seen[p.Address()] = true
+		s := reporter.PeerStatus(p.Address())
+		resp.Peers = append(resp.Peers, &control.PeerStatus{
+			Address:     s.Address,
+			State:       s.State,
+			Failures:    uint32(s.Failures),
+			LastSuccess: unix(s.LastSuccess),
+			LastFailure: unix(s.LastFailure),
+			LastError:   s.LastError,
+			RetryAt:     unix(s.RetryAt),
+		})
+	}
+	return resp, nil
+}
+
 func (d *Drand) Shutdown(ctx context.Context, in *control.ShutdownRequest) (*control.ShutdownResponse, error) {
 	d.Stop()
 	return nil, nil
 }
 
 func extractGroup(i *control.GroupInfo) (*key.Group, error) {
-	var g = &key.Group{}
-	switch x := i.Location.(type) {
+	var buff []byte
+	var err error
+	switch x := i.GetLocation().(type) {
 	case *control.GroupInfo_Path:
 		// search group file via local filesystem path
-		if err := key.Load(x.Path, g); err != nil {
-			return nil, err
-		}
+		buff, err = ioutil.ReadFile(x.Path)
+	case *control.GroupInfo_Url:
+		buff, err = fetchGroup(x.Url)
 	default:
 		return nil, errors.New("control: can't allow new empty group")
 	}
+	if err != nil {
+		return nil, err
+	}
+	// the pin covers the exact content of the file, not only the fields of
+	// the group hash
+	if expected := i.GetHash(); expected != "" {
+		if hash := key.FileHash(buff); !strings.EqualFold(hash, expected) {
+			return nil, fmt.Errorf("control: group file hash %s differs from expected hash %s", hash, expected)
+		}
+	}
+	gt := &key.GroupTOML{}
+	if _, err := toml.Decode(string(buff), gt); err != nil {
+		return nil, fmt.Errorf("control: decoding group: %s", err)
+	}
+	g := &key.Group{}
+	if err := g.FromTOML(gt); err != nil {
+		return nil, fmt.Errorf("control: decoding group: %s", err)
+	}
 	// run a few checks on the proposed group
 	if g.Len() < 4 {
 		return nil, errors.New("control: can't accept group with fewer than 4 members")
@@ -362,6 +535,31 @@ func extractGroup(i *control.GroupInfo) (*key.Group, error) {
 	return g, nil
 }
 
+// fetchGroup downloads the group file served at the given http(s) url.
+func fetchGroup(groupURL string) ([]byte, error) {
+	u, err := url.Parse(groupURL)
+	if err != nil {
+		return nil, fmt.Errorf("control: invalid group url: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("control: unsupported group url scheme %q", u.Scheme)
+	}
+	client := &http.Client{Timeout: DefaultGroupFetchTimeout}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("control: fetching group: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("control: fetching group: unexpected status %s", resp.Status)
+	}
+	buff, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxGroupFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("control: fetching group: %s", err)
+	}
+	return buff, nil
+}
+
 func extractEntropy(i *control.EntropyInfo) (io.Reader, bool) {
 	if i == nil {
 		return nil, false
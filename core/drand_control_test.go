@@ -0,0 +1,67 @@
+package core
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractGroupURL(t *testing.T) {
+	n := 5
+	ids := test.ListFromPrivates(test.GenerateIDs(n))
+	group := key.NewGroup(ids, key.DefaultThreshold(n), 10)
+	var buff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&buff).Encode(group.TOML()))
+	hash := key.FileHash(buff.Bytes())
+
+	// same group hash but a different address
+	moved := *group
+	moved.Nodes = append([]*key.Identity{}, group.Nodes...)
+	movedID := *moved.Nodes[0]
+	movedID.Addr = "127.0.0.1:1"
+	moved.Nodes[0] = &movedID
+	var movedBuff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&movedBuff).Encode(moved.TOML()))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/group.toml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buff.Bytes())
+	})
+	mux.HandleFunc("/moved.toml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(movedBuff.Bytes())
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	url := srv.URL + "/group.toml"
+
+	g, err := extractGroup(net.GroupFromURL(url, ""))
+	require.NoError(t, err)
+	require.Equal(t, group.String(), g.String())
+
+	g, err = extractGroup(net.GroupFromURL(url, hash))
+	require.NoError(t, err)
+	require.Equal(t, group.String(), g.String())
+
+	_, err = extractGroup(net.GroupFromURL(url, "deadbeef"))
+	require.Error(t, err)
+
+	// the pin covers the addresses, which are not part of the group hash
+	_, err = extractGroup(net.GroupFromURL(srv.URL+"/moved.toml", hash))
+	require.Error(t, err)
+
+	_, err = extractGroup(net.GroupFromURL(srv.URL+"/unknown", ""))
+	require.Error(t, err)
+
+	_, err = extractGroup(net.GroupFromURL("ftp://127.0.0.1/group.toml", ""))
+	require.Error(t, err)
+
+	_, err = extractGroup(nil)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"time"
@@ -18,6 +19,11 @@ import (
 func (d *Drand) Setup(c context.Context, in *drand.SetupPacket) (*drand.Empty, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
+	if in.Dkg.GetReveal() != nil && d.revealDKG != nil {
+		// entropy reveals are sent once nodes finished the dkg
+		d.revealDKG.Process(c, in.Dkg)
+		return new(drand.Empty), nil
+	}
 	if d.dkgDone {
 		return nil, errors.New("drand: dkg finished already")
 	}
@@ -34,7 +40,7 @@ func (d *Drand) Reshare(c context.Context, in *drand.ResharePacket) (*drand.Empt
 	defer d.state.Unlock()
 
 	if d.nextGroupHash == "" {
-		return nil, fmt.Errorf("drand %s: can't reshare because InitReshare has not been called", d.priv.Public.Addr)
+		return nil, fmt.Errorf("drand %s: can't reshare because InitReshare has not been called", d.identity.Addr)
 	}
 
 	// check that we are resharing to the new group that we expect
@@ -73,6 +79,18 @@ func (d *Drand) NewBeacon(c context.Context, in *drand.BeaconPacket) (*drand.Emp
 	return d.beacon.ProcessBeacon(c, in)
 }
 
+// Beacon receives a beacon gossiped by a peer of the mesh, forwarded to the
+// other peers once verified.
+func (d *Drand) Beacon(c context.Context, in *drand.GossipPacket) (*drand.Empty, error) {
+	d.state.Lock()
+	g := d.gossip
+	d.state.Unlock()
+	if g == nil {
+		return nil, errors.New("drand: gossip not enabled")
+	}
+	return g.ProcessGossip(c, in)
+}
+
 // PublicRand returns a public random beacon according to the request. If the Round
 // field is 0, then it returns the last one generated.
 func (d *Drand) PublicRand(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
@@ -101,47 +119,59 @@ func (d *Drand) PublicRand(c context.Context, in *drand.PublicRandRequest) (*dra
 		PreviousRound:     beacon.PreviousRound,
 		Round:             beacon.Round,
 		Signature:         beacon.Signature,
-		Randomness:        beacon.Randomness(),
+		Randomness:        beacon.RandomnessWith(d.group.RandomnessHashFunc()),
 	}, nil
 }
 
+// PublicRandStream sends the beacons from the requested round, if any, then
+// the new beacons until the client leaves
 func (d *Drand) PublicRandStream(req *drand.PublicRandRequest, stream drand.Public_PublicRandStreamServer) error {
 	peer, _ := peer.FromContext(stream.Context())
 	addr := peer.Addr.String()
-	done := make(chan error, 1)
-	d.log.Debug("request", "stream", "from", addr)
-	// register a callback for the duration of this stream
-	d.callbacks.AddCallback(addr, func(b *beacon.Beacon) {
-		err := stream.Send(&drand.PublicRandResponse{
+	d.log.Debug("request", "stream", "from", addr, "round", req.GetRound())
+	newHash := sha256.New
+	var store beacon.Store
+	d.state.Lock()
+	if d.group != nil {
+		newHash = d.group.RandomnessHashFunc()
+	}
+	if d.beacon != nil {
+		store = d.beacon.Store()
+	}
+	d.state.Unlock()
+	if req.GetRound() != 0 && store == nil {
+		return errors.New("drand: beacon generation not started yet")
+	}
+	return d.callbacks.streamBeacons(stream.Context(), addr, store, req.GetRound(), func(b *beacon.Beacon) error {
+		return stream.Send(&drand.PublicRandResponse{
 			Round:             b.Round,
 			Signature:         b.Signature,
 			PreviousRound:     b.PreviousRound,
 			PreviousSignature: b.PreviousSig,
-			Randomness:        b.Randomness(),
+			Randomness:        b.RandomnessWith(newHash),
 		})
-		// if connection has a problem, we drop the callback
-		if err != nil {
-			d.callbacks.DelCallback(addr)
-			done <- err
-		}
 	})
-	return <-done
 }
 
 // PrivateRand returns an ECIES encrypted random blob of 32 bytes from /dev/urandom
 func (d *Drand) PrivateRand(c context.Context, priv *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	suite, err := key.SuiteOf(d.identity.Key)
+	if err != nil {
+		return nil, err
+	}
+	g := suite.KeyGroup
 	protoPoint := priv.GetRequest().GetEphemeral()
-	point := key.KeyGroup.Point()
+	point := g.Point()
 	if err := point.UnmarshalBinary(protoPoint); err != nil {
 		return nil, err
 	}
-	msg, err := ecies.Decrypt(key.KeyGroup, ecies.DefaultHash, d.priv.Key, priv.GetRequest())
+	msg, err := ecies.DecryptDH(g, ecies.DefaultHash, d.longterm.DH, priv.GetRequest())
 	if err != nil {
 		d.log.With("module", "public").Error("private", "invalid ECIES", "err", err.Error())
 		return nil, errors.New("invalid ECIES request")
 	}
 
-	clientKey := key.KeyGroup.Point()
+	clientKey := g.Point()
 	if err := clientKey.UnmarshalBinary(msg); err != nil {
 		return nil, errors.New("invalid client key")
 	}
@@ -152,7 +182,7 @@ func (d *Drand) PrivateRand(c context.Context, priv *drand.PrivateRandRequest) (
 		return nil, fmt.Errorf("error gathering randomness: expected 32 bytes, got %d", len(randomness))
 	}
 
-	obj, err := ecies.Encrypt(key.KeyGroup, ecies.DefaultHash, clientKey, randomness[:])
+	obj, err := ecies.Encrypt(g, ecies.DefaultHash, clientKey, randomness[:])
 	return &drand.PrivateRandResponse{Response: obj}, err
 }
 
@@ -164,7 +194,7 @@ func (d *Drand) Home(c context.Context, in *drand.HomeRequest) (*drand.HomeRespo
 	}
 	return &drand.HomeResponse{
 		Status: fmt.Sprintf("drand up and running on %s",
-			d.priv.Public.Address()),
+			d.identity.Address()),
 	}, nil
 }
 
@@ -176,23 +206,27 @@ func (d *Drand) Group(ctx context.Context, in *drand.GroupRequest) (*drand.Group
 	if d.group == nil {
 		return nil, errors.New("drand: no dkg group setup yet")
 	}
-	gtoml := d.group.TOML().(*key.GroupTOML)
+	return groupResponse(d.group), nil
+}
+
+// groupResponse returns the public description of the group
+func groupResponse(g *key.Group) *drand.GroupResponse {
+	gtoml := g.TOML().(*key.GroupTOML)
 	var resp = new(drand.GroupResponse)
 	resp.Nodes = make([]*drand.Node, len(gtoml.Nodes))
-	for i, n := range gtoml.Nodes {
-		resp.Nodes[i] = &drand.Node{
-			Address: n.Address,
-			Key:     n.Key,
-			TLS:     n.TLS,
-		}
+	for i, id := range g.Nodes {
+		resp.Nodes[i] = toNode(id)
 	}
 	resp.Threshold = uint32(gtoml.Threshold)
+	resp.Scheme = g.Suite().Name
+	resp.Unchained = g.Unchained
+	resp.RandomnessHash = g.RandomnessHashName()
 	// take the period in second -> ms. grouptoml already transforms it to toml
-	ms := uint32(d.group.Period / time.Millisecond)
+	ms := uint32(g.Period / time.Millisecond)
 	resp.Period = ms
 	if gtoml.PublicKey != nil {
 		resp.Distkey = make([]string, len(gtoml.PublicKey.Coefficients))
 		copy(resp.Distkey, gtoml.PublicKey.Coefficients)
 	}
-	return resp, nil
+	return resp
 }
@@ -1,11 +1,13 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	gnet "net"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -55,6 +57,132 @@ func TestDrandDKGFresh(t *testing.T) {
 	dt.TestPublicBeacon(dt.ids[0])
 }
 
+func TestDrandDKGMutualTLS(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis, WithMutualTLS())
+	defer dt.Cleanup()
+	// the nodes share their host, only the bound TLS keys tell them apart
+	for _, d := range dt.drands {
+		require.NotNil(t, d.identity.TLSKeyHash)
+		require.NoError(t, d.identity.ValidSignature())
+	}
+	dt.RunDKG()
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.TestPublicBeacon(dt.ids[0])
+}
+
+func TestDrandDKGExternalKey(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis)
+	defer dt.Cleanup()
+
+	// the first node deals, decrypts and signs through its key process
+	ext := dt.drands[dt.ids[0]]
+	pair, err := ext.store.LoadKeyPair()
+	require.NoError(t, err)
+	socket := path.Join(dt.dir, "key.sock")
+	l, err := gnet.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+	go key.ServeExternalKey(l, pair)
+	ext.longterm = key.NewExternalKey(socket)
+
+	dt.RunDKG()
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.TestPublicBeacon(dt.ids[0])
+}
+
+func TestDrandPeerStatus(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis)
+	defer dt.Cleanup()
+	dt.RunDKG()
+	lastID := dt.ids[n-1]
+	dt.StopDrand(lastID)
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids[:n-1]...)
+
+	status := func() map[string]*drand.PeerStatus {
+		resp, err := dt.GetDrand(dt.ids[0]).PeerStatus(context.Background(), &drand.PeerStatusRequest{})
+		require.NoError(t, err)
+		peers := make(map[string]*drand.PeerStatus)
+		for _, p := range resp.GetPeers() {
+			peers[p.GetAddress()] = p
+		}
+		return peers
+	}
+	require.Eventually(t, func() bool {
+		return status()[lastID].GetFailures() > 0
+	}, 2*time.Second, 50*time.Millisecond)
+	peers := status()
+	require.Len(t, peers, n-1)
+	for _, id := range dt.ids[1 : n-1] {
+		require.Equal(t, net.PeerHealthy, peers[id].GetState())
+	}
+	require.NotEqual(t, net.PeerHealthy, peers[lastID].GetState())
+	require.NotEmpty(t, peers[lastID].GetLastError())
+}
+
+// gossipRecorder records the beacons gossiped to it
+type gossipRecorder struct {
+	*net.EmptyServer
+	packets chan *drand.GossipPacket
+}
+
+func (g *gossipRecorder) Beacon(c context.Context, p *drand.GossipPacket) (*drand.Empty, error) {
+	g.packets <- p
+	return new(drand.Empty), nil
+}
+
+func TestDrandGossip(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis)
+	defer dt.Cleanup()
+
+	// the relay uses the certificate of the first node, valid for any port
+	relayAddr := test.Addresses(1)[0]
+	recorder := &gossipRecorder{packets: make(chan *drand.GossipPacket, 10)}
+	certPath := dt.certPaths[0]
+	keyPath := strings.TrimSuffix(certPath, ".crt") + ".key"
+	relay, err := net.NewTLSGrpcListener(relayAddr, certPath, keyPath, recorder)
+	require.NoError(t, err)
+	go relay.Start()
+	defer relay.Stop()
+
+	dt.GetDrand(dt.ids[0]).opts.gossipPeers = []string{relayAddr}
+	dt.RunDKG()
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids...)
+
+	select {
+	case p := <-recorder.packets:
+		require.Equal(t, uint64(1), p.GetRound())
+		b, err := dt.GetBeacon(dt.ids[0], 1)
+		require.NoError(t, err)
+		require.Equal(t, b.Signature, p.GetSignature())
+	case <-time.After(5 * time.Second):
+		t.Fatal("beacon not gossiped to the relay")
+	}
+
+	// the other nodes do not gossip without peers
+	_, err = dt.GetDrand(dt.ids[1]).Beacon(context.Background(), &drand.GossipPacket{Round: 1})
+	require.Error(t, err)
+}
+
 func TestDrandDKGReshareTimeout(t *testing.T) {
 	oldN := 4
 	newN := 4
@@ -188,7 +316,7 @@ func (d *DrandTest) SetupReshare(keepOld, addNew, newThr int, transitionTime int
 	// add old participants
 	for _, id := range d.ids[:keepOld] {
 		drand := d.drands[id]
-		ids = append(ids, drand.priv.Public)
+		ids = append(ids, drand.identity)
 		for _, cp := range newCertPaths {
 			drand.opts.certmanager.Add(cp)
 		}
@@ -196,9 +324,9 @@ func (d *DrandTest) SetupReshare(keepOld, addNew, newThr int, transitionTime int
 	}
 	// add new participants
 	for i, drand := range newDrands {
-		ids = append(ids, drand.priv.Public)
-		newAddr[i] = drand.priv.Public.Address()
-		d.newDrands[drand.priv.Public.Address()] = drand
+		ids = append(ids, drand.identity)
+		newAddr[i] = drand.identity.Address()
+		d.newDrands[drand.identity.Address()] = drand
 		d.setClock(newAddr[i])
 		for _, cp := range d.certPaths {
 			drand.opts.certmanager.Add(cp)
@@ -224,9 +352,9 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) {
 		// instruct to be ready for a reshare
 		client, err := net.NewControlClient(dr.opts.controlPort)
 		require.NoError(d.t, err)
-		_, err = client.InitReshare(d.groupPath, d.newGroupPath, leader, timeout)
+		_, err = client.InitReshare(net.GroupFromPath(d.groupPath, ""), net.GroupFromPath(d.newGroupPath, ""), leader, timeout)
 		require.NoError(d.t, err)
-		fmt.Printf("\n\nDKG TEST: drand %s DONE RESHARING (leader? %v)\n", dr.priv.Public.Address(), leader)
+		fmt.Printf("\n\nDKG TEST: drand %s DONE RESHARING (leader? %v)\n", dr.identity.Address(), leader)
 		clientCounter.Done()
 	}
 
@@ -235,8 +363,8 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) {
 	var oldLeaving []string
 	for _, id := range d.ids {
 		drand := d.drands[id]
-		if d.newGroup.Contains(drand.priv.Public) {
-			oldNodes = append(oldNodes, drand.priv.Public.Address())
+		if d.newGroup.Contains(drand.identity) {
+			oldNodes = append(oldNodes, drand.identity.Address())
 		} else {
 			oldLeaving = append(oldLeaving, id)
 		}
@@ -258,7 +386,7 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) {
 	clientCounter.Add(oldRun - 1)
 	for _, id := range oldNodes[1:oldRun] {
 		dr := d.drands[id]
-		idx, found := d.newGroup.Index(dr.priv.Public)
+		idx, found := d.newGroup.Index(dr.identity)
 		if !found {
 			panic("old drand not found")
 		}
@@ -271,7 +399,7 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) {
 	clientCounter.Add(newRun)
 	for _, id := range d.newIds[:newRun] {
 		dr := d.newDrands[id]
-		idx, found := d.newGroup.Index(dr.priv.Public)
+		idx, found := d.newGroup.Index(dr.identity)
 		if !found {
 			panic("new drand not found")
 		}
@@ -284,7 +412,7 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) {
 	d.reshareIds = allIds
 	// run leader
 	leader := d.drands[oldNodes[0]]
-	idx, found := d.newGroup.Index(leader.priv.Public)
+	idx, found := d.newGroup.Index(leader.identity)
 	if !found {
 		panic("leader not found")
 	}
@@ -310,10 +438,9 @@ func checkWait(counter *sync.WaitGroup) {
 		panic("outdated beacon time")
 	}
 }
-func NewDrandTest(t *testing.T, n, thr int, period time.Duration, genesis int64) *DrandTest {
-	drands, group, dir, certPaths := BatchNewDrand(n, false,
-		WithCallOption(grpc.FailFast(true)),
-	)
+func NewDrandTest(t *testing.T, n, thr int, period time.Duration, genesis int64, opts ...ConfigOption) *DrandTest {
+	opts = append([]ConfigOption{WithCallOption(grpc.FailFast(true))}, opts...)
+	drands, group, dir, certPaths := BatchNewDrand(n, false, opts...)
 	group.Period = period
 	group.GenesisTime = genesis
 	groupPath := path.Join(dir, "dkggroup.toml")
@@ -322,7 +449,7 @@ func NewDrandTest(t *testing.T, n, thr int, period time.Duration, genesis int64)
 	ids := make([]string, n)
 	mDrands := make(map[string]*Drand, n)
 	for i, d := range drands {
-		ids[i] = d.priv.Public.Address()
+		ids[i] = d.identity.Address()
 		mDrands[ids[i]] = d
 	}
 	return &DrandTest{
@@ -349,17 +476,17 @@ func (d *DrandTest) RunDKG() {
 		go func(dd *Drand) {
 			client, err := net.NewControlClient(dd.opts.controlPort)
 			require.NoError(d.t, err)
-			_, err = client.InitDKG(d.groupPath, false, "", nil)
+			_, err = client.InitDKG(net.GroupFromPath(d.groupPath, ""), false, "", nil)
 			require.NoError(d.t, err)
 			wg.Done()
-			fmt.Printf("\n\n\n TESTDKG NON-ROOT %s FINISHED\n\n\n", dd.priv.Public.Address())
+			fmt.Printf("\n\n\n TESTDKG NON-ROOT %s FINISHED\n\n\n", dd.identity.Address())
 		}(d.drands[id])
 	}
 
 	root := d.drands[d.ids[0]]
 	controlClient, err := net.NewControlClient(root.opts.controlPort)
 	require.NoError(d.t, err)
-	_, err = controlClient.InitDKG(d.groupPath, true, "", nil)
+	_, err = controlClient.InitDKG(net.GroupFromPath(d.groupPath, ""), true, "", nil)
 	require.NoError(d.t, err)
 	wg.Wait()
 	fmt.Printf("\n\n\n TESTDKG ROOT %s FINISHED\n\n\n", d.ids[0])
@@ -373,7 +500,7 @@ func (d *DrandTest) RunDKG() {
 	d.group = group
 	require.Equal(d.t, d.thr, d.group.Threshold)
 	for _, drand := range d.drands {
-		require.True(d.t, d.group.Contains(drand.priv.Public))
+		require.True(d.t, d.group.Contains(drand.identity))
 	}
 	require.Len(d.t, d.group.PublicKey.Coefficients, d.thr)
 	require.NoError(d.t, key.Save(d.groupPath, d.group, false))
@@ -393,7 +520,7 @@ func (d *DrandTest) setClock(ids ...string) {
 	now := d.myClock.Now()
 	for _, id := range ids {
 		d.tryBoth(id, func(dr *Drand) {
-			addr := dr.priv.Public.Address()
+			addr := dr.identity.Address()
 			clock := clock.NewFakeClockAt(now)
 			dr.opts.clock = clock
 			dr.opts.dkgCallback = func(s *key.Share) {
@@ -411,7 +538,7 @@ func (d *DrandTest) setDKGCallback(ids []string) {
 		d.tryBoth(id, func(dr *Drand) {
 			dr.opts.dkgCallback = func(s *key.Share) {
 				d.Lock()
-				id := dr.priv.Public.Address()
+				id := dr.identity.Address()
 				d.shares[id] = s
 				d.Unlock()
 				//fmt.Printf("\n\nDKG DONE for %d - %s\n\n", dr.index, id)
@@ -430,16 +557,16 @@ func (d *DrandTest) StopDrand(id string) {
 	pinger, err := net.NewControlClient(dr.opts.controlPort)
 	require.NoError(d.t, err)
 	var counter = 1
-	fmt.Println(" DRAND ", dr.priv.Public.Address(), " TRYING TO PING")
+	fmt.Println(" DRAND ", dr.identity.Address(), " TRYING TO PING")
 	for range time.Tick(100 * time.Millisecond) {
 		if err := pinger.Ping(); err != nil {
-			fmt.Println(" DRAND ", dr.priv.Public.Address(), " TRYING TO PING DONE")
+			fmt.Println(" DRAND ", dr.identity.Address(), " TRYING TO PING DONE")
 			break
 		}
 		counter++
 		require.LessOrEqual(d.t, counter, 5)
 	}
-	fmt.Println(" DRAND ", dr.priv.Public.Address(), " STOPPED")
+	fmt.Println(" DRAND ", dr.identity.Address(), " STOPPED")
 }
 
 func (d *DrandTest) StartDrand(id string, catchup bool) {
@@ -484,7 +611,7 @@ func (d *DrandTest) TestBeaconLength(length int, ids ...string) {
 			drand.beacon.Store().Cursor(func(c beacon.Cursor) {
 				for b := c.First(); b != nil; b = c.Next() {
 					howMany++
-					fmt.Printf("\t %d - %s: beacon %s\n", drand.index, drand.priv.Public.Address(), b)
+					fmt.Printf("\t %d - %s: beacon %s\n", drand.index, drand.identity.Address(), b)
 				}
 			})
 			require.Equal(d.t, length, drand.beacon.Store().Len(), "id %s - howMany is %d vs Len() %d", id, howMany, drand.beacon.Store().Len())
@@ -496,7 +623,7 @@ func (d *DrandTest) TestBeaconLength(length int, ids ...string) {
 func (d *DrandTest) TestPublicBeacon(id string) {
 	dr := d.GetDrand(id)
 	client := net.NewGrpcClientFromCertManager(dr.opts.certmanager, dr.opts.grpcOpts...)
-	resp, err := client.PublicRand(test.NewTLSPeer(dr.priv.Public.Addr), &drand.PublicRandRequest{})
+	resp, err := client.PublicRand(test.NewTLSPeer(dr.identity.Addr), &drand.PublicRandRequest{})
 	require.NoError(d.t, err)
 	require.NotNil(d.t, resp)
 }
@@ -518,7 +645,7 @@ func TestDrandPublicGroup(t *testing.T) {
 	var group *drand.GroupResponse
 	for i, id := range dt.ids {
 		d := dt.drands[id]
-		groupResp, err := client.Group(d.priv.Public.Address(), d.priv.Public.TLS)
+		groupResp, err := client.Group(d.identity.Address(), d.identity.TLS)
 		require.NoError(t, err, fmt.Sprintf("idx %d: addr %s", i, id))
 		if group == nil {
 			group = groupResp
@@ -533,8 +660,8 @@ func TestDrandPublicGroup(t *testing.T) {
 			var found bool
 			for _, n := range nodes {
 				sameAddr := n.GetAddress() == addr
-				sameKey := n.GetKey() == key.PointToString(d.priv.Public.Key)
-				sameTLS := n.GetTLS() == d.priv.Public.TLS
+				sameKey := n.GetKey() == key.PointToString(d.identity.Key)
+				sameTLS := n.GetTLS() == d.identity.TLS
 				if sameAddr && sameKey && sameTLS {
 					found = true
 					break
@@ -542,7 +669,7 @@ func TestDrandPublicGroup(t *testing.T) {
 			}
 			require.True(t, found)
 		}
-		restGroup, err := rest.Group(d.priv.Public, &drand.GroupRequest{})
+		restGroup, err := rest.Group(d.identity, &drand.GroupRequest{})
 		require.NoError(t, err)
 		require.Equal(t, groupResp, restGroup)
 	}
@@ -604,6 +731,7 @@ func BatchNewDrand(n int, insecure bool, opts ...ConfigOption) ([]*Drand, *key.G
 			confOptions = append(confOptions, WithInsecure())
 		}
 		confOptions = append(confOptions, WithControlPort(ports[i]))
+		confOptions = append(confOptions, WithControlSocket(path.Join(dir, fmt.Sprintf("control-%d.sock", i))))
 		confOptions = append(confOptions, WithLogLevel(log.LogDebug))
 		// add options in last so it overwrites the default
 		confOptions = append(confOptions, opts...)
@@ -611,6 +739,9 @@ func BatchNewDrand(n int, insecure bool, opts ...ConfigOption) ([]*Drand, *key.G
 		if err != nil {
 			panic(err)
 		}
+		// the node may have bound its TLS key to its identity
+		idx, _ := group.Index(drands[i].identity)
+		group.Nodes[idx] = drands[i].identity
 	}
 	return drands, group, dir, certPaths
 }
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"google.golang.org/grpc"
+)
+
+// newGateway returns the gateway of the node listening on the given address
+// for the protocol methods, and for the public methods unless the config gives
+// them their own address.
+func (d *Drand) newGateway(addr string) (net.Gateway, error) {
+	c := d.opts
+	services := net.AllServices
+	if c.publicListenAddr != "" {
+		services = net.ProtocolServices
+	}
+	var g net.Gateway
+	var err error
+	var keyPair *net.KeyPairReloader
+	// REST config and limiter of the listener at the given address and of
+	// the public one, which gets the ones of the public methods unless they
+	// are served with the protocol ones
+	var opts []grpc.ServerOption
+	var restConf *net.RESTConfig
+	var limiter, publicLimiter *net.RateLimiter
+	rest := net.DefaultRESTConfig
+	rest.NextRound = d.nextRoundTime
+	if c.corsOrigins != nil {
+		rest.AllowedOrigins = c.corsOrigins
+	}
+	if c.rateLimit != nil {
+		d.log.Info("public_network", "rate-limit", "rate", c.rateLimit.Rate, "global_rate", c.rateLimit.GlobalRate, "max_streams", c.rateLimit.MaxStreams)
+		publicLimiter = net.NewRateLimiter(*c.rateLimit)
+	}
+	if c.publicListenAddr == "" {
+		restConf = &rest
+		limiter = publicLimiter
+	}
+	if c.insecure {
+		d.log.Info("network", "tls-disable")
+		g.Listener = net.NewTCPGrpcListenerFor(addr, d, services, restConf, limiter, opts...)
+		g.ProtocolClient = net.NewGrpcClient(c.grpcOpts...)
+	} else {
+		d.log.Info("network", "tls-enabled", "mutual", c.mutualTLS)
+		opts = append(opts, grpc.ConnectionTimeout(500*time.Millisecond))
+		keyPair, err = d.watchKeyPair(c.certPath, c.keyPath)
+		if err != nil {
+			return g, err
+		}
+		if c.mutualTLS {
+			trusted := c.certmanager
+			if trusted == nil {
+				trusted = net.NewCertManager()
+			}
+			g.Listener, err = net.NewMutualTLSGrpcListener(addr, keyPair, trusted, d.protocolPeers, d, services, restConf, limiter, opts...)
+		} else {
+			g.Listener, err = net.NewTLSGrpcListenerFor(addr, keyPair, d, services, restConf, limiter, opts...)
+		}
+		if err != nil {
+			return g, err
+		}
+		g.ProtocolClient = net.NewGrpcClientWithCertificate(c.certmanager, keyPair, c.grpcOpts...)
+	}
+	if c.publicListenAddr == "" {
+		return g, nil
+	}
+	switch {
+	case c.publicInsecure || (c.insecure && c.publicCertPath == "" && c.acme == nil):
+		d.log.Info("public_network", "tls-disable", "public_listen", c.publicListenAddr)
+		g.PublicListener = net.NewTCPGrpcListenerFor(c.publicListenAddr, d, net.PublicServices, &rest, publicLimiter)
+	default:
+		var certs net.CertSource = keyPair
+		if c.acme != nil {
+			d.log.Info("public_network", "acme", "domains", c.acme.Domains)
+			acme := *c.acme
+			acme.CacheDir = path.Join(c.ConfigFolder(), DefaultACMEFolder)
+			certs = net.NewACMECertSource(acme)
+		} else if c.publicCertPath != "" {
+			if certs, err = d.watchKeyPair(c.publicCertPath, c.publicKeyPath); err != nil {
+				g.Listener.Stop()
+				return g, err
+			}
+		}
+		d.log.Info("public_network", "tls-enabled", "public_listen", c.publicListenAddr)
+		g.PublicListener, err = net.NewTLSGrpcListenerFor(c.publicListenAddr, certs, d, net.PublicServices, &rest, publicLimiter)
+		if err != nil {
+			g.Listener.Stop()
+			return g, err
+		}
+	}
+	return g, nil
+}
+
+// nextRoundTime returns the time of the next round of the current group, the
+// zero time before the group is known
+func (d *Drand) nextRoundTime() time.Time {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.group == nil || d.group.GenesisTime == 0 {
+		return time.Time{}
+	}
+	_, next := beacon.NextRound(d.opts.clock.Now().Unix(), d.group.Period, d.group.GenesisTime)
+	return time.Unix(next, 0)
+}
+
+// watchKeyPair returns the key pair at the given paths, reloaded when its
+// files change and upon ReloadCertificates.
+func (d *Drand) watchKeyPair(certPath, keyPath string) (*net.KeyPairReloader, error) {
+	k, err := net.NewKeyPairReloader(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	k.Watch(DefaultCertReloadPeriod)
+	d.keyPairs = append(d.keyPairs, k)
+	return k, nil
+}
+
+// tlsKeyHash returns the hash of the public key of the TLS certificate of the
+// node, as bound to its identity.
+func (d *Drand) tlsKeyHash() ([]byte, error) {
+	cert, err := tls.LoadX509KeyPair(d.opts.certPath, d.opts.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	return key.TLSKeyHash(leaf), nil
+}
+
+// bindTLSKey binds the public key of the TLS certificate of the node to its
+// identity, signed with the longterm key, so the nodes requiring mutual TLS
+// can tell it apart from the other nodes of its host. The other nodes only
+// learn a new binding from the group file or from an address update.
+func (d *Drand) bindTLSKey() error {
+	hash, err := d.tlsKeyHash()
+	if err != nil {
+		return fmt.Errorf("drand: can't read TLS certificate: %s", err)
+	}
+	if bytes.Equal(hash, d.identity.TLSKeyHash) {
+		return nil
+	}
+	id := *d.identity
+	id.TLSKeyHash = hash
+	if err := id.SignWith(d.longterm); err != nil {
+		return fmt.Errorf("drand: can't sign identity: %s", err)
+	}
+	if err := d.store.SaveIdentity(&id); err != nil {
+		return fmt.Errorf("drand: can't save identity: %s", err)
+	}
+	d.log.Info("tls", "key bound to identity")
+	d.identity = &id
+	return nil
+}
+
+// ReloadCertificates loads again the TLS key pairs of the node from their
+// files, for example after their renewal.
+func (d *Drand) ReloadCertificates() error {
+	d.state.Lock()
+	defer d.state.Unlock()
+	for _, k := range d.keyPairs {
+		if err := k.Reload(); err != nil {
+			return err
+		}
+	}
+	d.log.Info("tls", "certificates reloaded", "key_pairs", len(d.keyPairs))
+	return nil
+}
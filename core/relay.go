@@ -0,0 +1,476 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/fs"
+	"github.com/drand/drand/gossip"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"google.golang.org/grpc/peer"
+)
+
+// DefaultRelayRetryPeriod is the time a relay waits before following the next
+// node when it lost the current one
+var DefaultRelayRetryPeriod = 1 * time.Second
+
+// Relay serves the randomness of a group without holding a share. It follows
+// the beacons of the nodes of the group, or of other relays, through SyncChain
+// and PublicRandStream, or through PublicRand when SyncChain is refused, as by
+// the nodes requiring mutual TLS or serving the public API on their own
+// listener. It verifies each of them with the distributed key of the
+// group and stores them to serve the public API. Relays scale the read traffic
+// horizontally without touching the group.
+type Relay struct {
+	*net.EmptyServer
+	opts   *Config
+	group  *key.Group
+	nodes  []net.Peer
+	store  beacon.Store
+	client net.Client
+	gossip *gossip.Handler
+	// streams of the public API
+	callbacks *callbackManager
+	listener  net.Listener
+	keyPair   *net.KeyPairReloader
+	log       log.Logger
+
+	// serializes the writes to the store
+	sync.Mutex
+	// serializes the syncs of the rounds missing before gossiped beacons
+	syncLock sync.Mutex
+	close    chan bool
+	stopOnce sync.Once
+	exitCh   chan bool
+}
+
+// NewRelay returns a relay of the group following the nodes or relays at the
+// given addresses. It uses the listen address, TLS, database, gossip and
+// public API options of the config.
+func NewRelay(group *key.Group, nodes []string, c *Config) (*Relay, error) {
+	if group.PublicKey == nil {
+		return nil, errors.New("relay: group without distributed key")
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("relay: no node to follow")
+	}
+	if c.listenAddr == "" {
+		return nil, errors.New("relay: no listen address")
+	}
+	fs.CreateSecureFolder(c.DBFolder())
+	store, err := beacon.NewBoltStore(c.dbFolder, c.boltOpts)
+	if err != nil {
+		return nil, err
+	}
+	// the chain starts from the genesis seed, as on the nodes
+	store.Put(&beacon.Beacon{Signature: group.GetGenesisSeed(), Round: 0})
+	r := &Relay{
+		opts:      c,
+		group:     group,
+		callbacks: newCallbackManager(),
+		log:       c.logger.With("module", "relay"),
+		close:     make(chan bool),
+		exitCh:    make(chan bool, 1),
+	}
+	r.store = beacon.NewCallbackStore(store, r.callbacks.NewBeacon)
+	if c.insecure {
+		r.client = net.NewGrpcClient(c.grpcOpts...)
+	} else {
+		r.client = net.NewGrpcClientFromCertManager(c.certmanager, c.grpcOpts...)
+	}
+	for _, addr := range nodes {
+		r.nodes = append(r.nodes, &peerAddr{addr, !c.insecure})
+	}
+	var peers []net.Peer
+	for _, addr := range c.gossipPeers {
+		peers = append(peers, &peerAddr{addr, !c.insecure})
+	}
+	r.gossip, err = gossip.NewHandler(r.client, &gossip.Config{
+		Group: group,
+		Peers: peers,
+		Clock: c.clock,
+	}, r.log)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	r.gossip.AddCallback(r.putGossiped)
+	if err := r.newListener(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// newListener creates the listener serving the public API of the relay, its
+// SyncChain method for the relays following it and the gossip service
+func (r *Relay) newListener() error {
+	c := r.opts
+	rest := net.DefaultRESTConfig
+	rest.NextRound = r.nextRoundTime
+	if c.corsOrigins != nil {
+		rest.AllowedOrigins = c.corsOrigins
+	}
+	var limiter *net.RateLimiter
+	if c.rateLimit != nil {
+		limiter = net.NewRateLimiter(*c.rateLimit)
+	}
+	if c.insecure {
+		r.log.Info("network", "tls-disable", "listen", c.listenAddr)
+		r.listener = net.NewTCPGrpcListenerFor(c.listenAddr, r, net.AllServices, &rest, limiter)
+		return nil
+	}
+	keyPair, err := net.NewKeyPairReloader(c.certPath, c.keyPath)
+	if err != nil {
+		return err
+	}
+	keyPair.Watch(DefaultCertReloadPeriod)
+	r.keyPair = keyPair
+	r.log.Info("network", "tls-enabled", "listen", c.listenAddr)
+	r.listener, err = net.NewTLSGrpcListenerFor(c.listenAddr, keyPair, r, net.AllServices, &rest, limiter)
+	if err != nil {
+		keyPair.Stop()
+	}
+	return err
+}
+
+// Start serves the public API and follows the nodes
+func (r *Relay) Start() {
+	go r.listener.Start()
+	go r.follow()
+}
+
+// Stop stops following the nodes and serving the public API
+func (r *Relay) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.close)
+		r.listener.Stop()
+		if r.keyPair != nil {
+			r.keyPair.Stop()
+		}
+		r.Lock()
+		r.store.Close()
+		r.Unlock()
+		r.exitCh <- true
+	})
+}
+
+// WaitExit returns a channel that signals when the relay stops
+func (r *Relay) WaitExit() chan bool {
+	return r.exitCh
+}
+
+// follow syncs the chain from one node and follows its new beacons until the
+// node is lost, then moves to the next node
+func (r *Relay) follow() {
+	for i := 0; ; i = (i + 1) % len(r.nodes) {
+		node := r.nodes[i]
+		if err := r.syncFrom(node); err != nil {
+			r.log.Error("sync_from", node.Address(), "err", err)
+		} else if err := r.streamFrom(node); err != nil {
+			r.log.Info("stream_from", node.Address(), "err", err)
+		}
+		select {
+		case <-r.close:
+			return
+		case <-time.After(DefaultRelayRetryPeriod):
+		}
+	}
+}
+
+// context returns a context canceled when the relay stops
+func (r *Relay) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-r.close:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// syncFrom stores the beacons the node has after the last one stored. The
+// rounds SyncChain did not send, if the node refused it, are fetched one by
+// one through the public API.
+func (r *Relay) syncFrom(node net.Peer) error {
+	if err := r.syncChainFrom(node); err != nil {
+		r.log.Debug("sync_chain", node.Address(), "err", err)
+	}
+	return r.syncPublicFrom(node)
+}
+
+// syncChainFrom stores the beacons sent by the node through SyncChain after
+// the last one stored
+func (r *Relay) syncChainFrom(node net.Peer) error {
+	last, err := r.store.Last()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := r.context()
+	defer cancel()
+	ch, err := r.client.SyncChain(ctx, node, &drand.SyncRequest{FromRound: last.Round + 1})
+	if err != nil {
+		return err
+	}
+	for reply := range ch {
+		b := &beacon.Beacon{
+			PreviousRound: reply.GetPreviousRound(),
+			PreviousSig:   reply.GetPreviousSig(),
+			Round:         reply.GetRound(),
+			Signature:     reply.GetSignature(),
+		}
+		if err := r.verifyAndPut(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncPublicFrom stores the beacons of the node after the last one stored up
+// to its latest one, requested by round through the public API. A round the
+// node can not send, as a round missing from its chain, is skipped: the next
+// beacon is stored only if it follows the last one stored.
+func (r *Relay) syncPublicFrom(node net.Peer) error {
+	latest, err := r.client.PublicRand(node, &drand.PublicRandRequest{})
+	if err != nil {
+		return err
+	}
+	last, err := r.store.Last()
+	if err != nil {
+		return err
+	}
+	var fetchErr error
+	for round := last.Round + 1; round <= latest.GetRound(); round++ {
+		select {
+		case <-r.close:
+			return errors.New("relay: stopped")
+		default:
+		}
+		resp := latest
+		if round < latest.GetRound() {
+			if resp, err = r.client.PublicRand(node, &drand.PublicRandRequest{Round: round}); err != nil {
+				fetchErr = err
+				continue
+			}
+		}
+		if err := r.verifyAndPut(beaconOfResponse(resp)); err != nil {
+			return err
+		}
+	}
+	if last, err = r.store.Last(); err != nil {
+		return err
+	}
+	if last.Round < latest.GetRound() {
+		return fmt.Errorf("relay: synced up to round %d of %d: %v", last.Round, latest.GetRound(), fetchErr)
+	}
+	return nil
+}
+
+// streamFrom stores the new beacons of the node until its stream ends
+func (r *Relay) streamFrom(node net.Peer) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	ch, err := r.client.PublicRandStream(ctx, node, &drand.PublicRandRequest{})
+	if err != nil {
+		return err
+	}
+	r.log.Info("follow", node.Address())
+	for resp := range ch {
+		last, err := r.store.Last()
+		if err != nil {
+			return err
+		}
+		// a beacon missed between the sync and the stream is synced first
+		if resp.GetPreviousRound() > last.Round {
+			if err := r.syncFrom(node); err != nil {
+				return err
+			}
+		}
+		if err := r.verifyAndPut(beaconOfResponse(resp)); err != nil {
+			return err
+		}
+	}
+	return errors.New("relay: stream closed")
+}
+
+func beaconOfResponse(resp *drand.PublicRandResponse) *beacon.Beacon {
+	return &beacon.Beacon{
+		PreviousRound: resp.GetPreviousRound(),
+		PreviousSig:   resp.GetPreviousSignature(),
+		Round:         resp.GetRound(),
+		Signature:     resp.GetSignature(),
+	}
+}
+
+// verifyAndPut stores the beacon if it is signed by the group
+func (r *Relay) verifyAndPut(b *beacon.Beacon) error {
+	if err := b.Verify(r.group); err != nil {
+		return fmt.Errorf("relay: invalid beacon for round %d: %s", b.Round, err)
+	}
+	r.put(b)
+	return nil
+}
+
+// putGossiped stores a beacon received from the gossip mesh. The rounds
+// missing between the last beacon stored and the gossiped one are synced from
+// the nodes first, so the chain of the relay has no hole.
+func (r *Relay) putGossiped(b *beacon.Beacon) {
+	last, err := r.store.Last()
+	if err != nil {
+		return
+	}
+	if b.PreviousRound > last.Round {
+		r.syncUpTo(b.PreviousRound)
+	}
+	r.put(b)
+}
+
+// syncUpTo syncs the chain from the nodes, in turn, until it reaches the
+// round
+func (r *Relay) syncUpTo(round uint64) {
+	r.syncLock.Lock()
+	defer r.syncLock.Unlock()
+	for _, node := range r.nodes {
+		last, err := r.store.Last()
+		if err != nil || last.Round >= round {
+			return
+		}
+		if err := r.syncFrom(node); err != nil {
+			r.log.Error("sync_from", node.Address(), "err", err)
+		}
+	}
+}
+
+// put stores a verified beacon more recent than the last one stored and
+// gossips it. A beacon following a round not stored yet is ignored, to not
+// leave a hole in the chain.
+func (r *Relay) put(b *beacon.Beacon) {
+	r.Lock()
+	defer r.Unlock()
+	select {
+	case <-r.close:
+		return
+	default:
+	}
+	last, err := r.store.Last()
+	if err != nil || b.Round <= last.Round {
+		return
+	}
+	if b.PreviousRound > last.Round {
+		r.log.Debug("relay_round", b.Round, "missing_round", b.PreviousRound, "last", last.Round)
+		return
+	}
+	if err := r.store.Put(b); err != nil {
+		r.log.Error("store", b.Round, "err", err)
+		return
+	}
+	r.log.Debug("relay_round", b.Round)
+	r.gossip.Publish(b)
+}
+
+// nextRoundTime returns the time of the next round of the group
+func (r *Relay) nextRoundTime() time.Time {
+	_, next := beacon.NextRound(r.opts.clock.Now().Unix(), r.group.Period, r.group.GenesisTime)
+	return time.Unix(next, 0)
+}
+
+// PublicRand returns the beacon of the requested round, the last one for the
+// round 0
+func (r *Relay) PublicRand(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	var b *beacon.Beacon
+	var err error
+	if in.GetRound() == 0 {
+		b, err = r.store.Last()
+	} else {
+		b, err = r.store.Get(in.GetRound())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't retrieve beacon: %s", err)
+	}
+	if b.Round == 0 {
+		return nil, errors.New("relay: no beacon relayed yet")
+	}
+	return r.response(b), nil
+}
+
+func (r *Relay) response(b *beacon.Beacon) *drand.PublicRandResponse {
+	return &drand.PublicRandResponse{
+		PreviousSignature: b.PreviousSig,
+		PreviousRound:     b.PreviousRound,
+		Round:             b.Round,
+		Signature:         b.Signature,
+		Randomness:        b.RandomnessWith(r.group.RandomnessHashFunc()),
+	}
+}
+
+// PublicRandStream sends the beacons relayed from the requested round, if
+// any, then the new ones until the client leaves
+func (r *Relay) PublicRandStream(req *drand.PublicRandRequest, stream drand.Public_PublicRandStreamServer) error {
+	p, _ := peer.FromContext(stream.Context())
+	return r.callbacks.streamBeacons(stream.Context(), p.Addr.String(), r.store, req.GetRound(), func(b *beacon.Beacon) error {
+		return stream.Send(r.response(b))
+	})
+}
+
+// PrivateRand is only served by the nodes of the group
+func (r *Relay) PrivateRand(context.Context, *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	return nil, errors.New("relay: private randomness is only served by the nodes of the group")
+}
+
+// DistKey returns the distributed key of the group
+func (r *Relay) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	buff, err := r.group.PublicKey.Key().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &drand.DistKeyResponse{
+		Key:    buff,
+		Scheme: r.group.PublicKey.Suite().Name,
+	}, nil
+}
+
+// Group returns the group relayed
+func (r *Relay) Group(context.Context, *drand.GroupRequest) (*drand.GroupResponse, error) {
+	return groupResponse(r.group), nil
+}
+
+// Home ...
+func (r *Relay) Home(context.Context, *drand.HomeRequest) (*drand.HomeResponse, error) {
+	return &drand.HomeResponse{
+		Status: fmt.Sprintf("drand relay up and running on %s", r.opts.listenAddr),
+	}, nil
+}
+
+// SyncChain sends the beacons stored from the requested round, for the
+// relays following this one
+func (r *Relay) SyncChain(req *drand.SyncRequest, stream drand.Protocol_SyncChainServer) error {
+	var err error
+	r.store.Cursor(func(c beacon.Cursor) {
+		for b := c.Seek(req.GetFromRound()); b != nil; b = c.Next() {
+			reply := &drand.SyncResponse{
+				PreviousRound: b.PreviousRound,
+				PreviousSig:   b.PreviousSig,
+				Round:         b.Round,
+				Signature:     b.Signature,
+			}
+			if err = stream.Send(reply); err != nil {
+				return
+			}
+		}
+	})
+	return err
+}
+
+// Beacon receives the beacons gossiped by the peers of the relay
+func (r *Relay) Beacon(c context.Context, in *drand.GossipPacket) (*drand.Empty, error) {
+	return r.gossip.ProcessGossip(c, in)
+}
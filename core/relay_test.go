@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelay(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis)
+	defer dt.Cleanup()
+	dt.RunDKG()
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids...)
+
+	folder, err := ioutil.TempDir("", "drand-relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(folder)
+	// the relay uses the certificate of the first node, valid for any port
+	relayAddr := test.Addresses(1)[0]
+	certPath := dt.certPaths[0]
+	keyPath := strings.TrimSuffix(certPath, ".crt") + ".key"
+	conf := NewConfig(WithConfigFolder(folder), WithListenAddress(relayAddr),
+		WithTLS(certPath, keyPath), WithTrustedCerts(dt.certPaths...))
+	conf.clock = dt.myClock
+
+	_, err = NewRelay(dt.group, nil, conf)
+	require.Error(t, err)
+	// the relay follows the second node when the first one is down
+	dt.StopDrand(dt.ids[0])
+	relay, err := NewRelay(dt.group, []string{dt.ids[0], dt.ids[1]}, conf)
+	require.NoError(t, err)
+	relay.Start()
+	defer relay.Stop()
+
+	client := net.NewGrpcClientFromCertManager(conf.certmanager)
+	relayPeer := test.NewTLSPeer(relayAddr)
+	waitRound := func(round uint64) *drand.PublicRandResponse {
+		var resp *drand.PublicRandResponse
+		for i := 0; i < 50; i++ {
+			resp, err = client.PublicRand(relayPeer, &drand.PublicRandRequest{})
+			if err == nil && resp.GetRound() >= round {
+				return resp
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("relay did not reach round %d: %v", round, err)
+		return nil
+	}
+
+	// the chain is synced from the node
+	resp := waitRound(1)
+	b, err := dt.GetBeacon(dt.ids[1], 1)
+	require.NoError(t, err)
+	require.Equal(t, b.Signature, resp.GetSignature())
+
+	// and the new beacons follow its stream
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.PublicRandStream(ctx, relayPeer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	dt.MoveTime(beaconPeriod)
+	resp = waitRound(2)
+	b, err = dt.GetBeacon(dt.ids[1], 2)
+	require.NoError(t, err)
+	require.Equal(t, b.Signature, resp.GetSignature())
+	select {
+	case streamed := <-stream:
+		require.Equal(t, uint64(2), streamed.GetRound())
+	case <-time.After(5 * time.Second):
+		t.Fatal("beacon not streamed by the relay")
+	}
+
+	// a stream from a round starts with the beacons stored from this round,
+	// on the relay as on the nodes
+	for _, addr := range []string{relayAddr, dt.ids[1]} {
+		replay, err := client.PublicRandStream(ctx, test.NewTLSPeer(addr), &drand.PublicRandRequest{Round: 1})
+		require.NoError(t, err)
+		for round := uint64(1); round <= 2; round++ {
+			select {
+			case streamed := <-replay:
+				require.Equal(t, round, streamed.GetRound())
+			case <-time.After(5 * time.Second):
+				t.Fatalf("round %d not replayed by %s", round, addr)
+			}
+		}
+	}
+
+	// the relay serves the public information of the group, not private randomness
+	distKey, err := client.DistKey(relayPeer, &drand.DistKeyRequest{})
+	require.NoError(t, err)
+	expected, err := dt.group.PublicKey.Key().MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, expected, distKey.GetKey())
+	_, err = relay.PrivateRand(context.Background(), &drand.PrivateRandRequest{})
+	require.Error(t, err)
+
+	// a gossiped beacon ahead of the chain of a relay is stored only after
+	// the rounds missing before it, synced from the nodes
+	folder2, err := ioutil.TempDir("", "drand-relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(folder2)
+	conf2 := NewConfig(WithConfigFolder(folder2), WithListenAddress(test.Addresses(1)[0]),
+		WithTLS(certPath, keyPath), WithTrustedCerts(dt.certPaths...))
+	conf2.clock = dt.myClock
+	behind, err := NewRelay(dt.group, []string{dt.ids[1]}, conf2)
+	require.NoError(t, err)
+	defer behind.Stop()
+	b, err = dt.GetBeacon(dt.ids[1], 2)
+	require.NoError(t, err)
+	behind.put(b)
+	last, err := behind.store.Last()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), last.Round)
+	behind.putGossiped(b)
+	for round := uint64(1); round <= 2; round++ {
+		stored, err := behind.store.Get(round)
+		require.NoError(t, err)
+		expected, err := dt.GetBeacon(dt.ids[1], int(round))
+		require.NoError(t, err)
+		require.True(t, expected.Equal(stored))
+	}
+}
+
+func TestRelayPublicListener(t *testing.T) {
+	n := 4
+	beaconPeriod := 1 * time.Second
+	var offsetGenesis = 1 * time.Second
+	genesis := clock.NewFakeClock().Now().Add(offsetGenesis).Unix()
+	dt := NewDrandTest(t, n, key.DefaultThreshold(n), beaconPeriod, genesis)
+	defer dt.Cleanup()
+	// the second node serves the public API on its own listener, which does
+	// not serve SyncChain
+	publicAddr := test.Addresses(1)[0]
+	node := dt.drands[dt.ids[1]]
+	node.gateway.StopAll()
+	node.opts.publicListenAddr = publicAddr
+	var err error
+	node.gateway, err = node.newGateway(dt.ids[1])
+	require.NoError(t, err)
+	node.gateway.StartAll()
+	dt.RunDKG()
+	dt.MoveTime(offsetGenesis)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.MoveTime(beaconPeriod)
+	dt.TestBeaconLength(3, dt.ids...)
+
+	folder, err := ioutil.TempDir("", "drand-relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(folder)
+	relayAddr := test.Addresses(1)[0]
+	certPath := dt.certPaths[0]
+	keyPath := strings.TrimSuffix(certPath, ".crt") + ".key"
+	conf := NewConfig(WithConfigFolder(folder), WithListenAddress(relayAddr),
+		WithTLS(certPath, keyPath), WithTrustedCerts(dt.certPaths...))
+	conf.clock = dt.myClock
+	relay, err := NewRelay(dt.group, []string{publicAddr}, conf)
+	require.NoError(t, err)
+	relay.Start()
+	defer relay.Stop()
+
+	client := net.NewGrpcClientFromCertManager(conf.certmanager)
+	relayPeer := test.NewTLSPeer(relayAddr)
+	waitRound := func(round uint64) *drand.PublicRandResponse {
+		var resp *drand.PublicRandResponse
+		for i := 0; i < 50; i++ {
+			resp, err = client.PublicRand(relayPeer, &drand.PublicRandRequest{})
+			if err == nil && resp.GetRound() >= round {
+				return resp
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("relay did not reach round %d: %v", round, err)
+		return nil
+	}
+
+	// the chain is synced round by round through the public API
+	waitRound(2)
+	for round := uint64(1); round <= 2; round++ {
+		stored, err := relay.store.Get(round)
+		require.NoError(t, err)
+		expected, err := dt.GetBeacon(dt.ids[1], int(round))
+		require.NoError(t, err)
+		require.True(t, expected.Equal(stored))
+	}
+
+	// and the new beacons follow the public stream
+	dt.MoveTime(beaconPeriod)
+	resp := waitRound(3)
+	b, err := dt.GetBeacon(dt.ids[1], 3)
+	require.NoError(t, err)
+	require.Equal(t, b.Signature, resp.GetSignature())
+}
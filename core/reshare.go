@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	control "github.com/drand/drand/protobuf/drand"
+	vss "github.com/drand/kyber/share/vss/pedersen"
+)
+
+// checkReshare diffs the old and new group of a resharing and validates that
+// the resharing can happen safely at the given time, with a DKG lasting at
+// most the given timeout.
+func checkReshare(oldGroup, newGroup *key.Group, now int64, timeout time.Duration) *control.ReshareDryRunResponse {
+	resp := &control.ReshareDryRunResponse{
+		OldThreshold: uint32(oldGroup.Threshold),
+		NewThreshold: uint32(newGroup.Threshold),
+	}
+	problem := func(format string, args ...interface{}) {
+		resp.Problems = append(resp.Problems, fmt.Sprintf(format, args...))
+	}
+	warning := func(format string, args ...interface{}) {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	rotated, err := key.ApplyRotations(oldGroup, newGroup)
+	if err != nil {
+		problem("invalid key rotation: %s", err)
+		rotated = oldGroup
+	}
+	for i, id := range rotated.Nodes {
+		if !id.Equal(oldGroup.Nodes[i]) {
+			warning("node %s rotates its key", id.Address())
+		}
+	}
+	for _, id := range rotated.Nodes {
+		if idx, ok := newGroup.Index(id); ok {
+			resp.Staying = append(resp.Staying, toNode(id))
+			if newGroup.Nodes[idx].Address() != id.Address() {
+				warning("node %s changes its address to %s", id.Address(), newGroup.Nodes[idx].Address())
+			}
+		} else {
+			resp.Leaving = append(resp.Leaving, toNode(id))
+		}
+	}
+	for _, id := range newGroup.Nodes {
+		if !rotated.Contains(id) {
+			resp.Joining = append(resp.Joining, toNode(id))
+		}
+	}
+
+	if oldGroup.GenesisTime != newGroup.GenesisTime {
+		problem("old and new group have different genesis time")
+	}
+	if oldGroup.GenesisTime > now {
+		problem("genesis time is in the future")
+	}
+	if oldGroup.Unchained != newGroup.Unchained {
+		problem("old and new group have different chaining modes")
+	}
+	if oldGroup.RandomnessHashName() != newGroup.RandomnessHashName() {
+		problem("old and new group have different randomness hashes")
+	}
+	if oldGroup.Period != newGroup.Period {
+		problem("old and new group have different period - unsupported feature at the moment")
+	}
+
+	// security constraints on the new group
+	newN := newGroup.Len()
+	if newN < 4 {
+		problem("new group has fewer than 4 members")
+	}
+	if newGroup.Threshold < vss.MinimumT(newN) {
+		problem("new threshold %d is lower than the minimum threshold %d for %d nodes", newGroup.Threshold, vss.MinimumT(newN), newN)
+	}
+	if newGroup.Threshold > newN {
+		problem("new threshold %d is greater than the number of nodes %d", newGroup.Threshold, newN)
+	}
+	if newGroup.Threshold < oldGroup.Threshold {
+		warning("threshold decreases from %d to %d", oldGroup.Threshold, newGroup.Threshold)
+	}
+	if len(resp.Staying) == 0 {
+		warning("no node of the old group is present in the new group")
+	}
+
+	// transition time
+	if newGroup.TransitionTime == 0 {
+		problem("new group has no transition time")
+		return resp
+	}
+	// same computation as the beacon does at transition time
+	tRound, tTime := beacon.NextRound(newGroup.TransitionTime, newGroup.Period, newGroup.GenesisTime)
+	resp.TransitionRound = tRound - 1
+	resp.TransitionTime = tTime - int64(newGroup.Period.Seconds())
+	if resp.TransitionTime != newGroup.TransitionTime {
+		problem("transition time %d is not the time of a round, closest round %d starts at %d", newGroup.TransitionTime, resp.TransitionRound, resp.TransitionTime)
+	}
+	if newGroup.TransitionTime < now {
+		problem("transition time is in the past")
+	} else if newGroup.TransitionTime < now+int64(timeout.Seconds()) {
+		problem("transition time happens before the end of the resharing timeout of %s", timeout)
+	}
+	return resp
+}
+
+// sameGroup returns true if both groups have the same hash
+func sameGroup(g1, g2 *key.Group) bool {
+	h1, err := g1.Hash()
+	if err != nil {
+		return false
+	}
+	h2, err := g2.Hash()
+	if err != nil {
+		return false
+	}
+	return h1 == h2
+}
+
+func toNode(id *key.Identity) *control.Node {
+	return &control.Node{
+		Address:   id.Address(),
+		Key:       key.PointToString(id.Key),
+		TLS:       id.IsTLS(),
+		Addresses: id.Addresses,
+		Metadata:  metadataToProto(id.Metadata),
+	}
+}
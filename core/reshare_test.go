@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReshare(t *testing.T) {
+	period := 10 * time.Second
+	genesis := int64(1000)
+	now := genesis + 100
+	ids := test.ListFromPrivates(test.GenerateIDs(7))
+	oldGroup := key.NewGroup(ids[:5], 4, genesis)
+	oldGroup.Period = period
+	// 2 nodes leave, 2 nodes join
+	newGroup := key.NewGroup(append(ids[2:5:5], ids[5:]...), 3, genesis)
+	newGroup.Period = period
+	newGroup.TransitionTime = genesis + 20*int64(period.Seconds())
+
+	resp := checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Empty(t, resp.Problems)
+	require.Len(t, resp.Staying, 3)
+	require.Len(t, resp.Leaving, 2)
+	require.Len(t, resp.Joining, 2)
+	require.Equal(t, uint32(4), resp.OldThreshold)
+	require.Equal(t, uint32(3), resp.NewThreshold)
+	require.Equal(t, newGroup.TransitionTime, resp.TransitionTime)
+	require.Equal(t, uint64(21), resp.TransitionRound)
+	// threshold decreases
+	require.Len(t, resp.Warnings, 1)
+
+	// transition not aligned on a round
+	newGroup.TransitionTime++
+	resp = checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Len(t, resp.Problems, 1)
+
+	// transition happening before the end of the DKG
+	newGroup.TransitionTime = genesis + 11*int64(period.Seconds())
+	resp = checkReshare(oldGroup, newGroup, now, 5*time.Minute)
+	require.Len(t, resp.Problems, 1)
+
+	// unsafe threshold and different period
+	newGroup.TransitionTime = genesis + 20*int64(period.Seconds())
+	newGroup.Threshold = 2
+	newGroup.Period = 2 * period
+	resp = checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Len(t, resp.Problems, 2)
+
+	// the default randomness hash is the same whether it is explicit or not
+	newGroup.Threshold = 3
+	newGroup.Period = period
+	newGroup.RandomnessHash = key.DefaultRandomnessHash
+	resp = checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Empty(t, resp.Problems)
+	newGroup.RandomnessHash = key.HashBLAKE2b
+	resp = checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Len(t, resp.Problems, 1)
+
+	// a node rotating its key stays in the group
+	oldPriv := test.GenerateIDs(5)
+	oldGroup = key.NewGroup(test.ListFromPrivates(oldPriv), 4, genesis)
+	oldGroup.Period = period
+	rotated, err := key.RotateKeyPair(oldPriv[0])
+	require.NoError(t, err)
+	ids = append([]*key.Identity{rotated.Public}, test.ListFromPrivates(oldPriv[1:])...)
+	newGroup = key.NewGroup(ids, 4, genesis)
+	newGroup.Period = period
+	newGroup.TransitionTime = genesis + 20*int64(period.Seconds())
+	resp = checkReshare(oldGroup, newGroup, now, time.Minute)
+	require.Empty(t, resp.Problems)
+	require.Len(t, resp.Staying, 5)
+	require.Empty(t, resp.Leaving)
+	require.Empty(t, resp.Joining)
+	require.Len(t, resp.Warnings, 1)
+}
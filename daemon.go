@@ -3,15 +3,17 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/drand/drand/core"
-	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
 	"github.com/urfave/cli/v2"
 )
 
 func startCmd(c *cli.Context) error {
 	conf := contextToConfig(c)
-	fs := key.NewFileStore(conf.ConfigFolder())
+	fs := keyStore(c, conf)
 	var drand *core.Drand
 	// determine if we already ran a DKG or not
 	_, errG := fs.LoadGroup()
@@ -41,11 +43,43 @@ func startCmd(c *cli.Context) error {
 		catchup := true
 		drand.StartBeacon(catchup)
 	}
+	go reloadOnHangup(drand, conf.Logger())
 	<-drand.WaitExit()
 
 	return nil
 }
 
+// reloadOnHangup reloads the TLS certificates of the daemon each time it
+// receives SIGHUP, for example from the renewal hook of certbot.
+func reloadOnHangup(drand *core.Drand, l log.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		if err := drand.ReloadCertificates(); err != nil {
+			l.Error("tls", "reload failed", "err", err)
+		}
+	}
+}
+
+func relayCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatal("drand: relay requires the group file as argument")
+	}
+	if !c.IsSet(followFlag.Name) {
+		fatal("drand: relay requires the nodes to follow with --%s", followFlag.Name)
+	}
+	group := getGroup(c)
+	conf := contextToConfig(c)
+	relay, err := core.NewRelay(group, c.StringSlice(followFlag.Name), conf)
+	if err != nil {
+		fatal("drand: can't start relay: %s", err)
+	}
+	fmt.Println("drand: relaying the randomness of the group")
+	relay.Start()
+	<-relay.WaitExit()
+	return nil
+}
+
 func stopDaemon(c *cli.Context) error {
 	client := controlClient(c)
 	if _, err := client.Shutdown(); err != nil {
@@ -74,7 +74,6 @@ func (n *Node) setup() {
 	}()
 
 	// call drand binary
-	n.priv = key.NewKeyPair(fullAddr)
 	newKey := exec.Command("drand", "generate-keypair", "--folder", n.base, fullAddr)
 	runCommand(newKey)
 
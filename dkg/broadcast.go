@@ -0,0 +1,242 @@
+package dkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	dkg_proto "github.com/drand/drand/protobuf/crypto/dkg"
+	vss "github.com/drand/kyber/share/vss/pedersen"
+	"github.com/drand/kyber/sign/schnorr"
+	"github.com/golang/protobuf/proto"
+)
+
+// echoBroadcast implements a reliable broadcast channel for the responses and
+// justifications of the DKG, on top of the Network used by the Handler. Each
+// node of the new group echoes, to every other nodes, the first packet it sees
+// for a given "slot" (i.e. the response of a verifier for a dealer or the
+// justification of a dealer for a verifier). A packet is only delivered to the
+// DKG state machine once a quorum of nodes of the new group echoed the same
+// packet for this slot. That way, a node sending different packets to
+// different nodes can not make honest nodes process different packets.
+// All methods must be called with the lock of the Handler held.
+type echoBroadcast struct {
+	h *Handler
+	// number of matching echoes needed before delivering a packet
+	quorum int
+	// slot -> true if this node already echoed a packet for this slot
+	echoed map[string]bool
+	// slot -> hash of the packet -> index of the echoing nodes
+	echoes map[string]map[string]map[uint32]bool
+	// hash of the packet -> packet
+	packets map[string]*dkg_proto.Packet
+	// slot -> hash of the packet delivered for this slot
+	delivered map[string]string
+}
+
+func newEchoBroadcast(h *Handler) *echoBroadcast {
+	return &echoBroadcast{
+		h:         h,
+		quorum:    echoQuorum(h.conf.NewNodes.Len(), h.conf.NewNodes.Threshold),
+		echoed:    make(map[string]bool),
+		echoes:    make(map[string]map[string]map[uint32]bool),
+		packets:   make(map[string]*dkg_proto.Packet),
+		delivered: make(map[string]string),
+	}
+}
+
+// echoQuorum returns the number of matching echoes required to deliver a
+// packet. With at most n - thr malicious nodes, two different packets can not
+// both gather that many echoes for the same slot. Note the quorum can be
+// higher than the threshold, e.g. 4 out of 5 nodes with a threshold of 3, so
+// the DKG needs more nodes online to finish than without the echo broadcast.
+func echoQuorum(n, thr int) int {
+	f := n - thr
+	return (n+f)/2 + 1
+}
+
+// broadcast sends a packet issued by this node to all the other nodes.
+func (e *echoBroadcast) broadcast(p *dkg_proto.Packet, msgType string) {
+	if !e.h.newNode {
+		// only nodes of the new group echo packets, so the other nodes simply
+		// send the packet and let the new group echo it.
+		go e.h.broadcast(p, true, msgType)
+		return
+	}
+	slot, hash, err := packetSlot(p)
+	if err != nil {
+		e.h.l.Error("echo_broadcast", err)
+		return
+	}
+	e.echo(p, slot, hash)
+}
+
+// process handles an incoming response, justification or echo and returns the
+// packets that are ready to be delivered to the DKG state machine.
+func (e *echoBroadcast) process(p *dkg_proto.Packet) []*dkg_proto.Packet {
+	var echoer = -1
+	var signature []byte
+	if p.Echo != nil {
+		if p.Echo.Packet == nil {
+			e.h.l.Error("echo", "empty packet")
+			return nil
+		}
+		if int(p.Echo.Index) >= e.h.conf.NewNodes.Len() {
+			e.h.l.Error("echo", "invalid index", "index", p.Echo.Index)
+			return nil
+		}
+		echoer = int(p.Echo.Index)
+		signature = p.Echo.Signature
+		p = p.Echo.Packet
+	}
+	slot, hash, err := packetSlot(p)
+	if err != nil {
+		e.h.l.Error("echo", err)
+		return nil
+	}
+	if echoer >= 0 {
+		pub := e.h.conf.NewNodes.Public(echoer).Key
+		if err := schnorr.Verify(e.h.conf.Suite, pub, []byte(hash), signature); err != nil {
+			e.h.l.Error("echo", "invalid signature", "index", echoer)
+			return nil
+		}
+	}
+	if _, ok := e.packets[hash]; !ok {
+		// a packet is only echoed and counted once its issuer is known to
+		// have signed it, otherwise any node could fill the slot of another
+		if err := e.verifyIssuer(p); err != nil {
+			e.h.l.Error("echo", err, "slot", slot)
+			return nil
+		}
+		e.packets[hash] = p
+	}
+	if echoer >= 0 {
+		e.addEcho(uint32(echoer), slot, hash)
+	}
+	if e.h.newNode && !e.echoed[slot] {
+		e.echo(p, slot, hash)
+	}
+	return e.deliver(slot)
+}
+
+// verifyIssuer checks the signature of the response or justification against
+// the longterm key of the node that issued it: the verifier for a response and
+// the dealer for a justification.
+func (e *echoBroadcast) verifyIssuer(p *dkg_proto.Packet) error {
+	conf := e.h.conf
+	switch {
+	case p.Response != nil:
+		r := p.Response.Response
+		if int(r.Index) >= conf.NewNodes.Len() {
+			return fmt.Errorf("dkg: invalid verifier index %d", r.Index)
+		}
+		resp := &vss.Response{
+			SessionID: r.SessionId,
+			Index:     r.Index,
+			Status:    r.Status,
+		}
+		pub := conf.NewNodes.Public(int(r.Index)).Key
+		return schnorr.Verify(conf.Suite, pub, resp.Hash(conf.Suite), r.Signature)
+	case p.Justification != nil:
+		dealers := conf.NewNodes
+		if conf.OldNodes != nil {
+			dealers = conf.OldNodes
+		}
+		if int(p.Justification.Index) >= dealers.Len() {
+			return fmt.Errorf("dkg: invalid dealer index %d", p.Justification.Index)
+		}
+		j, err := justificationFromProto(conf.Suite, p.Justification)
+		if err != nil {
+			return err
+		}
+		pub := dealers.Public(int(p.Justification.Index)).Key
+		return schnorr.Verify(conf.Suite, pub, j.Justification.Hash(conf.Suite), j.Justification.Signature)
+	}
+	return errors.New("dkg: only responses and justifications can be echoed")
+}
+
+// addEcho records the echo of the given node for the slot and logs any node
+// echoing two different packets for the same slot.
+func (e *echoBroadcast) addEcho(idx uint32, slot, hash string) {
+	hashes, ok := e.echoes[slot]
+	if !ok {
+		hashes = make(map[string]map[uint32]bool)
+		e.echoes[slot] = hashes
+	}
+	for h, echoers := range hashes {
+		if h != hash && echoers[idx] {
+			e.h.l.Error("echo", "equivocation", "index", idx, "slot", slot)
+		}
+	}
+	if _, ok := hashes[hash]; !ok {
+		hashes[hash] = make(map[uint32]bool)
+	}
+	hashes[hash][idx] = true
+}
+
+// deliver returns the packet to process for the slot if it has gathered enough
+// echoes and has not been delivered yet.
+func (e *echoBroadcast) deliver(slot string) []*dkg_proto.Packet {
+	if _, done := e.delivered[slot]; done {
+		return nil
+	}
+	for hash, echoers := range e.echoes[slot] {
+		if len(echoers) < e.quorum {
+			continue
+		}
+		e.delivered[slot] = hash
+		p := e.packets[hash]
+		if e.h.isIssuer(p) {
+			// our own packet has already been taken into account
+			return nil
+		}
+		return []*dkg_proto.Packet{p}
+	}
+	return nil
+}
+
+// echo signs and sends the echo of the packet to all other nodes, and records
+// it as our own echo.
+func (e *echoBroadcast) echo(p *dkg_proto.Packet, slot, hash string) {
+	sig, err := e.h.signer.Sign([]byte(hash))
+	if err != nil {
+		e.h.l.Error("echo", err)
+		return
+	}
+	e.echoed[slot] = true
+	e.packets[hash] = p
+	e.addEcho(uint32(e.h.nidx), slot, hash)
+	packet := &dkg_proto.Packet{
+		Echo: &dkg_proto.Echo{
+			Index:     uint32(e.h.nidx),
+			Packet:    p,
+			Signature: sig,
+		},
+	}
+	go e.h.broadcast(packet, true, "echo")
+}
+
+// packetSlot returns the identifier of the slot of the given response or
+// justification as well as the hex encoded hash of the packet.
+func packetSlot(p *dkg_proto.Packet) (string, string, error) {
+	var slot string
+	switch {
+	case p.Response != nil && p.Response.Response != nil:
+		r := p.Response.Response
+		slot = fmt.Sprintf("response-%x-%d-%d", r.SessionId, p.Response.Index, r.Index)
+		p = &dkg_proto.Packet{Response: p.Response}
+	case p.Justification != nil && p.Justification.Justification != nil:
+		j := p.Justification.Justification
+		slot = fmt.Sprintf("justification-%x-%d-%d", j.SessionId, p.Justification.Index, j.Index)
+		p = &dkg_proto.Packet{Justification: p.Justification}
+	default:
+		return "", "", errors.New("dkg: only responses and justifications can be echoed")
+	}
+	buff, err := proto.Marshal(p)
+	if err != nil {
+		return "", "", err
+	}
+	h := sha256.Sum256(buff)
+	return slot, hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,297 @@
+package dkg
+
+import (
+	"context"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	dkg_proto "github.com/drand/drand/protobuf/crypto/dkg"
+	vss_proto "github.com/drand/drand/protobuf/crypto/vss"
+	"github.com/drand/drand/test"
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	vss "github.com/drand/kyber/share/vss/pedersen"
+	"github.com/drand/kyber/sign/schnorr"
+	"github.com/drand/kyber/util/random"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+// memNet delivers packets directly to the handlers in memory
+type memNet struct {
+	handlers map[string]*Handler
+}
+
+func (m *memNet) Send(p net.Peer, packet *dkg_proto.Packet) error {
+	h, ok := m.handlers[p.Address()]
+	if !ok {
+		return nil
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &gonet.TCPAddr{}})
+	h.Process(ctx, packet)
+	return nil
+}
+
+func newEchoHandlers(t *testing.T, n, thr int) ([]*key.Pair, []*Handler, *memNet) {
+	privs := test.GenerateIDs(n)
+	group := key.NewGroup(test.ListFromPrivates(privs), thr, 0)
+	mem := &memNet{handlers: make(map[string]*Handler)}
+	handlers := make([]*Handler, n)
+	// the private keys are re-ordered according to the group
+	sorted := make([]*key.Pair, n)
+	for _, p := range privs {
+		conf := &Config{
+			Suite:         key.KeyGroup.(Suite),
+			NewNodes:      group,
+			Public:        p.Public,
+			Longterm:      p,
+			Clock:         clock.NewFakeClock(),
+			EchoBroadcast: true,
+		}
+		h, err := NewHandler(mem, conf, log.DefaultLogger)
+		require.NoError(t, err)
+		// the group sorts the nodes so we index handlers by their group index
+		idx, ok := group.Index(p.Public)
+		require.True(t, ok)
+		handlers[idx] = h
+		sorted[idx] = p
+		mem.handlers[p.Public.Address()] = h
+	}
+	return sorted, handlers, mem
+}
+
+// fakeEcho returns an echo from the given node of a response issued by this
+// node for the given dealer.
+func fakeEcho(t *testing.T, priv *key.Pair, idx, dealer uint32, status bool) *dkg_proto.Packet {
+	suite := key.KeyGroup.(Suite)
+	resp := &vss.Response{
+		SessionID: []byte("session"),
+		Index:     idx,
+		Status:    status,
+	}
+	sig, err := schnorr.Sign(suite, priv.Key, resp.Hash(suite))
+	require.NoError(t, err)
+	p := &dkg_proto.Packet{
+		Response: &dkg_proto.Response{
+			Index: dealer,
+			Response: &vss_proto.Response{
+				SessionId: resp.SessionID,
+				Index:     resp.Index,
+				Status:    resp.Status,
+				Signature: sig,
+			},
+		},
+	}
+	return echoOf(t, priv, idx, p)
+}
+
+// fakeJustification returns a justification signed by the given dealer for
+// the given verifier, revealing a random share.
+func fakeJustification(t *testing.T, dealer *key.Pair, dealerIdx, verifier uint32) *dkg_proto.Packet {
+	suite := key.KeyGroup.(Suite)
+	j := &vss.Justification{
+		SessionID: []byte("session"),
+		Index:     verifier,
+		Deal: &vss.Deal{
+			SessionID:   []byte("session"),
+			SecShare:    &share.PriShare{I: int(verifier), V: suite.Scalar().Pick(random.New())},
+			T:           3,
+			Commitments: []kyber.Point{suite.Point().Pick(random.New())},
+		},
+	}
+	sig, err := schnorr.Sign(suite, dealer.Key, j.Hash(suite))
+	require.NoError(t, err)
+	deal, err := dealToProto(j.Deal)
+	require.NoError(t, err)
+	return &dkg_proto.Packet{
+		Justification: &dkg_proto.Justification{
+			Index: dealerIdx,
+			Justification: &vss_proto.Justification{
+				SessionId: j.SessionID,
+				Index:     j.Index,
+				Deal:      deal,
+				Signature: sig,
+			},
+		},
+	}
+}
+
+// echoOf returns the echo of the packet signed by the given node
+func echoOf(t *testing.T, priv *key.Pair, idx uint32, p *dkg_proto.Packet) *dkg_proto.Packet {
+	_, hash, err := packetSlot(p)
+	require.NoError(t, err)
+	sig, err := schnorr.Sign(key.KeyGroup.(Suite), priv.Key, []byte(hash))
+	require.NoError(t, err)
+	return &dkg_proto.Packet{
+		Echo: &dkg_proto.Echo{
+			Index:     idx,
+			Packet:    p,
+			Signature: sig,
+		},
+	}
+}
+
+func delivered(h *Handler, slot string) (string, bool) {
+	h.Lock()
+	defer h.Unlock()
+	hash, ok := h.echo.delivered[slot]
+	return hash, ok
+}
+
+func TestEchoQuorum(t *testing.T) {
+	for _, tc := range []struct{ n, thr, quorum int }{
+		{4, 3, 3},
+		{5, 3, 4},
+		{7, 4, 6},
+		{10, 6, 8},
+	} {
+		require.Equal(t, tc.quorum, echoQuorum(tc.n, tc.thr))
+		// two different packets can't gather a quorum if the malicious nodes
+		// echo both
+		f := tc.n - tc.thr
+		require.True(t, 2*tc.quorum-tc.n > f)
+	}
+}
+
+func TestEchoBroadcastDeliver(t *testing.T) {
+	n, thr := 5, 3
+	privs, handlers, mem := newEchoHandlers(t, n, thr)
+	echo := fakeEcho(t, privs[0], 0, 1, true)
+	slot, hash, err := packetSlot(echo.Echo.Packet)
+	require.NoError(t, err)
+	for _, id := range privs[1:] {
+		require.NoError(t, mem.Send(id.Public, echo))
+	}
+	for _, h := range handlers[1:] {
+		waitFor(t, func() bool {
+			d, ok := delivered(h, slot)
+			return ok && d == hash
+		})
+	}
+}
+
+func TestEchoBroadcastEquivocation(t *testing.T) {
+	n, thr := 5, 3
+	privs, handlers, mem := newEchoHandlers(t, n, thr)
+	// node 0 sends a valid response to half of the nodes and a complaint to
+	// the other half for the same dealer
+	echoA := fakeEcho(t, privs[0], 0, 1, true)
+	echoB := fakeEcho(t, privs[0], 0, 1, false)
+	slot, hashA, err := packetSlot(echoA.Echo.Packet)
+	require.NoError(t, err)
+	_, hashB, err := packetSlot(echoB.Echo.Packet)
+	require.NoError(t, err)
+	require.NotEqual(t, hashA, hashB)
+
+	for _, id := range privs[1:3] {
+		require.NoError(t, mem.Send(id.Public, echoA))
+	}
+	for _, id := range privs[3:] {
+		require.NoError(t, mem.Send(id.Public, echoB))
+	}
+
+	// every honest node must see both packets through the echoes of the others
+	for _, h := range handlers[1:] {
+		waitFor(t, func() bool {
+			h.Lock()
+			defer h.Unlock()
+			return len(h.echo.echoes[slot]) == 2
+		})
+	}
+	// but none of them reached a quorum so none must be delivered
+	for _, h := range handlers[1:] {
+		_, ok := delivered(h, slot)
+		require.False(t, ok)
+	}
+}
+
+func TestEchoBroadcastForgedPacket(t *testing.T) {
+	n, thr := 5, 3
+	privs, handlers, mem := newEchoHandlers(t, n, thr)
+	// node 0 echoes a response on behalf of node 1, signed with its own key
+	forged := fakeEcho(t, privs[0], 0, 2, true)
+	forged.Echo.Packet.Response.Response.Index = 1
+	forged = echoOf(t, privs[0], 0, forged.Echo.Packet)
+	slot, _, err := packetSlot(forged.Echo.Packet)
+	require.NoError(t, err)
+	for _, id := range privs[1:] {
+		require.NoError(t, mem.Send(id.Public, forged))
+	}
+	// the honest nodes neither count, echo nor deliver it
+	for _, h := range handlers[1:] {
+		h.Lock()
+		require.Len(t, h.echo.echoes[slot], 0)
+		require.False(t, h.echo.echoed[slot])
+		h.Unlock()
+		_, ok := delivered(h, slot)
+		require.False(t, ok)
+	}
+}
+
+func TestEchoBroadcastEquivocatingDealer(t *testing.T) {
+	n, thr := 5, 3
+	privs, handlers, mem := newEchoHandlers(t, n, thr)
+	// dealer 0 reveals two different deals for verifier 1, each validly
+	// signed, to different halves of the group
+	justA := fakeJustification(t, privs[0], 0, 1)
+	justB := fakeJustification(t, privs[0], 0, 1)
+	slot, hashA, err := packetSlot(justA)
+	require.NoError(t, err)
+	slotB, hashB, err := packetSlot(justB)
+	require.NoError(t, err)
+	require.Equal(t, slot, slotB)
+	require.NotEqual(t, hashA, hashB)
+
+	for _, id := range privs[1:3] {
+		require.NoError(t, mem.Send(id.Public, justA))
+	}
+	for _, id := range privs[3:] {
+		require.NoError(t, mem.Send(id.Public, justB))
+	}
+
+	for _, h := range handlers[1:] {
+		waitFor(t, func() bool {
+			h.Lock()
+			defer h.Unlock()
+			return len(h.echo.echoes[slot]) == 2
+		})
+	}
+	for _, h := range handlers[1:] {
+		_, ok := delivered(h, slot)
+		require.False(t, ok)
+	}
+}
+
+func TestDKGFreshEchoBroadcast(t *testing.T) {
+	n := 5
+	thr := key.DefaultThreshold(n)
+	timeout := 2 * time.Second
+	dt := newDKGTest(t, n, thr, timeout, Config{EchoBroadcast: true})
+
+	for _, k := range dt.keys {
+		dt.ServeDKG(k)
+		defer dt.StopDKG(k)
+	}
+
+	dt.StartDKG(dt.keys[0])
+	keys, _ := dt.WaitFinish(n)
+	require.True(t, dt.CheckIncludedQUAL(keys))
+}
+
+// waitFor polls the condition until it is true or fails the test after two
+// seconds. require.Eventually is not used since it can panic in this version
+// of testify when the condition is met.
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition never satisfied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -13,14 +13,17 @@ import (
 	"sync"
 	"time"
 
+	dkg "github.com/drand/drand/dkg/pedersen"
+	vss "github.com/drand/drand/dkg/pedersen/vss"
+	"github.com/drand/drand/entropy"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/log"
 	"github.com/drand/drand/net"
+	share_proto "github.com/drand/drand/protobuf/crypto"
 	dkg_proto "github.com/drand/drand/protobuf/crypto/dkg"
 	vss_proto "github.com/drand/drand/protobuf/crypto/vss"
 	"github.com/drand/kyber"
-	dkg "github.com/drand/kyber/share/dkg/pedersen"
-	vss "github.com/drand/kyber/share/vss/pedersen"
+	"github.com/drand/kyber/share"
 	clock "github.com/jonboulle/clockwork"
 	"google.golang.org/grpc/peer"
 )
@@ -35,7 +38,7 @@ const DefaultTimeout = time.Duration(1) * time.Minute
 // transformed to be passed down to the kyber dkg library.
 type Config struct {
 	Suite          Suite
-	Key            *key.Pair
+	Public         *key.Identity
 	NewNodes       *key.Group
 	OldNodes       *key.Group
 	Share          *key.Share
@@ -43,6 +46,17 @@ type Config struct {
 	Reader         io.Reader
 	UserReaderOnly bool
 	Clock          clock.Clock
+	// EchoBroadcast enables the echo broadcast of the responses and
+	// justifications, so a node can not send different packets to different
+	// nodes. All nodes must enable it for the protocol to finish. Packets are
+	// only delivered once (n + n - thr) / 2 + 1 nodes echoed them, which can
+	// be more than the threshold: all nodes but one with 5 nodes and a
+	// threshold of 3.
+	EchoBroadcast bool
+	// Longterm performs the operations of this node with the private key of
+	// the Public identity: it signs and decrypts the deals, signs the
+	// responses, the echoes and the entropy commitment.
+	Longterm key.Longterm
 }
 
 // Share represents the private information that a node holds after a successful
@@ -51,11 +65,12 @@ type Share = dkg.DistKeyShare
 
 // Handler is the stateful struct that runs a DKG with the peers
 type Handler struct {
-	net           Network     // network to send data out
-	conf          *Config     // configuration given at init time
-	cdkg          *dkg.Config // dkg config
-	private       *key.Pair   // private key
-	nidx          int         // the index of the private/public key pair in the new list
+	net           Network       // network to send data out
+	conf          *Config       // configuration given at init time
+	cdkg          *dkg.Config   // dkg config
+	public        *key.Identity // public identity
+	signer        key.Signer    // signs with the longterm key
+	nidx          int           // the index of the private/public key pair in the new list
 	oidx          int
 	newNode       bool                       // true if this node belongs in the new group or not
 	oldNode       bool                       // true if this node belongs to the oldNode list
@@ -71,11 +86,16 @@ type Handler struct {
 	exitCh        chan bool                  // any old node not in the new group will signal the end of the protocol through this channel
 
 	sync.Mutex
-	share           *dkg.DistKeyShare // the final share generated
-	sendDeal        bool              // true if this DKG should be expected to send a deal
-	timerCh         chan bool         // closed when timer should stop waiting
-	timeouted       bool              // true if timeout occured
-	timeoutLaunched bool              // true if timeout has launched already
+	share           *dkg.DistKeyShare        // the final share generated
+	sendDeal        bool                     // true if this DKG should be expected to send a deal
+	timerCh         chan bool                // closed when timer should stop waiting
+	timeouted       bool                     // true if timeout occured
+	timeoutLaunched bool                     // true if timeout has launched already
+	echo            *echoBroadcast           // nil if echo broadcast is disabled
+	committed       *entropy.CommittedReader // user entropy committed to, if any
+	receipts        map[uint32]*Receipt      // entropy receipts of the dealers
+	revealCh        chan bool                // closed when all commitments are revealed
+	revealsDone     bool                     // true if revealCh is closed
 	l               log.Logger
 }
 
@@ -97,14 +117,28 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 	if c.Timeout == time.Duration(0) {
 		c.Timeout = DefaultTimeout
 	}
+	// during a fresh DKG, the user entropy is expanded from a seed the node
+	// commits to, so it can reveal it afterwards. It is only done when the
+	// user entropy is mixed with local randomness: with the user entropy
+	// only, the seed would reveal the polynomial dealt by the node.
+	var committed *entropy.CommittedReader
+	var reader = c.Reader
+	if c.Reader != nil && c.OldNodes == nil && !c.UserReaderOnly {
+		var err error
+		if committed, err = entropy.NewCommittedReader(c.Reader); err != nil {
+			return nil, fmt.Errorf("dkg: error reading user entropy: %s", err)
+		}
+		reader = committed
+	}
 	cdkg := &dkg.Config{
 		Suite:          c.Suite.(dkg.Suite),
-		Longterm:       c.Key.Key,
+		Longterm:       c.Longterm,
+		Public:         c.Public.Key,
 		NewNodes:       c.NewNodes.Points(),
 		PublicCoeffs:   dpub,
 		Share:          share,
 		Threshold:      c.NewNodes.Threshold,
-		Reader:         c.Reader,
+		Reader:         reader,
 		UserReaderOnly: c.UserReaderOnly,
 	}
 
@@ -120,12 +154,12 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 	var newNode, oldNode bool
 	var nidx, oidx int
 	var found bool
-	nidx, found = c.NewNodes.Index(c.Key.Public)
+	nidx, found = c.NewNodes.Index(c.Public)
 	if found {
 		newNode = true
 	}
 	if c.OldNodes != nil {
-		oidx, found = c.OldNodes.Index(c.Key.Public)
+		oidx, found = c.OldNodes.Index(c.Public)
 		if found {
 			oldNode = true
 		}
@@ -141,7 +175,8 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 	handler := &Handler{
 		conf:         c,
 		cdkg:         cdkg,
-		private:      c.Key,
+		public:       c.Public,
+		signer:       c.Longterm,
 		state:        state,
 		net:          n,
 		nidx:         nidx,
@@ -155,8 +190,21 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 		exitCh:       make(chan bool, 1),
 		sendDeal:     shouldSendDeal,
 		timerCh:      make(chan bool, 1),
+		committed:    committed,
+		receipts:     make(map[uint32]*Receipt),
+		revealCh:     make(chan bool),
 	}
 	handler.l = l.With("dkg", handler.info())
+	if c.EchoBroadcast {
+		handler.echo = newEchoBroadcast(handler)
+	}
+	if committed != nil && newNode {
+		handler.receipts[uint32(nidx)] = &Receipt{
+			Index:      uint32(nidx),
+			Address:    c.Public.Address(),
+			Commitment: committed.Commitment(),
+		}
+	}
 	return handler, nil
 }
 
@@ -169,13 +217,32 @@ func (h *Handler) Process(c context.Context, packet *dkg_proto.Packet) {
 		go h.startTimer() // start timer at the first message received
 	}
 	peer, _ := peer.FromContext(c)
+	if packet.Reveal != nil {
+		h.processReveal(packet.Reveal)
+		return
+	}
+	if h.echo != nil && packet.Deal == nil {
+		for _, p := range h.echo.process(packet) {
+			h.processPacket(peer, p)
+		}
+		return
+	}
+	if packet.Echo != nil && packet.Echo.Packet != nil {
+		// echo broadcast is disabled locally so we process the echoed packet
+		// directly
+		packet = packet.Echo.Packet
+	}
+	h.processPacket(peer, packet)
+}
+
+func (h *Handler) processPacket(peer *peer.Peer, packet *dkg_proto.Packet) {
 	switch {
 	case packet.Deal != nil:
 		h.processDeal(peer, packet.Deal)
 	case packet.Response != nil:
 		h.processResponse(peer, packet.Response)
 	case packet.Justification != nil:
-		panic("not yet implemented")
+		h.processJustification(peer, packet.Justification)
 	}
 }
 
@@ -234,12 +301,12 @@ func (h *Handler) QualifiedGroup() *key.Group {
 }
 
 func (h *Handler) startTimer() {
-	fmt.Printf(" DKG HANDLER TIMEOUT %s -> now %d -> will trigger at %d\n", h.conf.Key.Public.Address(), h.conf.Clock.Now().Unix(), h.conf.Clock.Now().Add(h.conf.Timeout).Unix())
+	fmt.Printf(" DKG HANDLER TIMEOUT %s -> now %d -> will trigger at %d\n", h.conf.Public.Address(), h.conf.Clock.Now().Unix(), h.conf.Clock.Now().Add(h.conf.Timeout).Unix())
 	select {
 	case <-h.conf.Clock.After(h.conf.Timeout):
 		h.Lock()
 		defer h.Unlock()
-		fmt.Printf("DKG HANDLER %s - %d - timeout triggered !\n", h.conf.Key.Public.Address(), h.nidx)
+		fmt.Printf("DKG HANDLER %s - %d - timeout triggered !\n", h.conf.Public.Address(), h.nidx)
 		h.l.Info("timout", "triggered")
 		h.timeouted = true
 		h.state.SetTimeout()
@@ -271,6 +338,7 @@ func (h *Handler) processDeal(p *peer.Peer, pdeal *dkg_proto.Deal) {
 		localLog.Error("kyber", err)
 		return
 	}
+	h.processCommitment(deal.Index, pdeal.Entropy)
 
 	if !h.sentDeals && h.sendDeal {
 		localLog.Debug("action", "sending_deals")
@@ -296,7 +364,7 @@ func (h *Handler) processDeal(p *peer.Peer, pdeal *dkg_proto.Deal) {
 			},
 		}
 		localLog.Debug("action", "broadcasting_responses")
-		go h.broadcast(out, true, "response")
+		h.broadcastPacket(out, "response")
 	}
 }
 
@@ -343,25 +411,103 @@ func (h *Handler) processResponse(p *peer.Peer, presp *dkg_proto.Response) {
 		return
 	}
 	if j != nil && h.oldNode {
-		// XXX TODO
 		localLog.Debug("broadcasting justification")
+		deal, err := dealToProto(j.Justification.Deal)
+		if err != nil {
+			localLog.Error("justification", err)
+			return
+		}
 		packet := &dkg_proto.Packet{
 			Justification: &dkg_proto.Justification{
 				Index: j.Index,
 				Justification: &vss_proto.Justification{
 					SessionId: j.Justification.SessionID,
 					Index:     j.Justification.Index,
+					Deal:      deal,
 					Signature: j.Justification.Signature,
 				},
 			},
 		}
-		go h.broadcast(packet, true, "justification")
+		h.broadcastPacket(packet, "justification")
 	}
 
 	localLog.Debug("processed_resp", h.respProcessed, "processed_total", h.n*(h.n-1), "certified", h.state.Certified())
 
 }
 
+// processJustification gives the deal revealed by a dealer after a complaint
+// to the DKG state machine
+func (h *Handler) processJustification(p *peer.Peer, pj *dkg_proto.Justification) {
+	defer h.checkCertified()
+	j, err := justificationFromProto(h.conf.Suite, pj)
+	if err != nil {
+		h.l.Error("process_justification", err)
+		return
+	}
+	if err := h.state.ProcessJustification(j); err != nil {
+		h.l.Error("process_justification", err, "dealer", j.Index, "verifier", j.Justification.Index)
+	}
+}
+
+// dealToProto returns the protobuf form of the cleartext deal of a
+// justification
+func dealToProto(d *vss.Deal) (*vss_proto.Deal, error) {
+	if d == nil || d.SecShare == nil {
+		return nil, errors.New("dkg: justification without deal")
+	}
+	buff, err := d.SecShare.V.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	commits := make([][]byte, len(d.Commitments))
+	for i, c := range d.Commitments {
+		if commits[i], err = c.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	return &vss_proto.Deal{
+		SessionId:   d.SessionID,
+		Share:       &share_proto.PrivateShare{Index: uint32(d.SecShare.I), Share: buff},
+		Threshold:   d.T,
+		Commitments: commits,
+	}, nil
+}
+
+// justificationFromProto returns the justification of the protobuf packet
+// with its cleartext deal
+func justificationFromProto(s Suite, pj *dkg_proto.Justification) (*dkg.Justification, error) {
+	vj := pj.GetJustification()
+	d := vj.GetDeal()
+	if vj == nil || d == nil || d.GetShare() == nil {
+		return nil, errors.New("dkg: justification without deal")
+	}
+	v := s.Scalar()
+	if err := v.UnmarshalBinary(d.GetShare().GetShare()); err != nil {
+		return nil, err
+	}
+	commits := make([]kyber.Point, len(d.GetCommitments()))
+	for i, buff := range d.GetCommitments() {
+		commits[i] = s.Point()
+		if err := commits[i].UnmarshalBinary(buff); err != nil {
+			return nil, err
+		}
+	}
+	return &dkg.Justification{
+		Index: pj.GetIndex(),
+		Justification: &vss.Justification{
+			SessionID: vj.GetSessionId(),
+			Index:     vj.GetIndex(),
+			Deal: &vss.Deal{
+				SessionID:   d.GetSessionId(),
+				SecShare:    &share.PriShare{I: int(d.GetShare().GetIndex()), V: v},
+				T:           d.GetThreshold(),
+				Commitments: commits,
+			},
+			Signature: vj.GetSignature(),
+		},
+	}, nil
+}
+
 func (h *Handler) info() string {
 	var s string
 	if h.oldNode {
@@ -400,6 +546,8 @@ func (h *Handler) checkCertified() {
 	}
 	h.done = true
 	close(h.timerCh)
+	h.reveal()
+	h.checkReveals()
 	if !h.newNode {
 		// we just signal an empty message since we are not holder of a share
 		// anymore
@@ -440,13 +588,18 @@ func (h *Handler) sendDeals() error {
 		h.Unlock()
 		return err
 	}
+	commitment, err := h.entropyCommitment()
+	if err != nil {
+		h.Unlock()
+		return err
+	}
 	h.Unlock()
 	h.l.Debug("send_deal", "start")
 	statusCh := make(chan bool, len(deals))
 	ids := h.conf.NewNodes.Identities()
 	for i, deal := range deals {
 		if i == h.nidx && h.newNode {
-			h.l.Fatal("same index deal", i, "pubkey", h.conf.Key.Public.Key.String())
+			h.l.Fatal("same index deal", i, "pubkey", h.conf.Public.Key.String())
 			panic("this is a bug with drand that should not happen. Please submit report if possible")
 		}
 		go func(i int, deal *dkg.Deal) {
@@ -461,6 +614,7 @@ func (h *Handler) sendDeals() error {
 						Nonce:     deal.Deal.Nonce,
 						Cipher:    deal.Deal.Cipher,
 					},
+					Entropy: commitment,
 				},
 			}
 			h.l.Debug("send_deal_to", i, "addr", id.Address())
@@ -492,6 +646,31 @@ func (h *Handler) sendDeals() error {
 
 }
 
+// broadcastPacket sends a response or a justification to all nodes, through
+// the echo broadcast if enabled. It must be called with the lock held.
+func (h *Handler) broadcastPacket(p *dkg_proto.Packet, msgType string) {
+	if h.echo != nil {
+		h.echo.broadcast(p, msgType)
+		return
+	}
+	go h.broadcast(p, true, msgType)
+}
+
+// isIssuer returns true if the given response or justification has been
+// issued by this node.
+func (h *Handler) isIssuer(p *dkg_proto.Packet) bool {
+	switch {
+	case p.Response != nil && p.Response.Response != nil:
+		return h.newNode && int(p.Response.Response.Index) == h.nidx
+	case p.Justification != nil:
+		if h.conf.OldNodes == nil {
+			return h.newNode && int(p.Justification.Index) == h.nidx
+		}
+		return h.oldNode && int(p.Justification.Index) == h.oidx
+	}
+	return false
+}
+
 // The following packets must be sent to the following nodes:
 // - Deals are sent to the new nodes only
 // - Responses are sent to to both new nodes and old nodes but *only once per
@@ -535,7 +714,7 @@ func (h *Handler) broadcast(p *dkg_proto.Packet, toOldNodes bool, msgType string
 }
 
 func (h *Handler) addr() string {
-	return h.private.Public.Address()
+	return h.public.Address()
 }
 
 func (h *Handler) dealerAddr(i uint32) string {
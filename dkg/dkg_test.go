@@ -1,6 +1,7 @@
 package dkg
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/drand/drand/entropy"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/log"
@@ -17,6 +19,8 @@ import (
 	"github.com/drand/drand/protobuf/crypto/dkg"
 	"github.com/drand/drand/protobuf/drand"
 	"github.com/drand/drand/test"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
 	clock "github.com/jonboulle/clockwork"
 	"github.com/nikkolasg/slog"
 	"github.com/stretchr/testify/require"
@@ -95,26 +99,39 @@ type DKGTest struct {
 }
 
 func NewDKGTest(t *testing.T, n, thr int, timeout time.Duration, r io.Reader, onlyUser bool) *DKGTest {
-	privs := test.GenerateIDs(n)
+	conf := Config{
+		Reader:         r,
+		UserReaderOnly: onlyUser,
+	}
+	return newDKGTest(t, n, thr, timeout, conf)
+}
+
+// newDKGTest creates a fresh DKG test using the given config as a template for
+// all nodes.
+func newDKGTest(t *testing.T, n, thr int, timeout time.Duration, conf Config) *DKGTest {
+	suite := key.DefaultSuite
+	if conf.Suite != nil {
+		var err error
+		suite, err = key.SuiteOf(conf.Suite.Point())
+		require.NoError(t, err)
+	}
+	privs := test.GenerateSuiteIDs(n, suite)
 	pubs := test.ListFromPrivates(privs)
 	newGroup := key.NewGroup(pubs, thr, 0)
 	newNodes := make(map[string]*node)
 	nets := testNets(n, true)
 	keys := make([]string, n)
 	clocks := make(map[string]clock.FakeClock)
-	conf := Config{
-		Suite:          key.KeyGroup.(Suite),
-		NewNodes:       newGroup,
-		Timeout:        timeout,
-		Reader:         r,
-		UserReaderOnly: onlyUser,
-	}
+	conf.Suite = suite.KeyGroup.(Suite)
+	conf.NewNodes = newGroup
+	conf.Timeout = timeout
 	for i := 0; i < n; i++ {
 		c := conf
-		c.Key = privs[i]
+		c.Public = privs[i].Public
+		c.Longterm = privs[i]
 		clock := clock.NewFakeClock()
 		c.Clock = clock
-		clocks[c.Key.Public.Address()] = clock
+		clocks[c.Public.Address()] = clock
 		var err error
 		nets[i].SetTimeout(timeout / 2)
 		handler, err := NewHandler(nets[i], &c, log.DefaultLogger)
@@ -184,11 +201,12 @@ func NewDKGTestResharing(t *testing.T, oldN, oldT, newN, newT, common int, timeo
 	}
 	for i := 0; i < oldToRemove; i++ {
 		c := conf
-		c.Key = oldPrivs[i]
+		c.Public = oldPrivs[i].Public
+		c.Longterm = oldPrivs[i]
 		clock := clock.NewFakeClock()
 		c.Clock = clock
-		clocks[c.Key.Public.Address()] = clock
-		groupIndex, ok := oldGroup.Index(c.Key.Public)
+		clocks[c.Public.Address()] = clock
+		groupIndex, ok := oldGroup.Index(c.Public)
 		require.True(t, ok)
 		c.Share = &key.Share{
 			Share:   oldShares[groupIndex],
@@ -198,29 +216,30 @@ func NewDKGTestResharing(t *testing.T, oldN, oldT, newN, newT, common int, timeo
 		handler, err := NewHandler(nets[i], &c, log.DefaultLogger)
 		checkErr(err)
 		dkgServer := testDKGServer{h: handler}
-		listener := net.NewTCPGrpcListener(c.Key.Public.Address(), &dkgServer)
+		listener := net.NewTCPGrpcListener(c.Public.Address(), &dkgServer)
 
-		oldNodes[c.Key.Public.Address()] = &node{
-			priv:     c.Key,
-			pub:      c.Key.Public,
+		oldNodes[c.Public.Address()] = &node{
+			priv:     oldPrivs[i],
+			pub:      c.Public,
 			net:      nets[i],
 			handler:  handler,
 			listener: listener,
 			newNode:  false,
 		}
-		keys[i] = c.Key.Public.Address()
+		keys[i] = c.Public.Address()
 	}
 	newNodes := make(map[string]*node)
 	for i := 0; i < newN; i++ {
 		c := conf
-		c.Key = newPrivs[i]
+		c.Public = newPrivs[i].Public
+		c.Longterm = newPrivs[i]
 		clock := clock.NewFakeClock()
 		c.Clock = clock
-		clocks[c.Key.Public.Address()] = clock
+		clocks[c.Public.Address()] = clock
 
 		nnet := nets[oldToRemove+i]
 		if i < common {
-			groupIndex, ok := oldGroup.Index(c.Key.Public)
+			groupIndex, ok := oldGroup.Index(c.Public)
 			require.True(t, ok)
 			c.Share = &key.Share{
 				Share:   oldShares[groupIndex],
@@ -231,15 +250,15 @@ func NewDKGTestResharing(t *testing.T, oldN, oldT, newN, newT, common int, timeo
 		handler, err := NewHandler(nnet, &c, log.DefaultLogger)
 		checkErr(err)
 		dkgServer := testDKGServer{h: handler}
-		newNodes[c.Key.Public.Address()] = &node{
-			priv:     c.Key,
-			pub:      c.Key.Public,
+		newNodes[c.Public.Address()] = &node{
+			priv:     newPrivs[i],
+			pub:      c.Public,
 			net:      nnet,
-			listener: net.NewTCPGrpcListener(c.Key.Public.Address(), &dkgServer),
+			listener: net.NewTCPGrpcListener(c.Public.Address(), &dkgServer),
 			handler:  handler,
 			newNode:  true,
 		}
-		keys[oldToRemove+i] = c.Key.Public.Address()
+		keys[oldToRemove+i] = c.Public.Address()
 	}
 	return &DKGTest{
 		total:     totalDKGs,
@@ -453,6 +472,39 @@ func TestDKGFresh(t *testing.T) {
 	require.True(t, dt.CheckIncludedQUAL(keys))
 }
 
+func TestDKGSigsOnG1(t *testing.T) {
+	n := 5
+	thr := key.DefaultThreshold(n)
+	timeout := 2 * time.Second
+	suite, err := key.SuiteFromName(key.SuiteSigsOnG1)
+	require.NoError(t, err)
+	dt := newDKGTest(t, n, thr, timeout, Config{Suite: suite.KeyGroup.(Suite)})
+
+	for _, k := range dt.keys {
+		dt.ServeDKG(k)
+	}
+	dt.StartDKG(dt.keys[0])
+	keys, _ := dt.WaitFinish(n)
+	require.True(t, dt.CheckIncludedQUAL(keys))
+
+	// the shares produce signatures on G1 verified by the distributed key
+	msg := []byte("hello")
+	var pubPoly *share.PubPoly
+	var sigs [][]byte
+	for _, k := range keys {
+		s := dt.getShare(k)
+		require.Equal(t, suite, s.Suite())
+		pubPoly = s.PubPoly()
+		sig, err := suite.Scheme.Sign(s.PrivateShare(), msg)
+		require.NoError(t, err)
+		sigs = append(sigs, sig)
+	}
+	sig, err := suite.Scheme.Recover(pubPoly, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.Equal(t, suite.SigGroup.PointLen(), len(sig))
+	require.NoError(t, suite.Scheme.VerifyRecovered(pubPoly.Commit(), msg, sig))
+}
+
 func TestDKGWithTimeout(t *testing.T) {
 	n := 7
 	thr := key.DefaultThreshold(n)
@@ -632,6 +684,11 @@ func TestDKGEntropy(t *testing.T) {
 
 	finished1 := run(dt1)
 	finished2 := run(dt2)
+	// the user entropy only is never committed to since revealing it would
+	// reveal the secret of the node
+	for _, nd := range dt1.newNodes {
+		require.Empty(t, nd.handler.Record().Receipts)
+	}
 
 	for _, id1 := range finished1 {
 		s1 := dt1.getShare(id1)
@@ -671,3 +728,65 @@ func tmpEntropySource() *entropy.ScriptReader {
 	r := entropy.NewScriptReader(f.Name())
 	return r
 }
+
+func TestDKGEntropyReceipts(t *testing.T) {
+	source := tmpEntropySource()
+	defer os.RemoveAll(source.GetPath())
+
+	n := 5
+	thr := key.DefaultThreshold(n)
+	timeout := 2 * time.Second
+	dt := NewDKGTest(t, n, thr, timeout, source, false)
+	for _, k := range dt.keys {
+		dt.ServeDKG(k)
+		defer dt.StopDKG(k)
+	}
+	dt.StartDKG(dt.keys[0])
+	_, timeouted := dt.WaitFinish(n)
+	require.False(t, timeouted)
+
+	for _, nd := range dt.newNodes {
+		select {
+		case <-nd.handler.WaitReveals():
+		case <-time.After(2 * time.Second):
+			t.Fatal("reveals not received")
+		}
+		record := nd.handler.Record()
+		require.Len(t, record.Receipts, n)
+		verifiers := nd.handler.state.Verifiers()
+		for _, r := range record.Receipts {
+			require.True(t, r.Verified)
+			require.NoError(t, entropy.VerifyReveal(r.Commitment, r.Seed))
+			// the seed is mixed with local randomness so it does not reveal
+			// the secret of the dealer
+			suite := key.KeyGroup.(Suite)
+			secret := suite.Scalar().Pick(random.New(entropy.NewSeedReader(r.Seed)))
+			public := suite.Point().Mul(secret, nil)
+			require.False(t, public.Equal(verifiers[r.Index].Commits()[0]))
+		}
+		// the record survives a TOML round trip
+		var buff bytes.Buffer
+		require.NoError(t, toml.NewEncoder(&buff).Encode(record.TOML()))
+		rt := new(Record)
+		rtoml := rt.TOMLValue()
+		_, err := toml.Decode(buff.String(), rtoml)
+		require.NoError(t, err)
+		require.NoError(t, rt.FromTOML(rtoml))
+		require.Equal(t, record, rt)
+	}
+}
+
+func TestDKGForgedReveal(t *testing.T) {
+	seed := []byte("seed of the user entropy of the dealer")
+	h := &Handler{
+		receipts: map[uint32]*Receipt{1: {Index: 1, Commitment: entropy.Commit(seed)}},
+		revealCh: make(chan bool),
+		l:        log.DefaultLogger,
+	}
+	// a seed not matching the commitment does not block the real one
+	h.processReveal(&dkg.Reveal{Index: 1, Seed: []byte("forged")})
+	require.Nil(t, h.receipts[1].Seed)
+	h.processReveal(&dkg.Reveal{Index: 1, Seed: seed})
+	require.Equal(t, seed, h.receipts[1].Seed)
+	require.True(t, h.receipts[1].Verified)
+}
@@ -0,0 +1,218 @@
+package dkg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/drand/drand/entropy"
+	dkg_proto "github.com/drand/drand/protobuf/crypto/dkg"
+	"github.com/drand/kyber/sign/schnorr"
+)
+
+// Receipt holds the commitment of a dealer to the seed of its user entropy
+// and, once revealed, the seed itself. Dealers only commit to their user
+// entropy when they mix it with local randomness, so the seed does not reveal
+// the polynomial they dealt.
+type Receipt struct {
+	// Index of the dealer in the group
+	Index uint32
+	// Address of the dealer
+	Address string
+	// Commitment of the dealer to its seed
+	Commitment []byte
+	// Seed revealed by the dealer, nil if not revealed
+	Seed []byte
+	// Verified is true if the revealed seed matches the commitment
+	Verified bool
+}
+
+// Record is the list of entropy receipts of a DKG, as seen by a node. It is
+// saved along the other DKG results.
+type Record struct {
+	Receipts []*Receipt
+}
+
+// RecordTOML is the TOML representation of a Record
+type RecordTOML struct {
+	Receipts []*ReceiptTOML
+}
+
+// ReceiptTOML is the TOML representation of a Receipt
+type ReceiptTOML struct {
+	Index      uint32
+	Address    string
+	Commitment string
+	Seed       string `toml:",omitempty"`
+	Verified   bool
+}
+
+// TOML returns a TOML compatible representation of the record
+func (r *Record) TOML() interface{} {
+	rt := &RecordTOML{Receipts: make([]*ReceiptTOML, len(r.Receipts))}
+	for i, rc := range r.Receipts {
+		rt.Receipts[i] = &ReceiptTOML{
+			Index:      rc.Index,
+			Address:    rc.Address,
+			Commitment: hex.EncodeToString(rc.Commitment),
+			Seed:       hex.EncodeToString(rc.Seed),
+			Verified:   rc.Verified,
+		}
+	}
+	return rt
+}
+
+// FromTOML decodes the record from its TOML representation
+func (r *Record) FromTOML(i interface{}) error {
+	rt, ok := i.(*RecordTOML)
+	if !ok {
+		return fmt.Errorf("dkg: record can't decode from %T", i)
+	}
+	r.Receipts = make([]*Receipt, len(rt.Receipts))
+	for i, rc := range rt.Receipts {
+		commitment, err := hex.DecodeString(rc.Commitment)
+		if err != nil {
+			return fmt.Errorf("dkg: receipt %d: %s", i, err)
+		}
+		seed, err := hex.DecodeString(rc.Seed)
+		if err != nil {
+			return fmt.Errorf("dkg: receipt %d: %s", i, err)
+		}
+		if len(seed) == 0 {
+			seed = nil
+		}
+		r.Receipts[i] = &Receipt{
+			Index:      rc.Index,
+			Address:    rc.Address,
+			Commitment: commitment,
+			Seed:       seed,
+			Verified:   rc.Verified,
+		}
+	}
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the record
+func (r *Record) TOMLValue() interface{} {
+	return &RecordTOML{}
+}
+
+// commitmentMessage returns the message signed by a dealer to commit to its
+// user entropy
+func commitmentMessage(index uint32, commitment []byte) []byte {
+	h := sha256.New()
+	binary.Write(h, binary.LittleEndian, index)
+	h.Write(commitment)
+	return h.Sum(nil)
+}
+
+// entropyCommitment returns the signed commitment of this node to its user
+// entropy, nil if it does not use any user entropy.
+func (h *Handler) entropyCommitment() (*dkg_proto.EntropyCommitment, error) {
+	if h.committed == nil {
+		return nil, nil
+	}
+	commitment := h.committed.Commitment()
+	msg := commitmentMessage(uint32(h.nidx), commitment)
+	sig, err := h.signer.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &dkg_proto.EntropyCommitment{
+		Commitment: commitment,
+		Signature:  sig,
+	}, nil
+}
+
+// processCommitment records the entropy commitment of the dealer of a valid
+// deal. It must be called with the lock held.
+func (h *Handler) processCommitment(dealer uint32, c *dkg_proto.EntropyCommitment) {
+	if c == nil || int(dealer) >= h.conf.NewNodes.Len() {
+		return
+	}
+	id := h.conf.NewNodes.Public(int(dealer))
+	msg := commitmentMessage(dealer, c.Commitment)
+	if err := schnorr.Verify(h.conf.Suite, id.Key, msg, c.Signature); err != nil {
+		h.l.Error("entropy_commitment", "invalid signature", "dealer", dealer)
+		return
+	}
+	h.receipts[dealer] = &Receipt{
+		Index:      dealer,
+		Address:    id.Address(),
+		Commitment: c.Commitment,
+	}
+}
+
+// processReveal records the seed revealed by a dealer if it matches its
+// commitment. The reveals are not signed, so a seed not matching the
+// commitment is ignored, to not let another node block the reveal of the
+// dealer. It must be called with the lock held.
+func (h *Handler) processReveal(r *dkg_proto.Reveal) {
+	receipt, ok := h.receipts[r.Index]
+	if !ok {
+		h.l.Debug("entropy_reveal", "unknown commitment", "dealer", r.Index)
+		return
+	}
+	if receipt.Seed != nil {
+		return
+	}
+	if err := entropy.VerifyReveal(receipt.Commitment, r.Seed); err != nil {
+		h.l.Error("entropy_reveal", err, "dealer", r.Index)
+		return
+	}
+	receipt.Seed = r.Seed
+	receipt.Verified = true
+	h.l.Debug("entropy_reveal", r.Index, "verified", receipt.Verified)
+	h.checkReveals()
+}
+
+// reveal sends the seed of the user entropy of this node to the other nodes,
+// once this node has finished the DKG. It must be called with the lock held.
+func (h *Handler) reveal() {
+	if h.committed == nil || !h.newNode {
+		return
+	}
+	r := &dkg_proto.Reveal{
+		Index: uint32(h.nidx),
+		Seed:  h.committed.Seed(),
+	}
+	h.processReveal(r)
+	go h.broadcast(&dkg_proto.Packet{Reveal: r}, false, "reveal")
+}
+
+// checkReveals signals on the WaitReveals channel once all known commitments
+// have been revealed. It must be called with the lock held.
+func (h *Handler) checkReveals() {
+	if !h.done || h.revealsDone {
+		return
+	}
+	for _, r := range h.receipts {
+		if r.Seed == nil {
+			return
+		}
+	}
+	h.revealsDone = true
+	close(h.revealCh)
+}
+
+// WaitReveals returns a channel that is closed once the DKG is finished and
+// all the dealers that committed to their user entropy revealed it.
+func (h *Handler) WaitReveals() chan bool {
+	return h.revealCh
+}
+
+// Record returns the entropy receipts of the dealers that committed to their
+// user entropy, ordered by index.
+func (h *Handler) Record() *Record {
+	h.Lock()
+	defer h.Unlock()
+	record := &Record{}
+	for i := 0; i < h.conf.NewNodes.Len(); i++ {
+		if r, ok := h.receipts[uint32(i)]; ok {
+			cpy := *r
+			record.Receipts = append(record.Receipts, &cpy)
+		}
+	}
+	return record
+}
@@ -82,12 +82,26 @@ func Encrypt(g kyber.Group, fn func() hash.Hash, public kyber.Point, msg []byte)
 // and the derivation of the symmetric key. It finally tries to decrypt the
 // ciphertext and returns the plaintext if successful, an error otherwise.
 func Decrypt(g kyber.Group, fn func() hash.Hash, priv kyber.Scalar, o *drand.ECIES) ([]byte, error) {
+	return DecryptDH(g, fn, func(eph kyber.Point) (kyber.Point, error) {
+		return g.Point().Mul(priv, eph), nil
+	}, o)
+}
+
+// DecryptDH is the same as Decrypt except that the DH exchange is done by the
+// given function, so the private key does not need to be in memory.
+func DecryptDH(g kyber.Group, fn func() hash.Hash, dhFn func(kyber.Point) (kyber.Point, error), o *drand.ECIES) ([]byte, error) {
+	if fn == nil {
+		fn = DefaultHash
+	}
 	eph := g.Point()
 	err := eph.UnmarshalBinary(o.GetEphemeral())
 	if err != nil {
 		return nil, err
 	}
-	dh := g.Point().Mul(priv, eph)
+	dh, err := dhFn(eph)
+	if err != nil {
+		return nil, err
+	}
 	dhBuff, err := dh.MarshalBinary()
 	if err != nil {
 		return nil, err
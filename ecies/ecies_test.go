@@ -10,7 +10,8 @@ import (
 
 func TestECIES(t *testing.T) {
 	msg := []byte("shake that cipher")
-	kp := key.NewKeyPair("127.0.0.1")
+	kp, err := key.NewKeyPair("127.0.0.1")
+	require.NoError(t, err)
 	h := sha256.New
 	cipher, err := Encrypt(key.KeyGroup, h, kp.Public.Key, msg)
 	require.Nil(t, err)
@@ -0,0 +1,90 @@
+package entropy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// SeedSize is the number of bytes read from the user entropy source to form
+// the seed of a CommittedReader
+const SeedSize = 32
+
+// CommittedReader reads a seed once from a user entropy source and then
+// expands it deterministically. Since all the bytes read from it derive from
+// the seed, a node can commit to the seed before using the reader and reveal
+// it afterwards, so anybody can check which entropy it used.
+type CommittedReader struct {
+	seed    []byte
+	counter uint64
+	buff    []byte
+}
+
+var _ io.Reader = &CommittedReader{}
+
+// NewCommittedReader reads SeedSize bytes from the given source and returns a
+// reader expanding them.
+func NewCommittedReader(source io.Reader) (*CommittedReader, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(source, seed); err != nil {
+		return nil, err
+	}
+	return NewSeedReader(seed), nil
+}
+
+// NewSeedReader returns a reader expanding the given seed. It returns the same
+// bytes as the CommittedReader that read this seed.
+func NewSeedReader(seed []byte) *CommittedReader {
+	return &CommittedReader{seed: seed}
+}
+
+// Read fills p with the next bytes of the expansion of the seed. It never
+// returns an error.
+func (c *CommittedReader) Read(p []byte) (int, error) {
+	read := 0
+	for read < len(p) {
+		if len(c.buff) == 0 {
+			var ctr [8]byte
+			binary.BigEndian.PutUint64(ctr[:], c.counter)
+			c.counter++
+			h := sha256.New()
+			h.Write(c.seed)
+			h.Write(ctr[:])
+			c.buff = h.Sum(nil)
+		}
+		n := copy(p[read:], c.buff)
+		c.buff = c.buff[n:]
+		read += n
+	}
+	return read, nil
+}
+
+// Seed returns the seed of the reader. It must only be revealed once the
+// entropy is not secret anymore.
+func (c *CommittedReader) Seed() []byte {
+	return c.seed
+}
+
+// Commitment returns the commitment to the seed of the reader
+func (c *CommittedReader) Commitment() []byte {
+	return Commit(c.seed)
+}
+
+// Commit returns the hash commitment of the given seed
+func Commit(seed []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("drand-entropy-commitment"))
+	h.Write(seed)
+	return h.Sum(nil)
+}
+
+// VerifyReveal returns an error if the seed does not correspond to the
+// commitment
+func VerifyReveal(commitment, seed []byte) error {
+	if !bytes.Equal(Commit(seed), commitment) {
+		return errors.New("entropy: revealed seed does not match commitment")
+	}
+	return nil
+}
@@ -76,3 +76,30 @@ func TestEntropyReadSmallExec(t *testing.T) {
 		t.Fatal("read did not work", n, err)
 	}
 }
+
+func TestCommittedReader(t *testing.T) {
+	source := bytes.NewReader(bytes.Repeat([]byte{0x42}, SeedSize))
+	r, err := NewCommittedReader(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1 := make([]byte, 100)
+	if n, err := r.Read(p1); err != nil || n != len(p1) {
+		t.Fatal("read did not work", n, err)
+	}
+	// the same seed gives the same bytes
+	p2 := make([]byte, 100)
+	NewSeedReader(r.Seed()).Read(p2)
+	if !bytes.Equal(p1, p2) {
+		t.Fatal("expansion of the seed is not deterministic")
+	}
+	if err := VerifyReveal(r.Commitment(), r.Seed()); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReveal(r.Commitment(), p1[:SeedSize]); err == nil {
+		t.Fatal("wrong seed verified")
+	}
+	if _, err := NewCommittedReader(bytes.NewReader([]byte{1, 2})); err == nil {
+		t.Fatal("seed read from a too short source")
+	}
+}
@@ -0,0 +1,246 @@
+// Package gossip propagates the beacons of a drand group over a mesh of nodes
+// and relays. Each member of the mesh verifies a beacon against the
+// distributed key of the group before delivering it and forwarding it to its
+// own peers, so the clients of a relay receive the randomness even if they
+// cannot reach the nodes of the group.
+//
+// The propagation is best-effort: a beacon lost on the way, or arriving more
+// than Window rounds after a more recent one, is not delivered again. The
+// members keep their chain complete by fetching the missing rounds from the
+// chain of a node, as the relays do with SyncChain.
+package gossip
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	clock "github.com/jonboulle/clockwork"
+)
+
+// Window is the number of rounds before the latest one delivered whose
+// beacons are still delivered, so the beacons reaching a member out of order
+// are not dropped.
+const Window = 16
+
+// maxRejected is the number of invalid beacons remembered, above which they
+// are forgotten
+const maxRejected = 1024
+
+// Config holds the group whose beacons are propagated and the peers they are
+// forwarded to.
+type Config struct {
+	// Group is the group producing the beacons, with its distributed key
+	Group *key.Group
+	// Peers are the nodes and relays of the mesh the beacons are forwarded to
+	Peers []net.Peer
+	Clock clock.Clock
+}
+
+// Handler receives the beacons of its peers and of the local node, and
+// forwards the new ones to its peers. Each round is delivered and forwarded
+// once, which stops the propagation once every member of the mesh has seen a
+// beacon. The rounds older than the window of the latest one are ignored.
+type Handler struct {
+	sync.Mutex
+	conf   *Config
+	client net.ProtocolClient
+	// round of the latest beacon delivered
+	latest uint64
+	// rounds delivered within the window of the latest one
+	seen map[uint64]bool
+	// hashes of the invalid beacons received, with their round, so a beacon
+	// sent again is refused without being verified again
+	rejected  map[[sha256.Size]byte]uint64
+	callbacks []func(*beacon.Beacon)
+	l         log.Logger
+}
+
+// NewHandler returns a handler propagating the beacons of the group given in
+// the config through the client.
+func NewHandler(c net.ProtocolClient, conf *Config, l log.Logger) (*Handler, error) {
+	if conf.Group == nil || conf.Group.PublicKey == nil {
+		return nil, errors.New("gossip: group without distributed key")
+	}
+	if conf.Clock == nil {
+		conf.Clock = clock.NewRealClock()
+	}
+	return &Handler{
+		conf:     conf,
+		client:   c,
+		seen:     make(map[uint64]bool),
+		rejected: make(map[[sha256.Size]byte]uint64),
+		l:        l.With("module", "gossip"),
+	}, nil
+}
+
+// AddCallback adds a function called with each new beacon received from the
+// mesh
+func (h *Handler) AddCallback(fn func(*beacon.Beacon)) {
+	h.Lock()
+	defer h.Unlock()
+	h.callbacks = append(h.callbacks, fn)
+}
+
+// Latest returns the round of the latest beacon delivered
+func (h *Handler) Latest() uint64 {
+	h.Lock()
+	defer h.Unlock()
+	return h.latest
+}
+
+// Publish forwards a beacon produced or verified by the local node to the
+// peers.
+func (h *Handler) Publish(b *beacon.Beacon) {
+	if !h.deliver(b.Round) {
+		return
+	}
+	h.forward(packetOf(b))
+}
+
+// ProcessGossip verifies a beacon received from a peer and forwards it to
+// the other peers if it is new. The beacons already seen or older than the
+// window are ignored without being verified again, and the beacons already
+// found invalid are refused without being verified again.
+func (h *Handler) ProcessGossip(c context.Context, p *drand.GossipPacket) (*drand.Empty, error) {
+	round := p.GetRound()
+	id := packetHash(p)
+	h.Lock()
+	isNew := h.isNew(round)
+	_, rejected := h.rejected[id]
+	h.Unlock()
+	if !isNew {
+		return new(drand.Empty), nil
+	}
+	if rejected {
+		return nil, fmt.Errorf("gossip: invalid beacon for round %d", round)
+	}
+	if err := h.verify(p); err != nil {
+		h.l.Debug("gossip_round", round, "invalid", err)
+		return nil, err
+	}
+	if !h.deliver(round) {
+		return new(drand.Empty), nil
+	}
+	h.l.Debug("gossip_round", round, "new_beacon", "forward")
+	h.applyCallbacks(beaconOf(p))
+	h.forward(p)
+	return new(drand.Empty), nil
+}
+
+// verify returns an error if the beacon is not signed by the group or its
+// round can not have happened yet
+func (h *Handler) verify(p *drand.GossipPacket) error {
+	g := h.conf.Group
+	next, _ := beacon.NextRound(h.conf.Clock.Now().Unix(), g.Period, g.GenesisTime)
+	// the next round is accepted to tolerate the clock drifts
+	if p.GetRound() > next {
+		return fmt.Errorf("gossip: round %d in the future, next round is %d", p.GetRound(), next)
+	}
+	if err := beaconOf(p).Verify(g); err != nil {
+		h.reject(p)
+		return fmt.Errorf("gossip: invalid beacon for round %d: %s", p.GetRound(), err)
+	}
+	return nil
+}
+
+// deliver records the round as delivered and returns true if it was not yet
+func (h *Handler) deliver(round uint64) bool {
+	h.Lock()
+	defer h.Unlock()
+	if !h.isNew(round) {
+		return false
+	}
+	h.seen[round] = true
+	if round > h.latest {
+		h.latest = round
+		for r := range h.seen {
+			if r+Window < h.latest {
+				delete(h.seen, r)
+			}
+		}
+		for id, r := range h.rejected {
+			if r+Window < h.latest {
+				delete(h.rejected, id)
+			}
+		}
+	}
+	return true
+}
+
+// reject records the beacon as invalid. The invalid beacons are all
+// forgotten once maxRejected of them are recorded.
+func (h *Handler) reject(p *drand.GossipPacket) {
+	h.Lock()
+	defer h.Unlock()
+	if len(h.rejected) >= maxRejected {
+		h.rejected = make(map[[sha256.Size]byte]uint64)
+	}
+	h.rejected[packetHash(p)] = p.GetRound()
+}
+
+// isNew returns true if the round is within the window and not delivered yet.
+// It must be called with the lock held.
+func (h *Handler) isNew(round uint64) bool {
+	return round > 0 && round+Window >= h.latest && !h.seen[round]
+}
+
+// forward sends the beacon to all the peers
+func (h *Handler) forward(p *drand.GossipPacket) {
+	for _, peer := range h.conf.Peers {
+		go func(peer net.Peer) {
+			if _, err := h.client.Gossip(peer, p); err != nil {
+				h.l.Debug("gossip_round", p.GetRound(), "forward_to", peer.Address(), "err", err)
+			}
+		}(peer)
+	}
+}
+
+func (h *Handler) applyCallbacks(b *beacon.Beacon) {
+	h.Lock()
+	defer h.Unlock()
+	for _, fn := range h.callbacks {
+		go fn(b)
+	}
+}
+
+func beaconOf(p *drand.GossipPacket) *beacon.Beacon {
+	return &beacon.Beacon{
+		PreviousRound: p.GetPreviousRound(),
+		PreviousSig:   p.GetPreviousSig(),
+		Round:         p.GetRound(),
+		Signature:     p.GetSignature(),
+	}
+}
+
+// packetHash returns the hash identifying the content of the packet
+func packetHash(p *drand.GossipPacket) [sha256.Size]byte {
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], p.GetPreviousRound())
+	h.Write(buf[:])
+	h.Write(p.GetPreviousSig())
+	binary.BigEndian.PutUint64(buf[:], p.GetRound())
+	h.Write(buf[:])
+	h.Write(p.GetSignature())
+	var id [sha256.Size]byte
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+func packetOf(b *beacon.Beacon) *drand.GossipPacket {
+	return &drand.GossipPacket{
+		PreviousRound: b.PreviousRound,
+		PreviousSig:   b.PreviousSig,
+		Round:         b.Round,
+		Signature:     b.Signature,
+	}
+}
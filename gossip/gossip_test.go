@@ -0,0 +1,193 @@
+package gossip
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/sign/bls"
+	"github.com/drand/kyber/util/random"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+// gossipServer serves the gossip service of a member of the mesh
+type gossipServer struct {
+	*net.EmptyServer
+	h *Handler
+}
+
+func (g *gossipServer) Beacon(c context.Context, p *drand.GossipPacket) (*drand.Empty, error) {
+	return g.h.ProcessGossip(c, p)
+}
+
+// mesh runs members of a mesh, each on its own listener
+type mesh struct {
+	group     *key.Group
+	private   kyber.Scalar
+	clock     clock.FakeClock
+	handlers  []*Handler
+	listeners []net.Listener
+	received  []chan *beacon.Beacon
+}
+
+// newMesh returns a mesh of n members where member i forwards the beacons
+// to the members given in links[i]
+func newMesh(t *testing.T, n int, links [][]int) *mesh {
+	scheme := bls.NewSchemeOnG2(key.Pairing)
+	private, public := scheme.NewKeyPair(random.New())
+	now := time.Now()
+	m := &mesh{
+		group: &key.Group{
+			Period:      time.Second,
+			GenesisTime: now.Unix() - 100,
+			PublicKey:   &key.DistPublic{Coefficients: []kyber.Point{public}},
+		},
+		private: private,
+		clock:   clock.NewFakeClockAt(now),
+	}
+	addrs := test.Addresses(n)
+	for i := 0; i < n; i++ {
+		var peers []net.Peer
+		for _, j := range links[i] {
+			peers = append(peers, test.NewPeer(addrs[j]))
+		}
+		conf := &Config{Group: m.group, Peers: peers, Clock: m.clock}
+		h, err := NewHandler(net.NewGrpcClient(), conf, log.DefaultLogger)
+		require.NoError(t, err)
+		received := make(chan *beacon.Beacon, 10)
+		h.AddCallback(func(b *beacon.Beacon) { received <- b })
+		lis := net.NewTCPGrpcListener(addrs[i], &gossipServer{h: h})
+		go lis.Start()
+		m.handlers = append(m.handlers, h)
+		m.listeners = append(m.listeners, lis)
+		m.received = append(m.received, received)
+	}
+	time.Sleep(100 * time.Millisecond)
+	return m
+}
+
+func (m *mesh) stop() {
+	for _, l := range m.listeners {
+		l.Stop()
+	}
+}
+
+// beacon returns the beacon of the round signed with the key of the group
+func (m *mesh) beacon(t *testing.T, round uint64) *beacon.Beacon {
+	prevSig := make([]byte, 32)
+	rand.Read(prevSig)
+	msg := beacon.Message(prevSig, round-1, round)
+	sig, err := bls.NewSchemeOnG2(key.Pairing).Sign(m.private, msg)
+	require.NoError(t, err)
+	return &beacon.Beacon{
+		PreviousRound: round - 1,
+		PreviousSig:   prevSig,
+		Round:         round,
+		Signature:     sig,
+	}
+}
+
+func TestGossipMesh(t *testing.T) {
+	// 0 -> 1 -> 2 -> 3 -> 1, with 4 only reachable through 2
+	m := newMesh(t, 5, [][]int{{1}, {2}, {3, 4}, {1}, {}})
+	defer m.stop()
+
+	b := m.beacon(t, 90)
+	m.handlers[0].Publish(b)
+	for i := 1; i < 5; i++ {
+		select {
+		case got := <-m.received[i]:
+			require.True(t, b.Equal(got))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("member %d did not receive the beacon", i)
+		}
+		require.Equal(t, b.Round, m.handlers[i].Latest())
+	}
+	// the cycle through 1 does not deliver the beacon twice
+	time.Sleep(200 * time.Millisecond)
+	for i := 1; i < 5; i++ {
+		require.Len(t, m.received[i], 0)
+	}
+}
+
+func TestGossipVerify(t *testing.T) {
+	m := newMesh(t, 2, [][]int{{1}, {}})
+	defer m.stop()
+	h := m.handlers[0]
+
+	// a beacon not signed by the group is refused and not forwarded
+	invalid := packetOf(m.beacon(t, 90))
+	invalid.Signature = packetOf(m.beacon(t, 91)).Signature
+	_, err := h.ProcessGossip(context.Background(), invalid)
+	require.Error(t, err)
+	// and refused again without being verified
+	require.Len(t, h.rejected, 1)
+	_, err = h.ProcessGossip(context.Background(), invalid)
+	require.Error(t, err)
+
+	// so is a beacon of a round that did not happen yet, which is not
+	// remembered as it can become valid
+	_, err = h.ProcessGossip(context.Background(), packetOf(m.beacon(t, 200)))
+	require.Error(t, err)
+	require.Equal(t, uint64(0), h.Latest())
+	require.Len(t, h.rejected, 1)
+
+	valid := packetOf(m.beacon(t, 90))
+	_, err = h.ProcessGossip(context.Background(), valid)
+	require.NoError(t, err)
+	select {
+	case got := <-m.received[1]:
+		require.Equal(t, valid.GetRound(), got.Round)
+	case <-time.After(5 * time.Second):
+		t.Fatal("valid beacon not forwarded")
+	}
+
+	// the rounds older than the window are ignored
+	_, err = h.ProcessGossip(context.Background(), packetOf(m.beacon(t, 90-Window-1)))
+	require.NoError(t, err)
+	require.Equal(t, valid.GetRound(), h.Latest())
+	select {
+	case got := <-m.received[1]:
+		t.Fatalf("round %d forwarded", got.Round)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestGossipOutOfOrder(t *testing.T) {
+	m := newMesh(t, 2, [][]int{{1}, {}})
+	defer m.stop()
+
+	// a beacon reaching the member after a more recent one is still
+	// delivered and forwarded, once
+	b90, b89 := m.beacon(t, 90), m.beacon(t, 89)
+	for _, b := range []*beacon.Beacon{b90, b89, b89, b90} {
+		_, err := m.handlers[0].ProcessGossip(context.Background(), packetOf(b))
+		require.NoError(t, err)
+	}
+	for i, received := range m.received {
+		rounds := make(map[uint64]bool)
+		for j := 0; j < 2; j++ {
+			select {
+			case got := <-received:
+				rounds[got.Round] = true
+			case <-time.After(5 * time.Second):
+				t.Fatalf("member %d received only %v", i, rounds)
+			}
+		}
+		require.True(t, rounds[b90.Round] && rounds[b89.Round])
+	}
+	require.Equal(t, b90.Round, m.handlers[1].Latest())
+	time.Sleep(200 * time.Millisecond)
+	for _, received := range m.received {
+		require.Len(t, received, 0)
+	}
+}
@@ -0,0 +1,125 @@
+package key
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/drand/kyber/sign/schnorr"
+)
+
+// AddressUpdate announces the new addresses and metadata of a node to the
+// other nodes of its group. It is signed by the longterm key of the node, so
+// the nodes can update the group without running a new DKG, since the hash of
+// the group only depends on the keys.
+type AddressUpdate struct {
+	// Identity is the identity of the node with its new addresses and
+	// metadata, including the proof of possession for the new address
+	Identity *Identity
+	// Timestamp is the unix time at which the update was made. Nodes only
+	// accept updates more recent than the last one they accepted.
+	Timestamp int64
+	// Signature of the update by the longterm key
+	Signature []byte
+}
+
+// NewAddressUpdate returns the update of the given identity, signed with the
+// given signer.
+func NewAddressUpdate(id *Identity, timestamp int64, s Signer) (*AddressUpdate, error) {
+	u := &AddressUpdate{Identity: id, Timestamp: timestamp}
+	msg, err := u.message()
+	if err != nil {
+		return nil, err
+	}
+	if u.Signature, err = s.Sign(msg); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Verify returns an error if the update is not signed by the key of its
+// identity or if the identity does not prove the possession of the key for
+// its new address.
+func (u *AddressUpdate) Verify() error {
+	if u.Identity == nil || u.Identity.Key == nil {
+		return errors.New("key: address update without identity")
+	}
+	if err := u.Identity.ValidSignature(); err != nil {
+		return err
+	}
+	msg, err := u.message()
+	if err != nil {
+		return err
+	}
+	suite, err := SuiteOf(u.Identity.Key)
+	if err != nil {
+		return err
+	}
+	if err := schnorr.Verify(suite.KeyGroup, u.Identity.Key, msg, u.Signature); err != nil {
+		return fmt.Errorf("key: invalid address update signature: %s", err)
+	}
+	return nil
+}
+
+// Apply returns a copy of the group where the identity having the same key as
+// the update carries the new addresses and metadata. The given group and its
+// identities are left untouched, so they can still be read concurrently. It
+// returns an error if the group does not contain the key or if the update is
+// not more recent than the last one applied to the identity.
+func (u *AddressUpdate) Apply(g *Group) (*Group, error) {
+	idx, found := g.Index(u.Identity)
+	if !found {
+		return nil, errors.New("key: address update from a node outside of the group")
+	}
+	if u.Timestamp <= g.Nodes[idx].Updated {
+		return nil, errors.New("key: stale address update")
+	}
+	id := *g.Nodes[idx]
+	id.Addr = u.Identity.Addr
+	id.TLS = u.Identity.TLS
+	id.Signature = u.Identity.Signature
+	id.Addresses = u.Identity.Addresses
+	id.Metadata = u.Identity.Metadata
+	id.TLSKeyHash = u.Identity.TLSKeyHash
+	id.Updated = u.Timestamp
+	ng := *g
+	ng.Nodes = make([]*Identity, len(g.Nodes))
+	copy(ng.Nodes, g.Nodes)
+	ng.Nodes[idx] = &id
+	return &ng, nil
+}
+
+func (u *AddressUpdate) message() ([]byte, error) {
+	id := u.Identity
+	buff, err := id.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("drand-address-update"))
+	h.Write(buff)
+	writeString(h, id.Addr)
+	binary.Write(h, binary.LittleEndian, id.TLS)
+	binary.Write(h, binary.LittleEndian, uint32(len(id.Addresses)))
+	for _, a := range id.Addresses {
+		writeString(h, a)
+	}
+	var meta Metadata
+	if id.Metadata != nil {
+		meta = *id.Metadata
+	}
+	writeString(h, meta.Operator)
+	writeString(h, meta.Contact)
+	writeString(h, meta.Region)
+	writeString(h, string(id.TLSKeyHash))
+	binary.Write(h, binary.LittleEndian, u.Timestamp)
+	return h.Sum(nil), nil
+}
+
+// writeString writes the length prefixed string to the writer
+func writeString(w io.Writer, s string) {
+	binary.Write(w, binary.LittleEndian, uint32(len(s)))
+	w.Write([]byte(s))
+}
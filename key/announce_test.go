@@ -0,0 +1,68 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressUpdate(t *testing.T) {
+	ps, group := BatchIdentities(4)
+	pair := ps[1]
+	pub := *pair.Public
+	pub.Addr = "127.0.0.1:4444"
+	pub.Addresses = []string{"10.0.0.1:4444", "[::1]:4444"}
+	pub.Metadata = &Metadata{Operator: "op", Contact: "op@example.com", Region: "eu"}
+	pub.TLSKeyHash = []byte{1, 2, 3, 4}
+	moved := &Pair{Key: pair.Key, Public: &pub}
+	require.NoError(t, moved.SelfSign())
+
+	update, err := NewAddressUpdate(moved.Public, 1000, moved)
+	require.NoError(t, err)
+	require.NoError(t, update.Verify())
+	updated, err := update.Apply(group)
+	require.NoError(t, err)
+	idx, found := updated.Index(pair.Public)
+	require.True(t, found)
+	require.Equal(t, "127.0.0.1:4444", updated.Nodes[idx].Address())
+	require.Equal(t, pub.Addresses, updated.Nodes[idx].Addresses)
+	require.Equal(t, "eu", updated.Nodes[idx].Metadata.Region)
+	require.Equal(t, pub.TLSKeyHash, updated.Nodes[idx].TLSKeyHash)
+	require.Equal(t, int64(1000), updated.Nodes[idx].Updated)
+	require.NoError(t, updated.Nodes[idx].ValidSignature())
+	// the original group is left untouched
+	require.Equal(t, pair.Public.Address(), group.Nodes[idx].Address())
+	require.Zero(t, group.Nodes[idx].Updated)
+
+	// the same update or an older one is rejected
+	_, err = update.Apply(updated)
+	require.Error(t, err)
+	older, err := NewAddressUpdate(moved.Public, 999, moved)
+	require.NoError(t, err)
+	_, err = older.Apply(updated)
+	require.Error(t, err)
+
+	// any change after signing invalidates the update
+	tampered := *update
+	tampered.Timestamp++
+	require.Error(t, tampered.Verify())
+	id := *update.Identity
+	id.Metadata = &Metadata{Operator: "evil"}
+	tampered = *update
+	tampered.Identity = &id
+	require.Error(t, tampered.Verify())
+
+	// an update signed by another key is rejected
+	forged, err := NewAddressUpdate(moved.Public, 1000, ps[2])
+	require.NoError(t, err)
+	require.Error(t, forged.Verify())
+
+	// an update from outside the group doesn't apply
+	outsider, err := NewKeyPair("127.0.0.1:5555")
+	require.NoError(t, err)
+	update, err = NewAddressUpdate(outsider.Public, 1000, outsider)
+	require.NoError(t, err)
+	require.NoError(t, update.Verify())
+	_, err = update.Apply(group)
+	require.Error(t, err)
+}
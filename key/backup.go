@@ -0,0 +1,182 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
+)
+
+// ShareBackup is one piece of a backup of the private share of a node, split
+// between custodians with a local Shamir secret sharing: any Threshold of the
+// Pieces pieces restore the share. The private scalars of the share, its value
+// followed by the coefficients of its private polynomial, are each split
+// separately while the public commits are copied in every piece.
+type ShareBackup struct {
+	// Index of the piece, from 0 to Pieces-1
+	Index     int
+	Threshold int
+	Pieces    int
+	// ShareIndex is the index of the backed up share in the group
+	ShareIndex int
+	Commits    []kyber.Point
+	// Secrets are the pieces of the share value and of the private polynomial
+	Secrets []kyber.Scalar
+}
+
+// ShareBackupTOML is the TOML representation of a ShareBackup
+type ShareBackupTOML struct {
+	Index      int
+	Threshold  int
+	Pieces     int
+	ShareIndex int
+	Commits    []string
+	Secrets    []string
+	// Scheme is the name of the suite of the share, empty for the default
+	Scheme string `toml:",omitempty"`
+}
+
+// BackupShare splits the share in n pieces, any t of them restoring it.
+func BackupShare(s *Share, t, n int) ([]*ShareBackup, error) {
+	if t < 1 || t > n {
+		return nil, fmt.Errorf("key: invalid backup threshold %d for %d pieces", t, n)
+	}
+	g := s.Suite().KeyGroup
+	secrets := append([]kyber.Scalar{s.Share.V}, s.PrivatePoly...)
+	pieces := make([]*ShareBackup, n)
+	for i := range pieces {
+		pieces[i] = &ShareBackup{
+			Index:      i,
+			Threshold:  t,
+			Pieces:     n,
+			ShareIndex: s.Share.I,
+			Commits:    s.Commits,
+			Secrets:    make([]kyber.Scalar, len(secrets)),
+		}
+	}
+	for j, secret := range secrets {
+		poly := share.NewPriPoly(g, t, secret, random.New())
+		for i, sh := range poly.Shares(n) {
+			pieces[i].Secrets[j] = sh.V
+		}
+	}
+	return pieces, nil
+}
+
+// RestoreShare reconstructs the share from enough pieces of the same backup
+// and checks it against the public polynomial of the share.
+func RestoreShare(pieces []*ShareBackup) (*Share, error) {
+	if len(pieces) == 0 {
+		return nil, errors.New("key: no backup pieces")
+	}
+	first := pieces[0]
+	seen := make(map[int]bool)
+	for _, p := range pieces {
+		if p.Threshold != first.Threshold || p.Pieces != first.Pieces ||
+			p.ShareIndex != first.ShareIndex || len(p.Secrets) != len(first.Secrets) ||
+			!samePoints(p.Commits, first.Commits) {
+			return nil, errors.New("key: backup pieces from different backups")
+		}
+		if p.Index < 0 || p.Index >= p.Pieces {
+			return nil, fmt.Errorf("key: invalid backup piece index %d", p.Index)
+		}
+		if seen[p.Index] {
+			return nil, fmt.Errorf("key: duplicate backup piece %d", p.Index)
+		}
+		seen[p.Index] = true
+	}
+	if len(pieces) < first.Threshold {
+		return nil, fmt.Errorf("key: %d backup pieces given, %d needed", len(pieces), first.Threshold)
+	}
+	g := (&Share{Commits: first.Commits}).Suite().KeyGroup
+	secrets := make([]kyber.Scalar, len(first.Secrets))
+	for j := range secrets {
+		shares := make([]*share.PriShare, len(pieces))
+		for i, p := range pieces {
+			shares[i] = &share.PriShare{I: p.Index, V: p.Secrets[j]}
+		}
+		secret, err := share.RecoverSecret(g, shares, first.Threshold, first.Pieces)
+		if err != nil {
+			return nil, err
+		}
+		secrets[j] = secret
+	}
+	s := &Share{
+		Commits:     first.Commits,
+		Share:       &share.PriShare{I: first.ShareIndex, V: secrets[0]},
+		PrivatePoly: secrets[1:],
+	}
+	expected := s.PubPoly().Eval(s.Share.I).V
+	if !g.Point().Mul(s.Share.V, nil).Equal(expected) {
+		return nil, errors.New("key: restored share does not match the public polynomial")
+	}
+	return s, nil
+}
+
+func samePoints(a, b []kyber.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TOML returns the TOML representation of the backup piece
+func (b *ShareBackup) TOML() interface{} {
+	bt := &ShareBackupTOML{
+		Index:      b.Index,
+		Threshold:  b.Threshold,
+		Pieces:     b.Pieces,
+		ShareIndex: b.ShareIndex,
+		Commits:    make([]string, len(b.Commits)),
+		Secrets:    make([]string, len(b.Secrets)),
+		Scheme:     (&Share{Commits: b.Commits}).Suite().tomlName(),
+	}
+	for i, c := range b.Commits {
+		bt.Commits[i] = PointToString(c)
+	}
+	for i, s := range b.Secrets {
+		bt.Secrets[i] = ScalarToString(s)
+	}
+	return bt
+}
+
+// FromTOML decodes the backup piece from its TOML representation
+func (b *ShareBackup) FromTOML(i interface{}) error {
+	bt, ok := i.(*ShareBackupTOML)
+	if !ok {
+		return errors.New("share backup can't decode from non ShareBackupTOML struct")
+	}
+	b.Index = bt.Index
+	b.Threshold = bt.Threshold
+	b.Pieces = bt.Pieces
+	b.ShareIndex = bt.ShareIndex
+	suite, err := SuiteFromName(bt.Scheme)
+	if err != nil {
+		return err
+	}
+	b.Commits = make([]kyber.Point, len(bt.Commits))
+	for i, c := range bt.Commits {
+		if b.Commits[i], err = StringToPoint(suite.KeyGroup, c); err != nil {
+			return fmt.Errorf("share backup commit %d: %s", i, err)
+		}
+	}
+	b.Secrets = make([]kyber.Scalar, len(bt.Secrets))
+	for i, s := range bt.Secrets {
+		if b.Secrets[i], err = StringToScalar(suite.KeyGroup, s); err != nil {
+			return fmt.Errorf("share backup secret %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the backup piece
+func (b *ShareBackup) TOMLValue() interface{} {
+	return &ShareBackupTOML{}
+}
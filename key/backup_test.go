@@ -0,0 +1,65 @@
+package key
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareBackup(t *testing.T) {
+	poly := share.NewPriPoly(KeyGroup, 3, nil, random.New())
+	_, commits := poly.Commit(KeyGroup.Point().Base()).Info()
+	s := &Share{
+		Commits:     commits,
+		Share:       poly.Eval(2),
+		PrivatePoly: poly.Coefficients(),
+	}
+
+	_, err := BackupShare(s, 4, 3)
+	require.Error(t, err)
+	pieces, err := BackupShare(s, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, pieces, 5)
+
+	// pieces survive their encrypted storage
+	tmp := path.Join(os.TempDir(), "drand-backup")
+	require.NoError(t, os.MkdirAll(tmp, 0700))
+	defer os.RemoveAll(tmp)
+	file := path.Join(tmp, "piece.toml")
+	require.NoError(t, SaveEncrypted(file, pieces[4], []byte("custodian")))
+	loaded := new(ShareBackup)
+	require.Equal(t, ErrPassphrase, LoadEncrypted(file, loaded, []byte("wrong")))
+	require.NoError(t, LoadEncrypted(file, loaded, []byte("custodian")))
+	pieces[4] = loaded
+
+	restored, err := RestoreShare([]*ShareBackup{pieces[4], pieces[0], pieces[2]})
+	require.NoError(t, err)
+	require.Equal(t, s.Share.I, restored.Share.I)
+	require.True(t, s.Share.V.Equal(restored.Share.V))
+	require.Len(t, restored.PrivatePoly, len(s.PrivatePoly))
+	for i := range s.PrivatePoly {
+		require.True(t, s.PrivatePoly[i].Equal(restored.PrivatePoly[i]))
+	}
+
+	// not enough pieces
+	_, err = RestoreShare(pieces[:2])
+	require.Error(t, err)
+	// duplicate pieces
+	_, err = RestoreShare([]*ShareBackup{pieces[0], pieces[0], pieces[1]})
+	require.Error(t, err)
+	// corrupted piece doesn't match the public polynomial
+	corrupted := *pieces[1]
+	corrupted.Secrets = append(corrupted.Secrets[:0:0], corrupted.Secrets...)
+	corrupted.Secrets[0] = KeyGroup.Scalar().Pick(random.New())
+	_, err = RestoreShare([]*ShareBackup{pieces[0], &corrupted, pieces[2]})
+	require.Error(t, err)
+	// pieces of another backup
+	others, err := BackupShare(&Share{Commits: commits[:1], Share: s.Share}, 3, 5)
+	require.NoError(t, err)
+	_, err = RestoreShare([]*ShareBackup{pieces[0], others[1], pieces[2]})
+	require.Error(t, err)
+}
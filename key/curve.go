@@ -1,30 +1,126 @@
 package key
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+
 	bls "github.com/drand/bls12-381"
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/pairing"
+	"github.com/drand/kyber/sign"
 	"github.com/drand/kyber/sign/tbls"
 )
 
-// TODO: global variables are evil, make that a config
+// Suite gathers the cryptographic parameters of a drand group: the pairing
+// curve and the orientation of the keys and signatures on it. The longterm
+// keys of the nodes and the distributed key live on the key group while the
+// beacons are signatures on the other group. It is a parameter of the group,
+// recorded by name in the group file.
+type Suite struct {
+	// Name identifies the suite in the group file and the public API
+	Name    string
+	Pairing pairing.Suite
+	// KeyGroup is the group of the longterm and distributed keys
+	KeyGroup kyber.Group
+	// SigGroup is the group of the signatures; it is always the other group
+	// of the pairing than KeyGroup
+	SigGroup kyber.Group
+	// Scheme is the threshold signature scheme producing the beacons
+	Scheme sign.ThresholdScheme
+}
+
+// Names of the suites supported by drand
+const (
+	// SuiteSigsOnG2 is BLS12-381 with keys on G1 and signatures on G2. It is
+	// the default suite.
+	SuiteSigsOnG2 = "bls12-381-sigs-on-g2"
+	// SuiteSigsOnG1 is BLS12-381 with keys on G2 and signatures on G1, making
+	// the beacons half the size.
+	SuiteSigsOnG1 = "bls12-381-sigs-on-g1"
+)
 
 // Pairing is the main pairing suite used by drand. New interesting curves
 // should be allowed by drand, such as BLS12-381.
 var Pairing = bls.NewBLS12381Suite()
 
-// G1 is the G1 group implementation.
-//var G1 = Pairing.G1()
+var suites = map[string]*Suite{
+	SuiteSigsOnG2: {
+		Name:     SuiteSigsOnG2,
+		Pairing:  Pairing,
+		KeyGroup: Pairing.G1(),
+		SigGroup: Pairing.G2(),
+		Scheme:   tbls.NewThresholdSchemeOnG2(Pairing),
+	},
+	SuiteSigsOnG1: {
+		Name:     SuiteSigsOnG1,
+		Pairing:  Pairing,
+		KeyGroup: Pairing.G2(),
+		SigGroup: Pairing.G1(),
+		Scheme:   tbls.NewThresholdSchemeOnG1(Pairing),
+	},
+}
+
+// DefaultSuite is the suite of the groups not specifying any
+var DefaultSuite = suites[SuiteSigsOnG2]
+
+// KeyGroup is the group used to create the keys of the default suite
+var KeyGroup = DefaultSuite.KeyGroup
+
+// SigGroup is the group used to create the signatures of the default suite;
+// it must always be different than KeyGroup: G1 key group and G2 sig group or
+// G1 sig group and G2 keygroup.
+var SigGroup = DefaultSuite.SigGroup
+
+// Scheme is the signature scheme of the default suite, defining over which
+// curve the signature and keys respectively are.
+var Scheme = DefaultSuite.Scheme
 
-// G2 is the G2 group implementation.
-//var G2 = Pairing.G2()
+// SuiteFromName returns the suite registered under the given name, the
+// default suite if the name is empty.
+func SuiteFromName(name string) (*Suite, error) {
+	if name == "" {
+		return DefaultSuite, nil
+	}
+	s, ok := suites[name]
+	if !ok {
+		return nil, fmt.Errorf("key: unknown scheme %q", name)
+	}
+	return s, nil
+}
 
-// KeyGroup is the group used to create the keys
-var KeyGroup = Pairing.G1()
+// SuiteOf returns the suite whose key group the given point belongs to. It
+// returns an error if the point is nil or does not belong to the key group of
+// any suite.
+func SuiteOf(p kyber.Point) (*Suite, error) {
+	if p == nil {
+		return nil, errors.New("key: no point to find the scheme of")
+	}
+	pType := reflect.TypeOf(p)
+	for _, s := range suites {
+		if reflect.TypeOf(s.KeyGroup.Point()) == pType {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("key: point of unknown scheme (%s)", pType)
+}
 
-// SigGroup is the group used to create the signatures; it must always be
-// different than KeyGroup: G1 key group and G2 sig group or G1 sig group and G2
-// keygroup.
-var SigGroup = Pairing.G2()
+// suiteOf returns the suite of a point created from the key group of one of
+// the suites, as all the points decoded or generated by this package are. It
+// panics otherwise since it denotes a programming error.
+func suiteOf(p kyber.Point) *Suite {
+	s, err := SuiteOf(p)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
 
-// Scheme is the signature scheme used, defining over which curve the signature
-// and keys respectively are.
-var Scheme = tbls.NewThresholdSchemeOnG2(Pairing)
+// tomlName returns the name of the suite to record in a TOML file, empty
+// for the default suite so the files of the default suite stay unchanged.
+func (s *Suite) tomlName() string {
+	if s == DefaultSuite {
+		return ""
+	}
+	return s.Name
+}
@@ -0,0 +1,161 @@
+package key
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/fs"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrPassphrase is returned when an encrypted file can't be decrypted with the
+// given passphrase, or when no passphrase is given.
+var ErrPassphrase = errors.New("key: invalid or missing passphrase for encrypted file")
+
+// scrypt parameters used to derive the encryption key from the passphrase
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptSalt   = 32
+	encryptedKDF = "scrypt"
+	encryptedAD  = "drand-encrypted-file-v1"
+)
+
+// EncryptedTOML is the TOML representation of a file encrypted with a key
+// derived from a passphrase. The plaintext is the TOML encoding of the
+// original content of the file.
+type EncryptedTOML struct {
+	KDF        string
+	Salt       string
+	N          int
+	R          int
+	P          int
+	Nonce      string
+	Ciphertext string
+}
+
+// SaveEncrypted saves the given Tomler encrypted under the passphrase at the
+// given path, with tight permissions.
+func SaveEncrypted(path string, t Tomler, passphrase []byte) error {
+	var plain bytes.Buffer
+	if err := toml.NewEncoder(&plain).Encode(t.TOML()); err != nil {
+		return err
+	}
+	et, err := encrypt(plain.Bytes(), passphrase)
+	if err != nil {
+		return err
+	}
+	fd, err := fs.CreateSecureFile(path)
+	if err != nil {
+		return fmt.Errorf("config: can't save encrypted file to %s: %s", path, err)
+	}
+	defer fd.Close()
+	return toml.NewEncoder(fd).Encode(et)
+}
+
+// LoadEncrypted loads the given Tomler from the file at the given path. The
+// file can be encrypted, in which case it is decrypted using the passphrase,
+// or not.
+func LoadEncrypted(path string, t Tomler, passphrase []byte) error {
+	et := new(EncryptedTOML)
+	if _, err := toml.DecodeFile(path, et); err != nil {
+		return err
+	}
+	if et.Ciphertext == "" {
+		// plain file
+		return Load(path, t)
+	}
+	plain, err := decrypt(et, passphrase)
+	if err != nil {
+		return err
+	}
+	tomlValue := t.TOMLValue()
+	if _, err := toml.Decode(string(plain), tomlValue); err != nil {
+		return err
+	}
+	return t.FromTOML(tomlValue)
+}
+
+// IsEncrypted returns true if the file at the given path is encrypted
+func IsEncrypted(path string) bool {
+	et := new(EncryptedTOML)
+	if _, err := toml.DecodeFile(path, et); err != nil {
+		return false
+	}
+	return et.Ciphertext != ""
+}
+
+func encrypt(plain, passphrase []byte) (*EncryptedTOML, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphrase
+	}
+	salt := make([]byte, scryptSalt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	cipher := aead.Seal(nil, nonce, plain, []byte(encryptedAD))
+	return &EncryptedTOML{
+		KDF:        encryptedKDF,
+		Salt:       hex.EncodeToString(salt),
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(cipher),
+	}, nil
+}
+
+func decrypt(et *EncryptedTOML, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphrase
+	}
+	if et.KDF != encryptedKDF {
+		return nil, fmt.Errorf("key: unknown kdf %q", et.KDF)
+	}
+	salt, err := hex.DecodeString(et.Salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(et.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := hex.DecodeString(et.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(passphrase, salt, et.N, et.R, et.P, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("key: invalid nonce size")
+	}
+	plain, err := aead.Open(nil, nonce, cipher, []byte(encryptedAD))
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return plain, nil
+}
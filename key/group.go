@@ -4,6 +4,7 @@ package key
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -38,6 +39,13 @@ type Group struct {
 	// The distributed public key of this group. It is nil if the group has not
 	// ran a DKG protocol yet.
 	PublicKey *DistPublic
+	// Unchained is true if the beacons sign only their round instead of
+	// chaining to the previous signature, so the message of every round is
+	// known in advance.
+	Unchained bool
+	// RandomnessHash is the name of the hash function deriving the randomness
+	// from the signatures, the default one if empty
+	RandomnessHash string
 }
 
 // Identities return the underlying slice of identities
@@ -66,6 +74,29 @@ func (g *Group) Index(pub *Identity) (int, bool) {
 	return -1, false
 }
 
+// Suite returns the suite of the group, given by the keys of its nodes. All
+// the nodes of a group have keys of the same suite.
+func (g *Group) Suite() *Suite {
+	if len(g.Nodes) == 0 {
+		if g.PublicKey != nil {
+			return g.PublicKey.Suite()
+		}
+		return DefaultSuite
+	}
+	return suiteOf(g.Nodes[0].Key)
+}
+
+// ValidSignatures returns an error if one of the identities of the group is
+// not signed by its key
+func (g *Group) ValidSignatures() error {
+	for _, id := range g.Nodes {
+		if err := id.ValidSignature(); err != nil {
+			return fmt.Errorf("%s: %s", id.Address(), err)
+		}
+	}
+	return nil
+}
+
 // Public returns the public associated to that index
 // or panic otherwise. XXX Change that to return error
 func (g *Group) Public(i int) *Identity {
@@ -85,6 +116,15 @@ func (g *Group) Hash() (string, error) {
 	return hex.EncodeToString(buff), err
 }
 
+// FileHash returns the hex encoded sha256 hash of the content of a group file.
+// Contrary to Hash, it covers every field of the file, such as the addresses,
+// the TLS flags or the period, so it is used to pin a group file given by a
+// third party. It can be computed with the sha256sum tool.
+func FileHash(buff []byte) string {
+	h := sha256.Sum256(buff)
+	return hex.EncodeToString(h[:])
+}
+
 func (g *Group) hashBytes() ([]byte, error) {
 	h := blake2b.New256()
 	// all nodes public keys and positions
@@ -98,6 +138,13 @@ func (g *Group) hashBytes() ([]byte, error) {
 	}
 	binary.Write(h, binary.LittleEndian, uint32(g.Threshold))
 	binary.Write(h, binary.LittleEndian, uint64(g.GenesisTime))
+	if g.Unchained {
+		// chained groups keep the hash they had before the option existed
+		h.Write([]byte("unchained"))
+	}
+	if g.RandomnessHash != "" && g.RandomnessHash != DefaultRandomnessHash {
+		h.Write([]byte(g.RandomnessHash))
+	}
 	return h.Sum(nil), nil
 }
 
@@ -127,9 +174,13 @@ type GroupTOML struct {
 	Period         string
 	Nodes          []*PublicTOML
 	GenesisTime    int64
-	TransitionTime int64  `toml:omitempty`
-	GenesisSeed    string `toml:omitempty`
+	TransitionTime int64  `toml:",omitempty"`
+	GenesisSeed    string `toml:",omitempty"`
 	PublicKey      *DistPublicTOML
+	// Scheme is the name of the suite of the group, empty for the default
+	Scheme         string `toml:",omitempty"`
+	Unchained      bool   `toml:",omitempty"`
+	RandomnessHash string `toml:",omitempty"`
 }
 
 // FromTOML decodes the group from the toml struct
@@ -138,6 +189,10 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 	if !ok {
 		return fmt.Errorf("grouptoml unknown")
 	}
+	suite, err := SuiteFromName(gt.Scheme)
+	if err != nil {
+		return fmt.Errorf("group: %v", err)
+	}
 	g.Threshold = gt.Threshold
 	g.Nodes = make([]*Identity, len(gt.Nodes))
 	for i, ptoml := range gt.Nodes {
@@ -145,6 +200,9 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 		if err := g.Nodes[i].FromTOML(ptoml); err != nil {
 			return fmt.Errorf("group: unwrapping node[%d]: %v", i, err)
 		}
+		if s, err := SuiteOf(g.Nodes[i].Key); err != nil || s != suite {
+			return fmt.Errorf("group: node[%d] has a key of another scheme than %s", i, suite.Name)
+		}
 	}
 
 	if g.Threshold < vss.MinimumT(len(gt.Nodes)) {
@@ -159,12 +217,20 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 		if err = g.PublicKey.FromTOML(gt.PublicKey); err != nil {
 			return fmt.Errorf("group: unwrapping distributed public key: %v", err)
 		}
+		if g.PublicKey.Suite() != suite {
+			return fmt.Errorf("group: distributed key of another scheme than %s", suite.Name)
+		}
 	}
 	g.Period, err = time.ParseDuration(gt.Period)
 	if err != nil {
 		return err
 	}
 	g.GenesisTime = gt.GenesisTime
+	g.Unchained = gt.Unchained
+	if _, err := RandomnessHash(gt.RandomnessHash); err != nil {
+		return fmt.Errorf("group: %v", err)
+	}
+	g.RandomnessHash = gt.RandomnessHash
 	if gt.TransitionTime != 0 {
 		g.TransitionTime = gt.TransitionTime
 	}
@@ -180,6 +246,7 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 func (g *Group) TOML() interface{} {
 	gtoml := &GroupTOML{
 		Threshold: g.Threshold,
+		Scheme:    g.Suite().tomlName(),
 	}
 	gtoml.Nodes = make([]*PublicTOML, g.Len())
 	for i, p := range g.Nodes {
@@ -191,6 +258,8 @@ func (g *Group) TOML() interface{} {
 	}
 	gtoml.Period = g.Period.String()
 	gtoml.GenesisTime = g.GenesisTime
+	gtoml.Unchained = g.Unchained
+	gtoml.RandomnessHash = g.RandomnessHash
 	if g.TransitionTime != 0 {
 		gtoml.TransitionTime = g.TransitionTime
 	}
@@ -225,9 +294,11 @@ func (g *Group) MergeGroup(list []*Identity) *Group {
 	}
 	nl := append(g.Identities(), list...)
 	return &Group{
-		Nodes:     copyAndSort(nl),
-		Threshold: thr,
-		Period:    g.Period,
+		Nodes:          copyAndSort(nl),
+		Threshold:      thr,
+		Period:         g.Period,
+		Unchained:      g.Unchained,
+		RandomnessHash: g.RandomnessHash,
 	}
 }
 
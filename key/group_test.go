@@ -7,6 +7,7 @@ import (
 	"time"
 
 	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/group/edwards25519"
 	"github.com/drand/kyber/util/random"
 	"github.com/stretchr/testify/require"
 )
@@ -59,3 +60,98 @@ func TestGroupSaveLoad(t *testing.T) {
 	require.Equal(t, genesis, loaded.GenesisTime)
 	require.Equal(t, transition, loaded.TransitionTime)
 }
+
+func TestGroupSuite(t *testing.T) {
+	n := 3
+	suite, err := SuiteFromName(SuiteSigsOnG1)
+	require.NoError(t, err)
+	_, err = SuiteFromName("unknown")
+	require.Error(t, err)
+
+	ids := make([]*Identity, n)
+	for i := range ids {
+		pair, err := NewSuiteKeyPair("127.0.0.1:8080", suite, false)
+		require.NoError(t, err)
+		ids[i] = pair.Public
+		require.NoError(t, ids[i].ValidSignature())
+	}
+	dpub := []kyber.Point{suite.KeyGroup.Point().Pick(random.New())}
+	group := LoadGroup(ids, &DistPublic{dpub}, DefaultThreshold(n))
+	group.Period = time.Second
+	require.Equal(t, suite, group.Suite())
+	gtoml := group.TOML().(*GroupTOML)
+	require.Equal(t, SuiteSigsOnG1, gtoml.Scheme)
+	require.Equal(t, SuiteSigsOnG1, gtoml.PublicKey.Scheme)
+
+	loaded := new(Group)
+	require.NoError(t, loaded.FromTOML(gtoml))
+	require.Equal(t, suite, loaded.Suite())
+	require.True(t, loaded.PublicKey.Equal(group.PublicKey))
+	for i := range ids {
+		require.True(t, loaded.Nodes[i].Equal(group.Nodes[i]))
+	}
+
+	// the default suite doesn't change the group files
+	defaultGroup := LoadGroup(newIds(n), &DistPublic{[]kyber.Point{KeyGroup.Point()}}, DefaultThreshold(n))
+	require.Equal(t, DefaultSuite, defaultGroup.Suite())
+	require.Empty(t, defaultGroup.TOML().(*GroupTOML).Scheme)
+
+	// nodes must have keys of the scheme of the group
+	gtoml.Nodes[0] = defaultGroup.TOML().(*GroupTOML).Nodes[0]
+	require.Error(t, new(Group).FromTOML(gtoml))
+}
+
+func TestSuiteOf(t *testing.T) {
+	for _, name := range []string{SuiteSigsOnG2, SuiteSigsOnG1} {
+		suite, err := SuiteFromName(name)
+		require.NoError(t, err)
+		s, err := SuiteOf(suite.KeyGroup.Point())
+		require.NoError(t, err)
+		require.Equal(t, suite, s)
+	}
+	// points of no suite are rejected instead of using the default suite
+	_, err := SuiteOf(nil)
+	require.Error(t, err)
+	_, err = SuiteOf(edwards25519.NewBlakeSHA256Ed25519().Point())
+	require.Error(t, err)
+}
+
+func TestGroupUnchained(t *testing.T) {
+	n := 3
+	group := LoadGroup(newIds(n), nil, DefaultThreshold(n))
+	group.Period = time.Second
+	chainedHash, err := group.Hash()
+	require.NoError(t, err)
+	require.False(t, group.TOML().(*GroupTOML).Unchained)
+
+	group.Unchained = true
+	unchainedHash, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, chainedHash, unchainedHash)
+
+	loaded := new(Group)
+	require.NoError(t, loaded.FromTOML(group.TOML()))
+	require.True(t, loaded.Unchained)
+}
+
+func TestGroupRandomnessHash(t *testing.T) {
+	n := 3
+	group := LoadGroup(newIds(n), nil, DefaultThreshold(n))
+	group.Period = time.Second
+	defaultHash, err := group.Hash()
+	require.NoError(t, err)
+	require.Empty(t, group.TOML().(*GroupTOML).RandomnessHash)
+
+	group.RandomnessHash = HashSHA3
+	sha3Hash, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, defaultHash, sha3Hash)
+
+	loaded := new(Group)
+	require.NoError(t, loaded.FromTOML(group.TOML()))
+	require.Equal(t, HashSHA3, loaded.RandomnessHash)
+
+	gtoml := group.TOML().(*GroupTOML)
+	gtoml.RandomnessHash = "md5"
+	require.Error(t, new(Group).FromTOML(gtoml))
+}
@@ -0,0 +1,61 @@
+package key
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"github.com/dchest/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// Names of the hash functions deriving the randomness from the beacon
+// signatures
+const (
+	HashSHA256  = "sha256"
+	HashBLAKE2b = "blake2b"
+	HashSHA3    = "sha3-256"
+)
+
+// DefaultRandomnessHash is the hash of the groups not specifying any
+const DefaultRandomnessHash = HashSHA256
+
+var randomnessHashes = map[string]func() hash.Hash{
+	HashSHA256:  sha256.New,
+	HashBLAKE2b: blake2b.New256,
+	HashSHA3:    sha3.New256,
+}
+
+// RandomnessHash returns the hash function registered under the given name,
+// the default one if the name is empty.
+func RandomnessHash(name string) (func() hash.Hash, error) {
+	if name == "" {
+		name = DefaultRandomnessHash
+	}
+	h, ok := randomnessHashes[name]
+	if !ok {
+		return nil, fmt.Errorf("key: unknown randomness hash %q", name)
+	}
+	return h, nil
+}
+
+// RandomnessHashName returns the name of the hash function deriving the
+// randomness of the beacons of the group, the default one if the group doesn't
+// specify any.
+func (g *Group) RandomnessHashName() string {
+	if g.RandomnessHash == "" {
+		return DefaultRandomnessHash
+	}
+	return g.RandomnessHash
+}
+
+// RandomnessHashFunc returns the hash function deriving the randomness of the
+// beacons of the group
+func (g *Group) RandomnessHashFunc() func() hash.Hash {
+	h, err := RandomnessHash(g.RandomnessHashName())
+	if err != nil {
+		// the name is checked when loading the group
+		panic(err)
+	}
+	return h
+}
@@ -2,7 +2,10 @@ package key
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -11,6 +14,7 @@ import (
 	kyber "github.com/drand/kyber"
 	"github.com/drand/kyber/share"
 	dkg "github.com/drand/kyber/share/dkg/pedersen"
+	"github.com/drand/kyber/sign/schnorr"
 	"github.com/drand/kyber/util/random"
 )
 
@@ -28,6 +32,32 @@ type Identity struct {
 	Key  kyber.Point
 	Addr string
 	TLS  bool
+	// Rotation certifies the key replaces a previous key of the node, nil if
+	// the key was not rotated
+	Rotation *Rotation
+	// Signature is the proof of possession of the private key, signing the
+	// address, the key, the TLS setting and the TLS key hash of the identity
+	Signature []byte
+	// Addresses are other addresses the node is reachable at, besides Addr,
+	// tried in order when Addr is unreachable
+	Addresses []string
+	// Metadata is optional information about the operator of the node
+	Metadata *Metadata
+	// Updated is the timestamp of the last address update applied to the
+	// identity, 0 if none. Older updates are rejected, even after a restart.
+	Updated int64
+	// TLSKeyHash is the hash of the public key of the TLS certificate of the
+	// node, nil if none is bound to the identity. The identity signature
+	// covers it, so the nodes requiring mutual TLS know the certificate a
+	// node calls them with is its own.
+	TLSKeyHash []byte
+}
+
+// Metadata contains optional information about the operator of a node
+type Metadata struct {
+	Operator string `toml:",omitempty"`
+	Contact  string `toml:",omitempty"`
+	Region   string `toml:",omitempty"`
 }
 
 // Address implements the net.Peer interface
@@ -40,28 +70,113 @@ func (i *Identity) IsTLS() bool {
 	return i.TLS
 }
 
-// NewKeyPair returns a freshly created private / public key pair. The group is
-// decided by the group variable by default. Currently, drand only supports
-// bn256.
-func NewKeyPair(address string) *Pair {
-	key := KeyGroup.Scalar().Pick(random.New())
-	pubKey := KeyGroup.Point().Mul(key, nil)
+// AlternateAddresses implements the net.AlternatePeer interface
+func (i *Identity) AlternateAddresses() []string {
+	return i.Addresses
+}
+
+// BoundTLSKey implements the net.TLSKeyPeer interface
+func (i *Identity) BoundTLSKey() []byte {
+	return i.TLSKeyHash
+}
+
+// TLSKeyHash returns the hash of the public key of the certificate, to bind
+// to an identity.
+func TLSKeyHash(cert *x509.Certificate) []byte {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return h[:]
+}
+
+// NewKeyPair returns a freshly created private / public key pair in the key
+// group of the default suite.
+func NewKeyPair(address string) (*Pair, error) {
+	return NewSuiteKeyPair(address, DefaultSuite, false)
+}
+
+// NewSuiteKeyPair returns a freshly created private / public key pair in the
+// key group of the given suite, with an identity signed by the new key. The
+// nodes of a group must all have keys of the suite of the group.
+func NewSuiteKeyPair(address string, s *Suite, tls bool) (*Pair, error) {
+	key := s.KeyGroup.Scalar().Pick(random.New())
+	pubKey := s.KeyGroup.Point().Mul(key, nil)
 	pub := &Identity{
 		Key:  pubKey,
 		Addr: address,
+		TLS:  tls,
 	}
-	return &Pair{
+	p := &Pair{
 		Key:    key,
 		Public: pub,
 	}
+	if err := p.SelfSign(); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 // NewTLSKeyPair returns a fresh keypair associated with the given address
 // reachable over TLS.
-func NewTLSKeyPair(address string) *Pair {
-	kp := NewKeyPair(address)
-	kp.Public.TLS = true
-	return kp
+func NewTLSKeyPair(address string) (*Pair, error) {
+	return NewSuiteKeyPair(address, DefaultSuite, true)
+}
+
+// ErrNoSignature is returned when an identity does not contain any proof of
+// possession of its key
+var ErrNoSignature = errors.New("key: identity has no signature, sign it with 'drand util self-sign'")
+
+// SelfSign signs the identity of the pair with its private key. It must be
+// called again when the address or the TLS setting of the identity changes.
+func (p *Pair) SelfSign() error {
+	return p.Public.SignWith(p)
+}
+
+// SignWith signs the identity with the given signer, which must hold the
+// private key of the identity, as an ExternalKey does.
+func (i *Identity) SignWith(s Signer) error {
+	msg, err := i.signatureMessage()
+	if err != nil {
+		return err
+	}
+	i.Signature, err = s.Sign(msg)
+	return err
+}
+
+// ValidSignature returns an error if the identity is not signed by its key,
+// i.e. if nothing proves the holder of the identity knows the private key.
+func (i *Identity) ValidSignature() error {
+	if len(i.Signature) == 0 {
+		return ErrNoSignature
+	}
+	msg, err := i.signatureMessage()
+	if err != nil {
+		return err
+	}
+	suite, err := SuiteOf(i.Key)
+	if err != nil {
+		return err
+	}
+	if err := schnorr.Verify(suite.KeyGroup, i.Key, msg, i.Signature); err != nil {
+		return fmt.Errorf("key: invalid identity signature for %s: %s", i.Addr, err)
+	}
+	return nil
+}
+
+func (i *Identity) signatureMessage() ([]byte, error) {
+	buff, err := i.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("drand-identity-proof"))
+	binary.Write(h, binary.LittleEndian, uint32(len(i.Addr)))
+	h.Write([]byte(i.Addr))
+	h.Write(buff)
+	binary.Write(h, binary.LittleEndian, i.TLS)
+	// identities signed before the binding of TLS keys keep their signature
+	if len(i.TLSKeyHash) > 0 {
+		h.Write(i.TLSKeyHash)
+	}
+	return h.Sum(nil), nil
 }
 
 // PairTOML is the TOML-able version of a private key
@@ -71,9 +186,17 @@ type PairTOML struct {
 
 // PublicTOML is the TOML-able version of a public key
 type PublicTOML struct {
-	Address string
-	Key     string
-	TLS     bool
+	Address   string
+	Key       string
+	TLS       bool
+	Rotation  *RotationTOML `toml:",omitempty"`
+	Signature string        `toml:",omitempty"`
+	Addresses []string      `toml:",omitempty"`
+	Metadata  *Metadata     `toml:",omitempty"`
+	Updated   int64         `toml:",omitempty"`
+	TLSKey    string        `toml:",omitempty"`
+	// Scheme is the name of the suite of the key, empty for the default
+	Scheme string `toml:",omitempty"`
 }
 
 // TOML returns a struct that can be marshalled using a TOML-encoding library
@@ -117,24 +240,59 @@ func (i *Identity) FromTOML(t interface{}) error {
 	if !ok {
 		return errors.New("Public can't decode from non PublicTOML struct")
 	}
+	suite, err := SuiteFromName(ptoml.Scheme)
+	if err != nil {
+		return err
+	}
 	buff, err := hex.DecodeString(ptoml.Key)
 	if err != nil {
 		return err
 	}
 	i.Addr = ptoml.Address
-	i.Key = KeyGroup.Point()
+	i.Key = suite.KeyGroup.Point()
 	i.TLS = ptoml.TLS
-	return i.Key.UnmarshalBinary(buff)
+	if err := i.Key.UnmarshalBinary(buff); err != nil {
+		return err
+	}
+	if i.Signature, err = hex.DecodeString(ptoml.Signature); err != nil {
+		return err
+	}
+	if len(i.Signature) == 0 {
+		i.Signature = nil
+	}
+	i.Addresses = ptoml.Addresses
+	i.Metadata = ptoml.Metadata
+	i.Updated = ptoml.Updated
+	if i.TLSKeyHash, err = hex.DecodeString(ptoml.TLSKey); err != nil {
+		return err
+	}
+	if len(i.TLSKeyHash) == 0 {
+		i.TLSKeyHash = nil
+	}
+	if ptoml.Rotation != nil {
+		i.Rotation = new(Rotation)
+		return i.Rotation.FromTOML(ptoml.Rotation)
+	}
+	return nil
 }
 
 // TOML returns a empty TOML-compatible version of the public key
 func (i *Identity) TOML() interface{} {
-	hex := PointToString(i.Key)
-	return &PublicTOML{
-		Address: i.Addr,
-		Key:     hex,
-		TLS:     i.TLS,
+	ptoml := &PublicTOML{
+		Address:   i.Addr,
+		Key:       PointToString(i.Key),
+		TLS:       i.TLS,
+		Signature: hex.EncodeToString(i.Signature),
+		Addresses: i.Addresses,
+		Metadata:  i.Metadata,
+		Updated:   i.Updated,
+		TLSKey:    hex.EncodeToString(i.TLSKeyHash),
+		Scheme:    suiteOf(i.Key).tomlName(),
+	}
+	if i.Rotation != nil {
+		ptoml.Rotation = i.Rotation.TOML().(*RotationTOML)
 	}
+	return ptoml
 }
 
 // TOMLValue returns a TOML-compatible interface value
@@ -166,7 +324,16 @@ type Share dkg.DistKeyShare
 // PubPoly returns the public polynomial that can be used to verify any
 // individual patial signature
 func (s *Share) PubPoly() *share.PubPoly {
-	return share.NewPubPoly(KeyGroup, KeyGroup.Point().Base(), s.Commits)
+	g := s.Suite().KeyGroup
+	return share.NewPubPoly(g, g.Point().Base(), s.Commits)
+}
+
+// Suite returns the suite of the distributed key of the share
+func (s *Share) Suite() *Suite {
+	if len(s.Commits) == 0 {
+		return DefaultSuite
+	}
+	return suiteOf(s.Commits[0])
 }
 
 // PrivateShare returns the private share used to produce a partial signature
@@ -193,6 +360,7 @@ func (s *Share) TOML() interface{} {
 	}
 	dtoml.Share = ScalarToString(s.Share.V)
 	dtoml.Index = s.Share.I
+	dtoml.Scheme = s.Suite().tomlName()
 	return dtoml
 }
 
@@ -202,9 +370,13 @@ func (s *Share) FromTOML(i interface{}) error {
 	if !ok {
 		return errors.New("invalid struct received for share")
 	}
+	suite, err := SuiteFromName(t.Scheme)
+	if err != nil {
+		return err
+	}
 	s.Commits = make([]kyber.Point, len(t.Commits))
 	for i, c := range t.Commits {
-		p, err := StringToPoint(KeyGroup, c)
+		p, err := StringToPoint(suite.KeyGroup, c)
 		if err != nil {
 			return fmt.Errorf("share.Commit[%d] corruputed: %s", i, err)
 		}
@@ -213,13 +385,13 @@ func (s *Share) FromTOML(i interface{}) error {
 
 	s.PrivatePoly = make([]kyber.Scalar, len(t.PrivatePoly))
 	for i, c := range t.PrivatePoly {
-		coeff, err := StringToScalar(KeyGroup, c)
+		coeff, err := StringToScalar(suite.KeyGroup, c)
 		if err != nil {
 			return fmt.Errorf("share.PrivatePoly[%d] corrupted: %s", i, err)
 		}
 		s.PrivatePoly[i] = coeff
 	}
-	sshare, err := StringToScalar(KeyGroup, t.Share)
+	sshare, err := StringToScalar(suite.KeyGroup, t.Share)
 	if err != nil {
 		return fmt.Errorf("share.Share corrupted: %s", err)
 	}
@@ -243,6 +415,8 @@ type ShareTOML struct {
 	// coefficients of the individual private polynomial generated by the node
 	// at the given index.
 	PrivatePoly []string
+	// name of the suite of the share, empty for the default
+	Scheme string `toml:",omitempty"`
 }
 
 // DistPublic represents the distributed public key generated during a DKG. This
@@ -262,6 +436,8 @@ func (d *DistPublic) Key() kyber.Point {
 // DistPublicTOML is a TOML compatible value of a DistPublic
 type DistPublicTOML struct {
 	Coefficients []string
+	// Scheme is the name of the suite of the key, empty for the default
+	Scheme string `toml:",omitempty"`
 }
 
 // TOML returns a TOML-compatible version of d
@@ -270,7 +446,15 @@ func (d *DistPublic) TOML() interface{} {
 	for i, s := range d.Coefficients {
 		strings[i] = PointToString(s)
 	}
-	return &DistPublicTOML{strings}
+	return &DistPublicTOML{Coefficients: strings, Scheme: d.Suite().tomlName()}
+}
+
+// Suite returns the suite of the distributed key
+func (d *DistPublic) Suite() *Suite {
+	if len(d.Coefficients) == 0 {
+		return DefaultSuite
+	}
+	return suiteOf(d.Coefficients[0])
 }
 
 // FromTOML initializes d from the TOML-compatible version of a DistPublic
@@ -279,10 +463,13 @@ func (d *DistPublic) FromTOML(i interface{}) error {
 	if !ok {
 		return errors.New("wrong interface: expected DistPublicTOML")
 	}
+	suite, err := SuiteFromName(dtoml.Scheme)
+	if err != nil {
+		return err
+	}
 	points := make([]kyber.Point, len(dtoml.Coefficients))
-	var err error
 	for i, s := range dtoml.Coefficients {
-		points[i], err = StringToPoint(KeyGroup, s)
+		points[i], err = StringToPoint(suite.KeyGroup, s)
 		if err != nil {
 			return err
 		}
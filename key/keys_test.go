@@ -16,7 +16,10 @@ import (
 
 func TestKeyPublic(t *testing.T) {
 	addr := "127.0.0.1:80"
-	kp := NewTLSKeyPair(addr)
+	kp, err := NewTLSKeyPair(addr)
+	require.NoError(t, err)
+	kp.Public.TLSKeyHash = []byte{1, 2, 3, 4}
+	require.NoError(t, kp.SelfSign())
 	ptoml := kp.Public.TOML().(*PublicTOML)
 	require.Equal(t, kp.Public.Addr, ptoml.Address)
 	require.Equal(t, kp.Public.TLS, ptoml.TLS)
@@ -27,13 +30,44 @@ func TestKeyPublic(t *testing.T) {
 
 	p2 := new(Identity)
 	p2toml := new(PublicTOML)
-	_, err := toml.DecodeReader(&writer, p2toml)
+	_, err = toml.DecodeReader(&writer, p2toml)
 	require.NoError(t, err)
 	require.NoError(t, p2.FromTOML(p2toml))
 
 	require.Equal(t, kp.Public.Addr, p2.Addr)
 	require.Equal(t, kp.Public.TLS, p2.TLS)
 	require.Equal(t, kp.Public.Key.String(), p2.Key.String())
+	require.Equal(t, kp.Public.TLSKeyHash, p2.TLSKeyHash)
+	require.NoError(t, p2.ValidSignature())
+}
+
+func TestKeySignature(t *testing.T) {
+	kp, err := NewTLSKeyPair("127.0.0.1:80")
+	require.NoError(t, err)
+	require.NoError(t, kp.Public.ValidSignature())
+
+	// changing the address or the TLS setting invalidates the signature
+	kp.Public.Addr = "127.0.0.1:81"
+	require.Error(t, kp.Public.ValidSignature())
+	require.NoError(t, kp.SelfSign())
+	require.NoError(t, kp.Public.ValidSignature())
+	kp.Public.TLS = false
+	require.Error(t, kp.Public.ValidSignature())
+	// so does binding another TLS key
+	kp.Public.TLS = true
+	kp.Public.TLSKeyHash = []byte{1, 2, 3, 4}
+	require.Error(t, kp.Public.ValidSignature())
+
+	// a signature of another key is rejected
+	other, err := NewTLSKeyPair("127.0.0.1:81")
+	require.NoError(t, err)
+	rogue := &Identity{Key: other.Public.Key, Addr: kp.Public.Addr, TLS: true, Signature: kp.Public.Signature}
+	require.Error(t, rogue.ValidSignature())
+
+	rogue.Signature = nil
+	require.Equal(t, ErrNoSignature, rogue.ValidSignature())
+	group := &Group{Nodes: []*Identity{other.Public, rogue}}
+	require.Error(t, group.ValidSignatures())
 }
 
 func TestKeyDistributedPublic(t *testing.T) {
@@ -112,7 +146,10 @@ func BatchIdentities(n int) ([]*Pair, *Group) {
 	for i := 0; i < n; i++ {
 		port := strconv.Itoa(startPort + i)
 		addr := startAddr + port
-		privs[i] = NewTLSKeyPair(addr)
+		var err error
+		if privs[i], err = NewTLSKeyPair(addr); err != nil {
+			panic(err)
+		}
 		pubs[i] = privs[i].Public
 	}
 	fakeDistKey := KeyGroup.Point().Pick(random.New())
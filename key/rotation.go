@@ -0,0 +1,160 @@
+package key
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/sign/schnorr"
+)
+
+// Rotation is a certificate, attached to an identity, stating that the owner
+// of the old longterm key replaced it by the key of the identity. It is signed
+// by the old key so the nodes can replace the old key by the new one in the
+// old group during a resharing.
+type Rotation struct {
+	OldKey    kyber.Point
+	Signature []byte
+}
+
+// RotationTOML is the TOML representation of a Rotation
+type RotationTOML struct {
+	OldKey    string
+	Signature string
+	// Scheme is the name of the suite of the old key, empty for the default
+	Scheme string `toml:",omitempty"`
+}
+
+// rotationMessage returns the message signed by the old key to certify the
+// rotation to the new key
+func rotationMessage(oldKey, newKey kyber.Point) ([]byte, error) {
+	oldBuff, err := oldKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	newBuff, err := newKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("drand-key-rotation"))
+	h.Write(oldBuff)
+	h.Write(newBuff)
+	return h.Sum(nil), nil
+}
+
+// NewRotation returns the rotation certificate from the old pair to the new
+// key
+func NewRotation(old *Pair, newKey kyber.Point) (*Rotation, error) {
+	msg, err := rotationMessage(old.Public.Key, newKey)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := old.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Rotation{OldKey: old.Public.Key, Signature: sig}, nil
+}
+
+// Verify returns an error if the certificate is not a valid rotation to the
+// given new key
+func (r *Rotation) Verify(newKey kyber.Point) error {
+	msg, err := rotationMessage(r.OldKey, newKey)
+	if err != nil {
+		return err
+	}
+	suite, err := SuiteOf(r.OldKey)
+	if err != nil {
+		return err
+	}
+	if err := schnorr.Verify(suite.KeyGroup, r.OldKey, msg, r.Signature); err != nil {
+		return fmt.Errorf("key: invalid rotation certificate: %s", err)
+	}
+	return nil
+}
+
+// TOML returns the TOML representation of the rotation
+func (r *Rotation) TOML() interface{} {
+	return &RotationTOML{
+		OldKey:    PointToString(r.OldKey),
+		Signature: hex.EncodeToString(r.Signature),
+		Scheme:    suiteOf(r.OldKey).tomlName(),
+	}
+}
+
+// FromTOML decodes the rotation from its TOML representation
+func (r *Rotation) FromTOML(i interface{}) error {
+	rt, ok := i.(*RotationTOML)
+	if !ok {
+		return errors.New("rotation can't decode from non RotationTOML struct")
+	}
+	suite, err := SuiteFromName(rt.Scheme)
+	if err != nil {
+		return err
+	}
+	buff, err := hex.DecodeString(rt.OldKey)
+	if err != nil {
+		return err
+	}
+	r.OldKey = suite.KeyGroup.Point()
+	if err := r.OldKey.UnmarshalBinary(buff); err != nil {
+		return err
+	}
+	r.Signature, err = hex.DecodeString(rt.Signature)
+	return err
+}
+
+// TOMLValue returns an empty TOML-compatible value of the rotation
+func (r *Rotation) TOMLValue() interface{} {
+	return &RotationTOML{}
+}
+
+// RotateKeyPair returns a fresh key pair with the same address as the given
+// pair, certified by a rotation signed with the given pair.
+func RotateKeyPair(old *Pair) (*Pair, error) {
+	suite, err := SuiteOf(old.Public.Key)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewSuiteKeyPair(old.Public.Address(), suite, old.Public.TLS)
+	if err != nil {
+		return nil, err
+	}
+	rotation, err := NewRotation(old, p.Public.Key)
+	if err != nil {
+		return nil, err
+	}
+	p.Public.Rotation = rotation
+	return p, nil
+}
+
+// ApplyRotations returns a copy of the old group where the keys rotated by
+// the identities of the new group are replaced by the new identities. The
+// nodes keep their positions, so their shares keep their indexes. It returns
+// an error if a rotation of an identity of the old group is invalid.
+func ApplyRotations(old, new *Group) (*Group, error) {
+	nodes := make([]*Identity, old.Len())
+	copy(nodes, old.Nodes)
+	for _, id := range new.Nodes {
+		if id.Rotation == nil {
+			continue
+		}
+		for i, oldID := range old.Nodes {
+			if !oldID.Key.Equal(id.Rotation.OldKey) {
+				continue
+			}
+			if err := id.Rotation.Verify(id.Key); err != nil {
+				return nil, fmt.Errorf("%s: %s", id.Address(), err)
+			}
+			nodes[i] = id
+		}
+	}
+	rotated := *old
+	// the seed derives from the original keys
+	rotated.GenesisSeed = old.GetGenesisSeed()
+	rotated.Nodes = nodes
+	return &rotated, nil
+}
@@ -0,0 +1,56 @@
+package key
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotation(t *testing.T) {
+	ps, group := BatchIdentities(4)
+	old := ps[1]
+	rotated, err := RotateKeyPair(old)
+	require.NoError(t, err)
+	require.Equal(t, old.Public.Address(), rotated.Public.Address())
+	require.False(t, rotated.Public.Key.Equal(old.Public.Key))
+	require.NoError(t, rotated.Public.Rotation.Verify(rotated.Public.Key))
+	require.Error(t, rotated.Public.Rotation.Verify(ps[2].Public.Key))
+
+	// the rotation survives the TOML encoding of the public key
+	var buff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&buff).Encode(rotated.Public.TOML()))
+	ptoml := new(PublicTOML)
+	_, err = toml.Decode(buff.String(), ptoml)
+	require.NoError(t, err)
+	id := new(Identity)
+	require.NoError(t, id.FromTOML(ptoml))
+	require.NotNil(t, id.Rotation)
+	require.NoError(t, id.Rotation.Verify(id.Key))
+
+	// the rotated key takes the position of the old key
+	oldIdx, ok := group.Index(old.Public)
+	require.True(t, ok)
+	newIDs := append([]*Identity{id}, ps[2].Public, ps[3].Public)
+	newGroup := NewGroup(newIDs, 3, group.GenesisTime)
+	applied, err := ApplyRotations(group, newGroup)
+	require.NoError(t, err)
+	require.Equal(t, group.Len(), applied.Len())
+	require.True(t, applied.Nodes[oldIdx].Equal(id))
+	require.Equal(t, group.GetGenesisSeed(), applied.GenesisSeed)
+	for i := range group.Nodes {
+		if i != oldIdx {
+			require.True(t, applied.Nodes[i].Equal(group.Nodes[i]))
+		}
+	}
+	// the original group is untouched
+	require.True(t, group.Nodes[oldIdx].Equal(old.Public))
+
+	// a rotation not signed by the old key is rejected
+	forged := *id
+	forged.Rotation = &Rotation{OldKey: old.Public.Key, Signature: id.Rotation.Signature}
+	forged.Key = ps[0].Public.Key
+	_, err = ApplyRotations(group, NewGroup([]*Identity{&forged, ps[2].Public, ps[3].Public}, 3, 0))
+	require.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package key
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/sign/schnorr"
+)
+
+// Signer signs messages with the longterm private key of a node. Signatures
+// are schnorr signatures verifiable with the public key of the identity.
+type Signer interface {
+	Sign(msg []byte) ([]byte, error)
+}
+
+// Decrypter performs the Diffie-Hellman operation needed to decrypt ECIES
+// ciphertexts encrypted to the longterm public key of a node.
+type Decrypter interface {
+	// DH returns the given point multiplied by the private key
+	DH(p kyber.Point) (kyber.Point, error)
+}
+
+// Longterm groups all operations made with the longterm private key. The
+// Pair loaded from the file store is the default implementation and
+// ExternalKey delegates them to an external process.
+type Longterm interface {
+	Signer
+	Decrypter
+}
+
+var _ Longterm = (*Pair)(nil)
+var _ Longterm = (*ExternalKey)(nil)
+
+// Sign implements the Signer interface
+func (p *Pair) Sign(msg []byte) ([]byte, error) {
+	// a pair without public key is a key of the default suite
+	suite := DefaultSuite
+	if p.Public != nil && p.Public.Key != nil {
+		var err error
+		if suite, err = SuiteOf(p.Public.Key); err != nil {
+			return nil, err
+		}
+	}
+	return schnorr.Sign(suite.KeyGroup.(schnorr.Suite), p.Key, msg)
+}
+
+// DH implements the Decrypter interface
+func (p *Pair) DH(point kyber.Point) (kyber.Point, error) {
+	suite, err := SuiteOf(point)
+	if err != nil {
+		return nil, err
+	}
+	return suite.KeyGroup.Point().Mul(p.Key, point), nil
+}
+
+// DefaultExternalKeyTimeout is the maximum time to wait for the external
+// process to answer a request
+const DefaultExternalKeyTimeout = 5 * time.Second
+
+// Operations supported by the external key protocol
+const (
+	ExternalOpPublic = "public"
+	ExternalOpSign   = "sign"
+	ExternalOpDH     = "dh"
+)
+
+// ExternalRequest is sent by drand to the external key process. The protocol
+// is one JSON encoded request per line over a local socket, answered by one
+// JSON encoded ExternalResponse per line. Data and Result are hex encoded.
+type ExternalRequest struct {
+	Op   string
+	Data string
+}
+
+// ExternalResponse is the answer of the external key process to a request.
+// Error is set if the request failed.
+type ExternalResponse struct {
+	Result string
+	Error  string
+}
+
+// ExternalKey implements Longterm by asking an external process, typically a
+// key management daemon, to perform the operations over a unix socket.
+type ExternalKey struct {
+	socket  string
+	timeout time.Duration
+}
+
+// NewExternalKey returns an ExternalKey talking to the process listening on
+// the given unix socket.
+func NewExternalKey(socket string) *ExternalKey {
+	return &ExternalKey{socket: socket, timeout: DefaultExternalKeyTimeout}
+}
+
+// PublicKey returns the public key of the external process
+func (e *ExternalKey) PublicKey() (kyber.Point, error) {
+	buff, err := e.request(ExternalOpPublic, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the process doesn't tell the suite of its key, the size of the
+	// encoding does
+	for _, s := range suites {
+		if s.KeyGroup.PointLen() == len(buff) {
+			p := s.KeyGroup.Point()
+			return p, p.UnmarshalBinary(buff)
+		}
+	}
+	return nil, errors.New("key: external public key of unknown size")
+}
+
+// Sign implements the Signer interface
+func (e *ExternalKey) Sign(msg []byte) ([]byte, error) {
+	return e.request(ExternalOpSign, msg)
+}
+
+// DH implements the Decrypter interface
+func (e *ExternalKey) DH(point kyber.Point) (kyber.Point, error) {
+	buff, err := point.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.request(ExternalOpDH, buff)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := SuiteOf(point)
+	if err != nil {
+		return nil, err
+	}
+	p := suite.KeyGroup.Point()
+	return p, p.UnmarshalBinary(res)
+}
+
+func (e *ExternalKey) request(op string, data []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", e.socket, e.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("key: can't reach external key process: %s", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(e.timeout))
+	req := &ExternalRequest{Op: op, Data: hex.EncodeToString(data)}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("key: external key process: %s", err)
+	}
+	var resp ExternalResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("key: external key process: %s", resp.Error)
+	}
+	return hex.DecodeString(resp.Result)
+}
+
+// ServeExternalKey answers the requests of the external key protocol on the
+// given listener using the given pair. It is a software stand-in for an
+// external key process and returns when the listener is closed.
+func ServeExternalKey(l net.Listener, p *Pair) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go serveExternalConn(conn, p)
+	}
+}
+
+func serveExternalConn(conn net.Conn, p *Pair) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var resp ExternalResponse
+		result, err := answerExternal(line, p)
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Result = hex.EncodeToString(result)
+		}
+		if err := enc.Encode(&resp); err != nil {
+			return
+		}
+	}
+}
+
+func answerExternal(line []byte, p *Pair) ([]byte, error) {
+	req := new(ExternalRequest)
+	if err := json.Unmarshal(line, req); err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Op {
+	case ExternalOpPublic:
+		return p.Public.Key.MarshalBinary()
+	case ExternalOpSign:
+		return p.Sign(data)
+	case ExternalOpDH:
+		suite, err := SuiteOf(p.Public.Key)
+		if err != nil {
+			return nil, err
+		}
+		point := suite.KeyGroup.Point()
+		if err := point.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		dh, err := p.DH(point)
+		if err != nil {
+			return nil, err
+		}
+		return dh.MarshalBinary()
+	default:
+		return nil, errors.New("unknown operation " + req.Op)
+	}
+}
@@ -0,0 +1,48 @@
+package key
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/drand/drand/ecies"
+	"github.com/drand/kyber/sign/schnorr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "key.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+
+	pair, err := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, err)
+	go ServeExternalKey(l, pair)
+
+	ext := NewExternalKey(socket)
+	pub, err := ext.PublicKey()
+	require.NoError(t, err)
+	require.True(t, pub.Equal(pair.Public.Key))
+
+	msg := []byte("hello world")
+	sig, err := ext.Sign(msg)
+	require.NoError(t, err)
+	require.NoError(t, schnorr.Verify(KeyGroup, pair.Public.Key, msg, sig))
+
+	obj, err := ecies.Encrypt(KeyGroup, nil, pair.Public.Key, msg)
+	require.NoError(t, err)
+	for _, lt := range []Longterm{pair, ext} {
+		plain, err := ecies.DecryptDH(KeyGroup, nil, lt.DH, obj)
+		require.NoError(t, err)
+		require.Equal(t, msg, plain)
+	}
+
+	_, err = NewExternalKey(path.Join(dir, "none.sock")).Sign(msg)
+	require.Error(t, err)
+}
@@ -21,6 +21,18 @@ type Store interface {
 	// LoadKeyPair loads the private/public key pair associated with the drand
 	// operator
 	LoadKeyPair() (*Pair, error)
+	// SaveIdentity saves only the public identity, for a node whose private
+	// key is held outside of the store
+	SaveIdentity(i *Identity) error
+	// LoadIdentity loads only the public identity of the drand operator
+	LoadIdentity() (*Identity, error)
+	// SavePendingKeyPair saves a new key pair that only replaces the current
+	// one once CommitPendingKeyPair is called
+	SavePendingKeyPair(p *Pair) error
+	// LoadPendingKeyPair loads the pending key pair, if any
+	LoadPendingKeyPair() (*Pair, error)
+	// CommitPendingKeyPair replaces the current key pair by the pending one
+	CommitPendingKeyPair() error
 	SaveShare(share *Share) error
 	LoadShare() (*Share, error)
 	SaveGroup(*Group) error
@@ -49,6 +61,8 @@ const GroupFolderName = "groups"
 const keyFileName = "drand_id"
 const privateExtension = ".private"
 const publicExtension = ".public"
+const pendingExtension = ".pending"
+const oldExtension = ".old"
 const groupFileName = "drand_group.toml"
 const shareFileName = "dist_key.private"
 const distKeyFileName = "dist_key.public"
@@ -68,6 +82,8 @@ type fileStore struct {
 	shareFile      string
 	distKeyFile    string
 	groupFile      string
+	// passphrase used to encrypt the private key and the share, if any
+	passphrase []byte
 }
 
 // NewFileStore is used to create the config folder and all the subfolders.
@@ -89,10 +105,31 @@ func NewFileStore(baseFolder string) Store {
 	return store
 }
 
+// NewEncryptedFileStore returns a file store that encrypts the private key and
+// the private share under the given passphrase. Plain files are still loaded,
+// so existing configurations can be migrated by loading and saving them again.
+func NewEncryptedFileStore(baseFolder string, passphrase []byte) Store {
+	store := NewFileStore(baseFolder).(*fileStore)
+	store.passphrase = passphrase
+	return store
+}
+
+// PrivateKeyFile returns the path of the private key file in the given config
+// folder
+func PrivateKeyFile(baseFolder string) string {
+	return path.Join(baseFolder, KeyFolderName, keyFileName) + privateExtension
+}
+
+// ShareFile returns the path of the private share file in the given config
+// folder
+func ShareFile(baseFolder string) string {
+	return path.Join(baseFolder, GroupFolderName, shareFileName)
+}
+
 // SaveKeyPair first saves the private key in a file with tight permissions and then
 // saves the public part in another file.
 func (f *fileStore) SaveKeyPair(p *Pair) error {
-	if err := Save(f.privateKeyFile, p, true); err != nil {
+	if err := f.savePrivate(f.privateKeyFile, p); err != nil {
 		return err
 	}
 	fmt.Printf("Saved the key : %s at %s\n", p.Public.Addr, f.publicKeyFile)
@@ -101,11 +138,59 @@ func (f *fileStore) SaveKeyPair(p *Pair) error {
 
 // LoadKeyPair decode private key first then public
 func (f *fileStore) LoadKeyPair() (*Pair, error) {
+	return f.loadKeyPair(f.privateKeyFile, f.publicKeyFile)
+}
+
+func (f *fileStore) loadKeyPair(privateFile, publicFile string) (*Pair, error) {
 	p := new(Pair)
-	if err := Load(f.privateKeyFile, p); err != nil {
+	if err := f.loadPrivate(privateFile, p); err != nil {
 		return nil, err
 	}
-	return p, Load(f.publicKeyFile, p.Public)
+	return p, Load(publicFile, p.Public)
+}
+
+// SaveIdentity saves the public identity without touching the private key
+// file.
+func (f *fileStore) SaveIdentity(i *Identity) error {
+	fmt.Printf("Saved the key : %s at %s\n", i.Addr, f.publicKeyFile)
+	return Save(f.publicKeyFile, i, false)
+}
+
+// LoadIdentity decodes the public identity only.
+func (f *fileStore) LoadIdentity() (*Identity, error) {
+	i := new(Identity)
+	return i, Load(f.publicKeyFile, i)
+}
+
+// SavePendingKeyPair saves the key pair next to the current one, with the
+// ".pending" extension.
+func (f *fileStore) SavePendingKeyPair(p *Pair) error {
+	if err := f.savePrivate(f.privateKeyFile+pendingExtension, p); err != nil {
+		return err
+	}
+	return Save(f.publicKeyFile+pendingExtension, p.Public, false)
+}
+
+// LoadPendingKeyPair returns ErrAbsent if there is no pending key pair
+func (f *fileStore) LoadPendingKeyPair() (*Pair, error) {
+	if ok, _ := fs.Exists(f.privateKeyFile + pendingExtension); !ok {
+		return nil, ErrAbsent
+	}
+	return f.loadKeyPair(f.privateKeyFile+pendingExtension, f.publicKeyFile+pendingExtension)
+}
+
+// CommitPendingKeyPair moves the pending key pair in place of the current one,
+// which is kept with the ".old" extension.
+func (f *fileStore) CommitPendingKeyPair() error {
+	for _, file := range []string{f.privateKeyFile, f.publicKeyFile} {
+		if err := os.Rename(file, file+oldExtension); err != nil {
+			return err
+		}
+		if err := os.Rename(file+pendingExtension, file); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (f *fileStore) LoadGroup() (*Group, error) {
@@ -119,12 +204,12 @@ func (f *fileStore) SaveGroup(g *Group) error {
 
 func (f *fileStore) SaveShare(share *Share) error {
 	fmt.Printf("crypto store: saving private share in %s\n", f.shareFile)
-	return Save(f.shareFile, share, true)
+	return f.savePrivate(f.shareFile, share)
 }
 
 func (f *fileStore) LoadShare() (*Share, error) {
 	s := new(Share)
-	return s, Load(f.shareFile, s)
+	return s, f.loadPrivate(f.shareFile, s)
 }
 
 func (f *fileStore) SaveDistPublic(d *DistPublic) error {
@@ -137,6 +222,20 @@ func (f *fileStore) LoadDistPublic() (*DistPublic, error) {
 	return d, Load(f.distKeyFile, d)
 }
 
+// savePrivate saves the private material encrypted if the store has a
+// passphrase, in plain otherwise.
+func (f *fileStore) savePrivate(path string, t Tomler) error {
+	if f.passphrase != nil {
+		return SaveEncrypted(path, t, f.passphrase)
+	}
+	return Save(path, t, true)
+}
+
+// loadPrivate loads the private material, decrypting it if needed.
+func (f *fileStore) loadPrivate(path string, t Tomler) error {
+	return LoadEncrypted(path, t, f.passphrase)
+}
+
 func (f *fileStore) Reset(...ResetOption) error {
 	if err := Delete(f.distKeyFile); err != nil {
 		return fmt.Errorf("drand: err deleting dist. key file: %v", err)
@@ -37,6 +37,17 @@ func TestKeysSaveLoad(t *testing.T) {
 	//require.True(t, fs.FileExists(store.privateKeyFile))
 	//require.True(t, fs.FileExists(store.publicKeyFile))
 
+	// the identity is loaded and saved without the private key
+	loadedID, err := store.LoadIdentity()
+	require.NoError(t, err)
+	require.True(t, loadedID.Equal(ps[0].Public))
+	loadedID.Addr = "127.0.0.1:9999"
+	require.NoError(t, store.SaveIdentity(loadedID))
+	loadedKey, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, loadedKey.Key.String(), ps[0].Key.String())
+	require.Equal(t, "127.0.0.1:9999", loadedKey.Public.Address())
+
 	// test group
 	require.Nil(t, store.SaveGroup(group))
 	loadedGroup, err := store.LoadGroup()
@@ -74,3 +85,74 @@ func TestKeysSaveLoad(t *testing.T) {
 	require.Equal(t, dp.Key().String(), loadedDp.Key().String())
 
 }
+
+func TestKeysEncryptedSaveLoad(t *testing.T) {
+	ps, _ := BatchIdentities(2)
+	tmp := path.Join(os.TempDir(), "drand-key-encrypted")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+
+	// plain files are loaded by the encrypted store
+	plain := NewFileStore(tmp)
+	require.NoError(t, plain.SaveKeyPair(ps[0]))
+	pass := []byte("correct horse battery staple")
+	store := NewEncryptedFileStore(tmp, pass).(*fileStore)
+	loaded, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, ps[0].Key.String(), loaded.Key.String())
+
+	require.NoError(t, store.SaveKeyPair(ps[1]))
+	require.True(t, IsEncrypted(store.privateKeyFile))
+	require.False(t, IsEncrypted(store.publicKeyFile))
+	loaded, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, ps[1].Key.String(), loaded.Key.String())
+
+	_, err = plain.LoadKeyPair()
+	require.Equal(t, ErrPassphrase, err)
+	_, err = NewEncryptedFileStore(tmp, []byte("wrong")).LoadKeyPair()
+	require.Equal(t, ErrPassphrase, err)
+
+	s := &Share{
+		Commits: []kyber.Point{ps[0].Public.Key, ps[1].Public.Key},
+		Share:   &share.PriShare{V: ps[0].Key, I: 1},
+	}
+	require.NoError(t, store.SaveShare(s))
+	require.True(t, IsEncrypted(store.shareFile))
+	loadedShare, err := store.LoadShare()
+	require.NoError(t, err)
+	require.Equal(t, s.Share.V.String(), loadedShare.Share.V.String())
+	require.Equal(t, s.Share.I, loadedShare.Share.I)
+}
+
+func TestPendingKeyPair(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-pending")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp).(*fileStore)
+	ps, _ := BatchIdentities(1)
+	require.NoError(t, store.SaveKeyPair(ps[0]))
+
+	_, err := store.LoadPendingKeyPair()
+	require.Equal(t, ErrAbsent, err)
+
+	rotated, err := RotateKeyPair(ps[0])
+	require.NoError(t, err)
+	require.NoError(t, store.SavePendingKeyPair(rotated))
+	pending, err := store.LoadPendingKeyPair()
+	require.NoError(t, err)
+	require.True(t, pending.Public.Key.Equal(rotated.Public.Key))
+	// the current key is untouched until the pending one is committed
+	current, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, current.Public.Key.Equal(ps[0].Public.Key))
+
+	require.NoError(t, store.CommitPendingKeyPair())
+	current, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, current.Public.Key.Equal(rotated.Public.Key))
+	_, err = store.LoadPendingKeyPair()
+	require.Equal(t, ErrAbsent, err)
+	_, err = os.Stat(store.privateKeyFile + oldExtension)
+	require.NoError(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -82,7 +83,7 @@ var insecureFlag = &cli.BoolFlag{
 
 var controlFlag = &cli.StringFlag{
 	Name:  "control",
-	Usage: "Set the port you want to listen to for control port commands. If not specified, we will use the default port 8888.",
+	Usage: "Set the port you want to listen to for control port commands. If not specified, we will use the default port 8888. Commands use the control socket of the config folder unless a port is given.",
 }
 
 var listenFlag = &cli.StringFlag{
@@ -90,6 +91,98 @@ var listenFlag = &cli.StringFlag{
 	Usage: "Set the listening (binding) address. Useful if you have some kind of proxy.",
 }
 
+var publicListenFlag = &cli.StringFlag{
+	Name: "public-listen",
+	Usage: "Serve the public gRPC and REST API on their own listener bound to " +
+		"this address. The listening address then only serves the protocol " +
+		"between the nodes, which can stay firewalled.",
+}
+
+var publicTLSCertFlag = &cli.StringFlag{
+	Name:  "public-tls-cert",
+	Usage: "Certificate of the public listener, the one of --tls-cert by default.",
+}
+
+var publicTLSKeyFlag = &cli.StringFlag{
+	Name:  "public-tls-key",
+	Usage: "Private key of the public listener, the one of --tls-key by default.",
+}
+
+var publicInsecureFlag = &cli.BoolFlag{
+	Name:  "public-tls-disable",
+	Usage: "Serve the public listener without TLS, for example behind a proxy terminating TLS.",
+}
+
+var publicACMEFlag = &cli.StringSliceFlag{
+	Name: "public-acme",
+	Usage: "Obtain and renew the certificate of the public listener from Let's " +
+		"Encrypt for the given domain(s), accepting its terms of service. The " +
+		"public listener must be reachable on port 443 of the domains.",
+}
+
+var acmeDirectoryFlag = &cli.StringFlag{
+	Name:  "acme-directory",
+	Usage: "Directory URL of the ACME certificate authority used with --public-acme, Let's Encrypt by default.",
+}
+
+var acmeEmailFlag = &cli.StringFlag{
+	Name:  "acme-email",
+	Usage: "Contact email of the ACME account used with --public-acme.",
+}
+
+var rateLimitFlag = &cli.Float64Flag{
+	Name:  "rate-limit",
+	Usage: "Number of requests per second each IP address can make to the public API, unlimited if not set.",
+}
+
+var rateBurstFlag = &cli.IntFlag{
+	Name:  "rate-burst",
+	Usage: "Number of requests each IP address can make at once to the public API, --rate-limit rounded up by default.",
+}
+
+var globalRateLimitFlag = &cli.Float64Flag{
+	Name:  "global-rate-limit",
+	Usage: "Number of requests per second all the clients together can make to the public API, unlimited if not set.",
+}
+
+var maxStreamsFlag = &cli.IntFlag{
+	Name:  "max-streams",
+	Usage: "Number of randomness streams served at the same time, unlimited if not set.",
+}
+
+var maxRequestSizeFlag = &cli.IntFlag{
+	Name:  "max-request-size",
+	Usage: "Size in bytes of the largest request accepted on the public API, unlimited if not set.",
+}
+
+var corsOriginsFlag = &cli.StringSliceFlag{
+	Name:  "cors-origins",
+	Usage: "Origin(s) allowed to call the REST API from a browser, any origin by default.",
+}
+
+var gossipPeersFlag = &cli.StringSliceFlag{
+	Name: "gossip-peers",
+	Usage: "Forward the beacons of the group to the node(s) or relay(s) at " +
+		"the given address(es), which verify them and forward them to their own peers.",
+}
+
+var followFlag = &cli.StringSliceFlag{
+	Name: "follow",
+	Usage: "Address(es) of the node(s) or relay(s) of the group the relay follows, " +
+		"tried in turn when the current one is lost.",
+}
+
+var restFlag = &cli.BoolFlag{
+	Name:  "rest",
+	Usage: "Contact the nodes over their REST API instead of gRPC.",
+}
+
+var watchFlag = &cli.BoolFlag{
+	Name: "watch",
+	Usage: "Print the new randomness as it is produced, from the round given " +
+		"with --round if any. A cut stream is resumed without missing any round.",
+}
+
 var nodeFlag = &cli.StringFlag{
 	Name:  "nodes",
 	Usage: "Contact the nodes at the given list of whitespace-separated addresses which have to be present in group.toml.",
@@ -145,6 +238,11 @@ var oldGroupFlag = &cli.StringFlag{
 		"included in the current DKG.",
 }
 
+var inFlag = &cli.StringFlag{
+	Name:  "in",
+	Usage: "read the input from the given file instead of stdin",
+}
+
 var timeoutFlag = &cli.StringFlag{
 	Name:  "timeout",
 	Usage: fmt.Sprintf("Timeout to use during the DKG, in string format. Default is %s", core.DefaultDKGTimeout),
@@ -170,6 +268,116 @@ var startInFlag = &cli.StringFlag{
 	Usage: "Duration to parse in which the setup or resharing phase will start. This flags sets the `GenesisTime` or `TransitionTime` in `start-in` period from now.",
 }
 
+var groupURLFlag = &cli.StringFlag{
+	Name:  "group-url",
+	Usage: "http(s) url from which the daemon fetches the group file, instead of giving a group file as argument.",
+}
+
+var groupHashFlag = &cli.StringFlag{
+	Name:  "group-hash",
+	Usage: "Hex-encoded sha256 hash of the expected group file, as printed by the group command or sha256sum. The daemon rejects the group file if its hash is different.",
+}
+
+var connectFlag = &cli.StringFlag{
+	Name:  "connect",
+	Usage: "Base url of a coordinator to register to. The node then fetches the group from the coordinator and runs the DKG with it if its file hash matches the --group-hash flag, or prints it otherwise.",
+}
+
+var secretFlag = &cli.StringFlag{
+	Name:    "secret",
+	Usage:   "Secret shared between the coordinator and the participants.",
+	EnvVars: []string{"DRAND_COORDINATOR_SECRET"},
+}
+
+var expectedFlag = &cli.IntFlag{
+	Name:  "expected",
+	Usage: "Number of participants the coordinator waits for before building the group.",
+}
+
+var reshareFlag = &cli.BoolFlag{
+	Name:  "reshare",
+	Usage: "Run a resharing protocol to the given group even if the node has no distributed key yet.",
+}
+
+var dryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "Used with --reshare, only checks the resharing to the new group and reports the changes and problems found, without running it.",
+}
+
+var echoBroadcastFlag = &cli.BoolFlag{
+	Name:  "dkg-echo",
+	Usage: "Enables the echo broadcast of the DKG responses and justifications to prevent nodes from sending different messages to different nodes. All nodes of the group must enable it.",
+}
+
+var mutualTLSFlag = &cli.BoolFlag{
+	Name: "tls-mutual",
+	Usage: "Only accept protocol calls from nodes of the group presenting their " +
+		"TLS certificate, trusted by this node and bound to their identity. " +
+		"The node binds its certificate to its public identity file when it starts, " +
+		"run update-address after changing the certificate. The public API stays open.",
+}
+
+var controlTokenFlag = &cli.BoolFlag{
+	Name: "control-token",
+	Usage: "Require the clients of the control port to present the token stored " +
+		"in the config folder. The control socket in the config folder is " +
+		"always available to the user running the daemon.",
+}
+
+var keySocketFlag = &cli.StringFlag{
+	Name:  "key-socket",
+	Usage: "Unix socket of an external process performing the signatures and decryptions with the longterm key. The node then only loads its public identity from the key folder.",
+}
+
+var rotateFlag = &cli.BoolFlag{
+	Name: "rotate",
+	Usage: "Create a new keypair, certified by the existing key, to replace it. " +
+		"The new key replaces the existing one once a resharing to a group containing the new public key succeeds.",
+}
+
+var schemeFlag = &cli.StringFlag{
+	Name: "scheme",
+	Usage: fmt.Sprintf("Scheme of the key: %s (default) for signatures on G2 or %s for "+
+		"signatures on G1. All the nodes of a group need keys of the same scheme.", key.SuiteSigsOnG2, key.SuiteSigsOnG1),
+}
+
+var unchainedFlag = &cli.BoolFlag{
+	Name: "unchained",
+	Usage: "Create a group whose beacons only sign their round instead of chaining " +
+		"to the previous signature, so the message of any future round is known.",
+}
+
+var randomnessHashFlag = &cli.StringFlag{
+	Name: "randomness-hash",
+	Usage: fmt.Sprintf("Hash function deriving the randomness from the signatures: %s (default), %s or %s.",
+		key.HashSHA256, key.HashBLAKE2b, key.HashSHA3),
+}
+
+var addressFlag = &cli.StringFlag{
+	Name:  "address",
+	Usage: "New public address of the node, announced to the group.",
+}
+
+var addressesFlag = &cli.StringSliceFlag{
+	Name:  "addresses",
+	Usage: "Alternative public addresses of the node, announced to the group. The other nodes fall back to them when the main address is unreachable.",
+}
+
+var operatorFlag = &cli.StringFlag{
+	Name:  "operator",
+	Usage: "Name of the operator of the node, published in the group.",
+}
+
+var contactFlag = &cli.StringFlag{
+	Name:  "contact",
+	Usage: "Contact of the operator of the node, published in the group.",
+}
+
+var regionFlag = &cli.StringFlag{
+	Name:  "region",
+	Usage: "Region where the node runs, published in the group.",
+}
+
 func main() {
 	app := cli.NewApp()
 
@@ -186,16 +394,37 @@ func main() {
 			Usage: "Start the drand daemon.",
 			Flags: toArray(folderFlag, tlsCertFlag, tlsKeyFlag,
 				insecureFlag, controlFlag, listenFlag,
-				certsDirFlag, pushFlag, verboseFlag),
+				certsDirFlag, pushFlag, verboseFlag, echoBroadcastFlag, passphraseFileFlag,
+				keySocketFlag, mutualTLSFlag, controlTokenFlag, publicListenFlag,
+				publicTLSCertFlag, publicTLSKeyFlag, publicInsecureFlag, publicACMEFlag,
+				acmeDirectoryFlag, acmeEmailFlag, rateLimitFlag, rateBurstFlag,
+				globalRateLimitFlag, maxStreamsFlag, maxRequestSizeFlag, corsOriginsFlag,
+				gossipPeersFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return startCmd(c)
 			},
 		},
+		&cli.Command{
+			Name: "relay",
+			Usage: "Start a read-only relay of the group given as argument. The " +
+				"relay holds no share: it follows the beacons of the nodes given " +
+				"with --follow, verifies them with the distributed key of the group " +
+				"and serves them on the public API.\n",
+			ArgsUsage: "<group.toml> the group file with the distributed key",
+			Flags: toArray(folderFlag, tlsCertFlag, tlsKeyFlag, insecureFlag,
+				listenFlag, certsDirFlag, verboseFlag, followFlag, rateLimitFlag,
+				rateBurstFlag, globalRateLimitFlag, maxStreamsFlag, maxRequestSizeFlag,
+				corsOriginsFlag, gossipPeersFlag),
+			Action: func(c *cli.Context) error {
+				banner()
+				return relayCmd(c)
+			},
+		},
 		&cli.Command{
 			Name:  "stop",
 			Usage: "Stop the drand daemon.\n",
-			Flags: toArray(controlFlag),
+			Flags: toArray(folderFlag, controlFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return stopDaemon(c)
@@ -209,23 +438,46 @@ func main() {
 				"existing group can also issue new shares to a new group: use " +
 				"the flag --from to specify the current group and give " +
 				"the new group as argument. Specify the --leader flag to make " +
-				"this daemon start the protocol\n",
+				"this daemon start the protocol. The group can also be fetched " +
+				"by the daemon from an url with the --group-url flag, and " +
+				"pinned with the --group-hash flag. With the --connect flag, " +
+				"the node registers to a coordinator and runs the DKG with the " +
+				"group built by the coordinator, once its file hash is given " +
+				"with the --group-hash flag\n",
 			ArgsUsage: "<group.toml> group file",
 			Flags: toArray(folderFlag, insecureFlag, controlFlag,
-				leaderFlag, oldGroupFlag, timeoutFlag, sourceFlag, userEntropyOnlyFlag),
+				leaderFlag, oldGroupFlag, timeoutFlag, sourceFlag, userEntropyOnlyFlag,
+				groupURLFlag, groupHashFlag, connectFlag, secretFlag, reshareFlag, dryRunFlag,
+				passphraseFileFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return shareCmd(c)
 			},
 		},
+		&cli.Command{
+			Name: "coordinate",
+			Usage: "Run a coordinator to which participants of a new network " +
+				"register with a shared secret. Once the expected number of " +
+				"participants is registered, the coordinator builds the group " +
+				"that participants use to run the DKG with 'share --connect'.\n",
+			Flags: toArray(listenFlag, tlsCertFlag, tlsKeyFlag, insecureFlag,
+				secretFlag, expectedFlag, thresholdFlag, periodFlag, genesisFlag, startInFlag),
+			Action: func(c *cli.Context) error {
+				banner()
+				return coordinateCmd(c)
+			},
+		},
 		&cli.Command{
 			Name: "generate-keypair",
 			Usage: "Generate the longterm keypair (drand.private, drand.public)" +
 				"for this node.\n",
 			ArgsUsage: "<address> is the public address for other nodes to contact",
-			Flags:     toArray(folderFlag, insecureFlag),
+			Flags:     toArray(folderFlag, insecureFlag, passphraseFileFlag, rotateFlag, schemeFlag),
 			Action: func(c *cli.Context) error {
 				banner()
+				if c.Bool(rotateFlag.Name) {
+					return rotateKeyCmd(c)
+				}
 				return keygenCmd(c)
 			},
 		},
@@ -236,7 +488,7 @@ func main() {
 				"a new group.toml file with the given identites.\n",
 			ArgsUsage: "<key1 key2 key3...> must be the identities of the group " +
 				"to create/to insert into the group",
-			Flags: toArray(folderFlag, outFlag, periodFlag, thresholdFlag, genesisFlag, transitionFlag, fromGroupFlag, startInFlag),
+			Flags: toArray(folderFlag, outFlag, periodFlag, thresholdFlag, genesisFlag, transitionFlag, fromGroupFlag, startInFlag, unchainedFlag, randomnessHashFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return groupCmd(c)
@@ -281,7 +533,8 @@ func main() {
 						"beacon via TLS and falls back to plaintext communication " +
 						"if the contacted node has not activated TLS in which case " +
 						"it prints a warning.\n",
-					Flags: toArray(tlsCertFlag, insecureFlag, roundFlag, nodeFlag),
+					Flags: toArray(tlsCertFlag, insecureFlag, roundFlag, nodeFlag,
+						restFlag, watchFlag),
 					Action: func(c *cli.Context) error {
 						return getPublicRandomness(c)
 					},
@@ -299,14 +552,51 @@ func main() {
 				},
 			},
 		},
+		{
+			Name: "encrypt",
+			Usage: "Encrypts data toward a future round of the beacon chain " +
+				"of group.toml: it can only be decrypted once the signature of " +
+				"this round is published. Any future round can be chosen for an " +
+				"unchained group; the data of a chained group is encrypted " +
+				"toward the round following the last beacon, fetched from the " +
+				"nodes.\n",
+			ArgsUsage: "<group.toml> provides the distributed key and the " +
+				"nodes of the chain",
+			Flags: toArray(tlsCertFlag, insecureFlag, roundFlag, nodeFlag, inFlag, outFlag),
+			Action: func(c *cli.Context) error {
+				return encryptCmd(c)
+			},
+		},
+		{
+			Name: "decrypt",
+			Usage: "Decrypts data encrypted with the encrypt command, " +
+				"fetching the signature of its round from the nodes of " +
+				"group.toml.\n",
+			ArgsUsage: "<group.toml> provides the distributed key and the " +
+				"nodes of the chain",
+			Flags: toArray(tlsCertFlag, insecureFlag, nodeFlag, inFlag, outFlag),
+			Action: func(c *cli.Context) error {
+				return decryptCmd(c)
+			},
+		},
 		{
 			Name:  "ping",
 			Usage: "pings the daemon checking its state\n",
-			Flags: toArray(controlFlag),
+			Flags: toArray(folderFlag, controlFlag),
 			Action: func(c *cli.Context) error {
 				return pingpongCmd(c)
 			},
 		},
+		{
+			Name: "update-address",
+			Usage: "Changes the addresses and metadata of the running node and " +
+				"announces them, signed by the longterm key, to the other nodes " +
+				"of the group, without a new DKG.\n",
+			Flags: toArray(folderFlag, controlFlag, addressFlag, addressesFlag, operatorFlag, contactFlag, regionFlag),
+			Action: func(c *cli.Context) error {
+				return updateAddressCmd(c)
+			},
+		},
 		{
 			Name:  "reset",
 			Usage: "Resets the local distributed information (share, group file and random beacons). It KEEPS the private/public key pair.",
@@ -315,6 +605,52 @@ func main() {
 				return resetCmd(c)
 			},
 		},
+		{
+			Name:  "util",
+			Usage: "Multiple utilities to manage the local files of the node.\n",
+			Subcommands: []*cli.Command{
+				{
+					Name: "encrypt-keys",
+					Usage: "Encrypts the existing private key and share with a " +
+						"passphrase, read from the " + passphraseEnv + " environment " +
+						"variable, the --passphrase-file flag or prompted.\n",
+					Flags: toArray(folderFlag, passphraseFileFlag),
+					Action: func(c *cli.Context) error {
+						return encryptKeysCmd(c)
+					},
+				},
+				{
+					Name: "self-sign",
+					Usage: "Signs the public identity of the node with its private " +
+						"key, proving its possession, for keys generated without it.\n",
+					Flags: toArray(folderFlag, passphraseFileFlag),
+					Action: func(c *cli.Context) error {
+						return selfSignCmd(c)
+					},
+				},
+				{
+					Name: "backup-share",
+					Usage: "Splits the private share in encrypted pieces for custodians, " +
+						"any threshold of them restoring the share.\n",
+					ArgsUsage: "<folder> where the pieces are saved",
+					Flags: toArray(folderFlag, passphraseFileFlag, piecesFlag,
+						backupThresholdFlag, piecePassphraseFilesFlag),
+					Action: func(c *cli.Context) error {
+						return backupShareCmd(c)
+					},
+				},
+				{
+					Name: "restore-share",
+					Usage: "Restores the private share from backup pieces and checks it " +
+						"against its public polynomial.\n",
+					ArgsUsage: "<piece1> <piece2> ... the backup pieces",
+					Flags:     toArray(folderFlag, passphraseFileFlag, piecePassphraseFilesFlag),
+					Action: func(c *cli.Context) error {
+						return restoreShareCmd(c)
+					},
+				},
+			},
+		},
 		{
 			Name: "show",
 			Usage: "local information retrieval about the node's cryptographic " +
@@ -328,7 +664,7 @@ func main() {
 				{
 					Name:  "share",
 					Usage: "shows the private share\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(folderFlag, controlFlag),
 					Action: func(c *cli.Context) error {
 						return showShareCmd(c)
 					},
@@ -338,7 +674,7 @@ func main() {
 					Usage: "shows the current group.toml used. The group.toml " +
 						"may contain the distributed public key if the DKG has been " +
 						"ran already.\n",
-					Flags: toArray(outFlag, controlFlag),
+					Flags: toArray(folderFlag, outFlag, controlFlag),
 					Action: func(c *cli.Context) error {
 						return showGroupCmd(c)
 					},
@@ -346,7 +682,7 @@ func main() {
 				{
 					Name:  "cokey",
 					Usage: "shows the collective key generated during DKG.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(folderFlag, controlFlag),
 					Action: func(c *cli.Context) error {
 						return showCokeyCmd(c)
 					},
@@ -354,7 +690,7 @@ func main() {
 				{
 					Name:  "private",
 					Usage: "shows the long-term private key of a node.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(folderFlag, controlFlag),
 					Action: func(c *cli.Context) error {
 						return showPrivateCmd(c)
 					},
@@ -362,11 +698,20 @@ func main() {
 				{
 					Name:  "public",
 					Usage: "shows the long-term public key of a node.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(folderFlag, controlFlag),
 					Action: func(c *cli.Context) error {
 						return showPublicCmd(c)
 					},
 				},
+				{
+					Name: "peers",
+					Usage: "shows the health of the connections to the other " +
+						"nodes of the group.\n",
+					Flags: toArray(folderFlag, controlFlag),
+					Action: func(c *cli.Context) error {
+						return showPeersCmd(c)
+					},
+				},
 			},
 		},
 	}
@@ -472,19 +817,25 @@ func keygenCmd(c *cli.Context) error {
 		fmt.Println("Invalid port.")
 		addr = addr + ":" + askPort()
 	}
-	var priv *key.Pair
-	if c.Bool("tls-disable") {
-		fmt.Println("Generating private / public key pair without TLS.")
-		priv = key.NewKeyPair(addr)
-	} else {
+	suite, err := key.SuiteFromName(c.String(schemeFlag.Name))
+	if err != nil {
+		fatal("drand: %s", err)
+	}
+	tls := !c.Bool("tls-disable")
+	if tls {
 		fmt.Println("Generating private / public key pair with TLS indication")
-		priv = key.NewTLSKeyPair(addr)
+	} else {
+		fmt.Println("Generating private / public key pair without TLS.")
+	}
+	priv, err := key.NewSuiteKeyPair(addr, suite, tls)
+	if err != nil {
+		fatal("drand: can't generate key pair: %s", err)
 	}
 
 	config := contextToConfig(c)
-	fs := key.NewFileStore(config.ConfigFolder())
+	fs := keyStore(c, config)
 
-	if _, err := fs.LoadKeyPair(); err == nil {
+	if _, err := fs.LoadKeyPair(); err == nil || err == key.ErrPassphrase {
 		fmt.Printf("Keypair already present in `%s`.\nRemove them before generating new one\n", config.ConfigFolder())
 		return nil
 	}
@@ -497,16 +848,45 @@ func keygenCmd(c *cli.Context) error {
 		fatal("err getting full path: ", err)
 	}
 	fmt.Println("Generated keys at ", absPath)
+	printNodeSnippet(priv.Public)
+	return nil
+}
+
+// rotateKeyCmd creates a new keypair certified by the current key. The new
+// keypair is saved as pending: the node only switches to it once a resharing
+// to a group containing the new key succeeds.
+func rotateKeyCmd(c *cli.Context) error {
+	config := contextToConfig(c)
+	fs := keyStore(c, config)
+	old, err := fs.LoadKeyPair()
+	if err != nil {
+		fatal("drand: can't load the current keypair: %s", err)
+	}
+	priv, err := key.RotateKeyPair(old)
+	if err != nil {
+		fatal("drand: can't rotate keypair: %s", err)
+	}
+	if err := fs.SavePendingKeyPair(priv); err != nil {
+		fatal("could not save key: ", err)
+	}
+	fmt.Println("Rotated keys, the new keys are saved with the .pending extension.")
+	fmt.Println("The node keeps its current key until the next resharing to a " +
+		"group containing the new public key succeeds. The previous keys are " +
+		"then kept with the .old extension.")
+	printNodeSnippet(priv.Public)
+	return nil
+}
+
+func printNodeSnippet(id *key.Identity) {
 	fmt.Println("You can copy paste the following snippet to a common group.toml file:")
 	var buff bytes.Buffer
 	buff.WriteString("[[Nodes]]\n")
-	if err := toml.NewEncoder(&buff).Encode(priv.Public.TOML()); err != nil {
+	if err := toml.NewEncoder(&buff).Encode(id.TOML()); err != nil {
 		panic(err)
 	}
 	buff.WriteString("\n")
 	fmt.Println(buff.String())
 	fmt.Println("Or just collect all public key files and use the group command!")
-	return nil
 }
 
 func groupCmd(c *cli.Context) error {
@@ -539,8 +919,24 @@ func groupCmd(c *cli.Context) error {
 		fatal("drand: genesis time in the past or not specified")
 	}
 	fmt.Printf("Creating the new group file at time %s\n", time.Unix(genesis, 0).String())
+	suite, err := key.SuiteOf(publics[0].Key)
+	if err != nil {
+		fatal("drand: key of %s: %s", publics[0].Address(), err)
+	}
+	for _, id := range publics[1:] {
+		if s, err := key.SuiteOf(id.Key); err != nil || s != suite {
+			fatal("drand: keys of different schemes, %s and %s", publics[0].Address(), id.Address())
+		}
+	}
 	group := key.NewGroup(publics, threshold, genesis)
 	group.Period = period
+	group.Unchained = c.Bool(unchainedFlag.Name)
+	if _, err := key.RandomnessHash(c.String(randomnessHashFlag.Name)); err != nil {
+		fatal("drand: %s", err)
+	}
+	if name := c.String(randomnessHashFlag.Name); name != key.DefaultRandomnessHash {
+		group.RandomnessHash = name
+	}
 	groupOut(c, group)
 	return nil
 }
@@ -587,6 +983,8 @@ func groupReshareCmd(c *cli.Context) error {
 	// NOTE: for now we keep the same period as the old group, changing period
 	// for the same group is not implemented yet
 	newGroup.Period = group.Period
+	newGroup.Unchained = group.Unchained
+	newGroup.RandomnessHash = group.RandomnessHash
 	newGroup.GenesisSeed = group.GetGenesisSeed()
 	newGroup.TransitionTime = c.Int64(transitionFlag.Name)
 
@@ -595,21 +993,22 @@ func groupReshareCmd(c *cli.Context) error {
 }
 
 func groupOut(c *cli.Context, group *key.Group) {
+	var buff bytes.Buffer
+	if err := toml.NewEncoder(&buff).Encode(group.TOML()); err != nil {
+		fatal("drand: can't encode group to TOML: %v", err)
+	}
 	if c.IsSet("out") {
 		groupPath := c.String("out")
-		if err := key.Save(groupPath, group, false); err != nil {
+		if err := ioutil.WriteFile(groupPath, buff.Bytes(), 0644); err != nil {
 			fatal("drand: can't save group to specified file name: %v", err)
 		}
 	} else {
-		var buff bytes.Buffer
-		if err := toml.NewEncoder(&buff).Encode(group.TOML()); err != nil {
-			fatal("drand: can't encode group to TOML: %v", err)
-		}
-		buff.WriteString("\n")
 		fmt.Printf("Copy the following snippet into a new group.toml file " +
 			"and distribute it to all the participants:\n")
-		fmt.Printf(buff.String())
+		fmt.Println(buff.String())
 	}
+	// the hash of the file content, as expected by the --group-hash flag
+	fmt.Printf("Group file hash: %s\n", key.FileHash(buff.Bytes()))
 }
 
 func getThreshold(c *cli.Context) int {
@@ -632,6 +1031,9 @@ func getPublicKeys(c *cli.Context) []*key.Identity {
 		if err := key.Load(str, pub); err != nil {
 			fatal("drand: can't load key %d: %v", i, err)
 		}
+		if err := pub.ValidSignature(); err != nil {
+			fatal("drand: key %s of %s is not valid: %v", str, pub.Address(), err)
+		}
 		publics[i] = pub
 	}
 	return publics
@@ -649,6 +1051,12 @@ func checkGroup(c *cli.Context) error {
 	var allGood = true
 	var invalidIds []string
 	for _, id := range group.Nodes {
+		if err := id.ValidSignature(); err != nil {
+			fmt.Printf("drand: invalid id %s: %s\n", id.Address(), err)
+			allGood = false
+			invalidIds = append(invalidIds, id.Address())
+			continue
+		}
 		client := net.NewGrpcClientFromCertManager(conf.Certs())
 		_, err := client.Home(id, &drand.HomeRequest{})
 		if err != nil {
@@ -660,7 +1068,7 @@ func checkGroup(c *cli.Context) error {
 		fmt.Printf("drand: id %s answers correctly\n", id.Address())
 	}
 	if !allGood {
-		return fmt.Errorf("Following nodes don't answer: %s", strings.Join(invalidIds, " ,"))
+		return fmt.Errorf("Following nodes are invalid or don't answer: %s", strings.Join(invalidIds, " ,"))
 	}
 	return nil
 }
@@ -708,6 +1116,45 @@ func contextToConfig(c *cli.Context) *core.Config {
 	if port != "" {
 		opts = append(opts, core.WithControlPort(port))
 	}
+	if c.IsSet(publicListenFlag.Name) {
+		opts = append(opts, core.WithPublicListenAddress(c.String(publicListenFlag.Name)))
+	}
+	if c.IsSet(publicTLSCertFlag.Name) != c.IsSet(publicTLSKeyFlag.Name) {
+		fatal("drand: public-tls-cert and public-tls-key must be used together")
+	}
+	if c.IsSet(publicTLSCertFlag.Name) {
+		opts = append(opts, core.WithPublicTLS(c.String(publicTLSCertFlag.Name), c.String(publicTLSKeyFlag.Name)))
+	}
+	if c.Bool(publicInsecureFlag.Name) {
+		opts = append(opts, core.WithPublicInsecure())
+	}
+	if c.IsSet(publicACMEFlag.Name) {
+		if !c.IsSet(publicListenFlag.Name) || c.IsSet(publicTLSCertFlag.Name) || c.Bool(publicInsecureFlag.Name) {
+			fatal("drand: public-acme requires public-listen and excludes public-tls-cert and public-tls-disable")
+		}
+		opts = append(opts, core.WithPublicACME(c.StringSlice(publicACMEFlag.Name),
+			c.String(acmeDirectoryFlag.Name), c.String(acmeEmailFlag.Name)))
+	}
+	limits := []string{rateLimitFlag.Name, rateBurstFlag.Name, globalRateLimitFlag.Name,
+		maxStreamsFlag.Name, maxRequestSizeFlag.Name}
+	for _, name := range limits {
+		if c.IsSet(name) {
+			opts = append(opts, core.WithRateLimit(net.RateLimitConfig{
+				Rate:           c.Float64(rateLimitFlag.Name),
+				Burst:          c.Int(rateBurstFlag.Name),
+				GlobalRate:     c.Float64(globalRateLimitFlag.Name),
+				MaxStreams:     c.Int(maxStreamsFlag.Name),
+				MaxRequestSize: c.Int(maxRequestSizeFlag.Name),
+			}))
+			break
+		}
+	}
+	if c.IsSet(gossipPeersFlag.Name) {
+		opts = append(opts, core.WithGossipPeers(c.StringSlice(gossipPeersFlag.Name)...))
+	}
+	if c.IsSet(corsOriginsFlag.Name) {
+		opts = append(opts, core.WithCORS(c.StringSlice(corsOriginsFlag.Name)...))
+	}
 	config := c.String(folderFlag.Name)
 	opts = append(opts, core.WithConfigFolder(config))
 
@@ -719,6 +1166,9 @@ func contextToConfig(c *cli.Context) *core.Config {
 	} else {
 		certPath, keyPath := c.String("tls-cert"), c.String("tls-key")
 		opts = append(opts, core.WithTLS(certPath, keyPath))
+		if c.Bool(mutualTLSFlag.Name) {
+			opts = append(opts, core.WithMutualTLS())
+		}
 	}
 	if c.IsSet("certs-dir") {
 		paths, err := fs.Files(c.String("certs-dir"))
@@ -727,6 +1177,15 @@ func contextToConfig(c *cli.Context) *core.Config {
 		}
 		opts = append(opts, core.WithTrustedCerts(paths...))
 	}
+	if c.Bool(echoBroadcastFlag.Name) {
+		opts = append(opts, core.WithDkgEchoBroadcast())
+	}
+	if c.IsSet(keySocketFlag.Name) {
+		opts = append(opts, core.WithExternalKey(c.String(keySocketFlag.Name)))
+	}
+	if c.Bool(controlTokenFlag.Name) {
+		opts = append(opts, core.WithControlToken())
+	}
 	conf := core.NewConfig(opts...)
 	return conf
 }
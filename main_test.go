@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	gnet "net"
 	"os"
 	"os/exec"
@@ -14,9 +15,12 @@ import (
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/core"
 	"github.com/drand/drand/fs"
 	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
 	"github.com/drand/drand/test"
 	"github.com/drand/kyber"
 	"github.com/drand/kyber/share"
@@ -62,6 +66,27 @@ func TestKeyGen(t *testing.T) {
 	require.Nil(t, priv)
 }
 
+func TestEncryptKeys(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-encrypt")
+	defer os.RemoveAll(tmp)
+	cmd := exec.Command("drand", "generate-keypair", "--folder", tmp, "127.0.0.1:8081")
+	require.NoError(t, cmd.Run())
+
+	pass := "drand-test-passphrase"
+	cmd = exec.Command("drand", "util", "encrypt-keys", "--folder", tmp)
+	cmd.Env = append(os.Environ(), "DRAND_PASSPHRASE="+pass)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	config := core.NewConfig(core.WithConfigFolder(tmp))
+	require.True(t, key.IsEncrypted(key.PrivateKeyFile(config.ConfigFolder())))
+	_, err = key.NewFileStore(config.ConfigFolder()).LoadKeyPair()
+	require.Equal(t, key.ErrPassphrase, err)
+	priv, err := key.NewEncryptedFileStore(config.ConfigFolder(), []byte(pass)).LoadKeyPair()
+	require.NoError(t, err)
+	require.NotNil(t, priv.Public)
+}
+
 //tests valid commands and then invalid commands
 func TestGroup(t *testing.T) {
 	n := 5
@@ -73,7 +98,9 @@ func TestGroup(t *testing.T) {
 	privs := make([]*key.Pair, n, n)
 	for i := 0; i < n; i++ {
 		names[i] = path.Join(tmpPath, fmt.Sprintf("drand-%d.public", i))
-		privs[i] = key.NewKeyPair("127.0.0.1")
+		var err error
+		privs[i], err = key.NewKeyPair("127.0.0.1")
+		require.NoError(t, err)
 		require.NoError(t, key.Save(names[i], privs[i].Public, false))
 		if yes, err := fs.Exists(names[i]); !yes || err != nil {
 			t.Fatal(err.Error())
@@ -150,7 +177,8 @@ func TestGroup(t *testing.T) {
 	newPrivs := make([]*key.Pair, n, n)
 	for i := 0; i < n; i++ {
 		newNames[i] = path.Join(tmpPath, fmt.Sprintf("drand-%d.public", n+i))
-		newPrivs[i] = key.NewKeyPair("127.0.0.1:443")
+		newPrivs[i], err = key.NewKeyPair("127.0.0.1:443")
+		require.NoError(t, err)
 		require.NoError(t, key.Save(newNames[i], newPrivs[i].Public, false))
 		if yes, err := fs.Exists(newNames[i]); !yes || err != nil {
 			t.Fatal(err.Error())
@@ -195,7 +223,7 @@ func TestStartAndStop(t *testing.T) {
 	groupPath := path.Join(tmpPath, fmt.Sprintf("group.toml"))
 	require.NoError(t, key.Save(groupPath, group, false))
 
-	cmd := exec.Command("drand", "generate-keypair", "127.0.0.1:8080", "--tls-disable", "--folder", tmpPath)
+	cmd := exec.Command("drand", "generate-keypair", "--tls-disable", "--folder", tmpPath, "127.0.0.1:8080")
 	require.NoError(t, cmd.Run())
 	startCh := make(chan bool)
 	go func() {
@@ -214,7 +242,7 @@ func TestStartAndStop(t *testing.T) {
 	}()
 	<-startCh
 	time.Sleep(50 * time.Millisecond)
-	cmd = exec.Command("drand", "stop")
+	cmd = exec.Command("drand", "stop", "--folder", tmpPath)
 	buff, err := cmd.CombinedOutput()
 	require.NoError(t, err, string(buff))
 	select {
@@ -260,7 +288,8 @@ func TestStartWithoutGroup(t *testing.T) {
 	ctrlPort1 := test.FreePort()
 	ctrlPort2 := test.FreePort()
 
-	priv := key.NewKeyPair(addr)
+	priv, err := key.NewKeyPair(addr)
+	require.NoError(t, err)
 	require.NoError(t, key.Save(pubPath, priv.Public, false))
 
 	config := core.NewConfig(core.WithConfigFolder(tmpPath))
@@ -268,7 +297,7 @@ func TestStartWithoutGroup(t *testing.T) {
 	require.NoError(t, fs.SaveKeyPair(priv))
 
 	installCmd := exec.Command("go", "install")
-	_, err := installCmd.Output()
+	_, err = installCmd.Output()
 	require.NoError(t, err)
 
 	lctx, lcancel := context.WithCancel(context.Background())
@@ -384,7 +413,8 @@ func TestClientTLS(t *testing.T) {
 	addr := "127.0.0.1:8085"
 	ctrlPort := "9091"
 
-	priv := key.NewTLSKeyPair(addr)
+	priv, err := key.NewTLSKeyPair(addr)
+	require.NoError(t, err)
 	require.NoError(t, key.Save(pubPath, priv.Public, false))
 
 	config := core.NewConfig(core.WithConfigFolder(tmpPath))
@@ -426,7 +456,7 @@ func TestClientTLS(t *testing.T) {
 	go startCmd.Run()
 
 	installCmd := exec.Command("go", "install")
-	_, err := installCmd.Output()
+	_, err = installCmd.Output()
 	require.NoError(t, err)
 
 	cmd := exec.Command("drand", "get", "private", "--tls-cert", certPath, groupPath)
@@ -495,3 +525,74 @@ func TestClientTLS(t *testing.T) {
 	require.True(t, strings.Contains(string(out), expectedOutput))
 	require.NoError(t, err)
 }
+
+// unchainedServer serves the beacons of an unchained group signed locally
+type unchainedServer struct {
+	*net.EmptyServer
+	sign func(round uint64) []byte
+}
+
+func (s *unchainedServer) PublicRand(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	sig := s.sign(in.GetRound())
+	return &drand.PublicRandResponse{
+		Round:      in.GetRound(),
+		Signature:  sig,
+		Randomness: beacon.RandomnessFromSignature(sig),
+	}, nil
+}
+
+func TestEncryptDecryptUnchained(t *testing.T) {
+	tmpPath := path.Join(os.TempDir(), "drand-tlock")
+	os.RemoveAll(tmpPath)
+	os.Mkdir(tmpPath, 0740)
+	defer os.RemoveAll(tmpPath)
+
+	n, thr := 3, 2
+	priPoly := share.NewPriPoly(key.KeyGroup, thr, nil, random.New())
+	pubPoly := priPoly.Commit(key.KeyGroup.Point().Base())
+	_, commits := pubPoly.Info()
+	sign := func(round uint64) []byte {
+		msg := beacon.MessageFor(true, nil, 0, round)
+		var partials [][]byte
+		for _, s := range priPoly.Shares(n)[:thr] {
+			partial, err := key.Scheme.Sign(s, msg)
+			require.NoError(t, err)
+			partials = append(partials, partial)
+		}
+		sig, err := key.Scheme.Recover(pubPoly, msg, partials, thr, n)
+		require.NoError(t, err)
+		return sig
+	}
+
+	addr := "127.0.0.1:" + test.FreePort()
+	listener := net.NewTCPGrpcListener(addr, &unchainedServer{EmptyServer: new(net.EmptyServer), sign: sign})
+	go listener.Start()
+	defer listener.Stop()
+
+	ids := test.GenerateIDs(n)
+	ids[0].Public.Addr = addr
+	group := key.NewGroup(test.ListFromPrivates(ids), thr, time.Now().Unix())
+	group.Period = 2 * time.Second
+	group.PublicKey = &key.DistPublic{Coefficients: commits}
+	group.Unchained = true
+	groupPath := path.Join(tmpPath, "group.toml")
+	require.NoError(t, key.Save(groupPath, group, false))
+
+	data := []byte("sealed until round 5")
+	plainPath := path.Join(tmpPath, "plain")
+	cipherPath := path.Join(tmpPath, "cipher.toml")
+	decryptedPath := path.Join(tmpPath, "decrypted")
+	require.NoError(t, ioutil.WriteFile(plainPath, data, 0600))
+
+	cmd := exec.Command("drand", "encrypt", "--round", "5", "--in", plainPath, "--out", cipherPath, groupPath)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// the node serves the beacon of round 5, only valid in unchained mode
+	cmd = exec.Command("drand", "decrypt", "--tls-disable", "--nodes", addr, "--in", cipherPath, "--out", decryptedPath, groupPath)
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	decrypted, err := ioutil.ReadFile(decryptedPath)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+}
@@ -0,0 +1,60 @@
+package net
+
+import (
+	"crypto/tls"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ACMEConfig configures the certificates obtained from an ACME certificate
+// authority, Let's Encrypt by default.
+type ACMEConfig struct {
+	// Domains are the names the certificates are requested for
+	Domains []string
+	// CacheDir is the folder storing the account key and the certificates
+	CacheDir string
+	// DirectoryURL is the directory of the ACME server, the one of Let's
+	// Encrypt if empty
+	DirectoryURL string
+	// Email is the contact of the account, optional
+	Email string
+}
+
+// ACMECertSource is a CertSource obtaining and renewing its certificates from
+// an ACME certificate authority. The domains are validated with the
+// tls-alpn-01 challenge, answered by the listener using the source, so the
+// listener must be reachable on port 443 of the domains. Using it implies the
+// acceptance of the terms of service of the certificate authority.
+type ACMECertSource struct {
+	m *autocert.Manager
+}
+
+// NewACMECertSource returns a CertSource obtaining its certificates with the
+// given config.
+func NewACMECertSource(c ACMEConfig) *ACMECertSource {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(c.CacheDir),
+		HostPolicy: autocert.HostWhitelist(c.Domains...),
+		Email:      c.Email,
+	}
+	if c.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: c.DirectoryURL}
+	}
+	return &ACMECertSource{m}
+}
+
+// GetCertificate implements the CertSource interface.
+func (a *ACMECertSource) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return a.m.GetCertificate(hello)
+}
+
+// nextProtos returns the protocols to negotiate on top of the ones of drand
+func nextProtos(certs CertSource) []string {
+	protos := []string{"h2"}
+	if _, ok := certs.(*ACMECertSource); ok {
+		protos = append(protos, acme.ALPNProto)
+	}
+	return protos
+}
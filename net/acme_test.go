@@ -0,0 +1,351 @@
+package net
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+)
+
+// acmeStandIn is a minimal ACME certificate authority validating the domains
+// with the tls-alpn-01 challenge, enough to run the certificate flow of
+// autocert against a local listener.
+type acmeStandIn struct {
+	*httptest.Server
+	roots    *x509.CertPool
+	rootKey  *ecdsa.PrivateKey
+	rootCert *x509.Certificate
+	rootDER  []byte
+
+	sync.Mutex
+	// addresses the domains resolve to
+	resolve map[string]string
+	// validated domains
+	valid  map[string]bool
+	orders []*acmeOrder
+}
+
+type acmeOrder struct {
+	domains []string
+	leaf    []byte
+}
+
+// acmeIdentifiers are the extensions of the tls-alpn-01 challenge
+// certificate, the one of RFC 8737 and the one of its drafts
+var acmeIdentifiers = []asn1.ObjectIdentifier{
+	{1, 3, 6, 1, 5, 5, 7, 1, 31},
+	{1, 3, 6, 1, 5, 5, 7, 1, 30, 1},
+}
+
+func newACMEStandIn(t *testing.T) *acmeStandIn {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "drand test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	ca := &acmeStandIn{
+		roots:    x509.NewCertPool(),
+		rootKey:  key,
+		rootCert: cert,
+		rootDER:  der,
+		resolve:  make(map[string]string),
+		valid:    make(map[string]bool),
+	}
+	ca.roots.AddCert(cert)
+	ca.Server = httptest.NewServer(http.HandlerFunc(ca.handle))
+	return ca
+}
+
+func (ca *acmeStandIn) url(format string, args ...interface{}) string {
+	return ca.URL + fmt.Sprintf(format, args...)
+}
+
+func (ca *acmeStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", "nonce")
+	switch p := r.URL.Path; {
+	case p == "/directory":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"newNonce":   ca.url("/new-nonce"),
+			"newAccount": ca.url("/new-account"),
+			"newOrder":   ca.url("/new-order"),
+		})
+	case p == "/new-nonce":
+	case p == "/new-account":
+		w.Header().Set("Location", ca.url("/account/1"))
+		writeJSON(w, http.StatusCreated, map[string]string{"status": acme.StatusValid})
+	case p == "/new-order":
+		var req struct {
+			Identifiers []struct{ Value string }
+		}
+		if err := decodeJWSPayload(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		o := new(acmeOrder)
+		for _, id := range req.Identifiers {
+			o.domains = append(o.domains, id.Value)
+		}
+		ca.Lock()
+		ca.orders = append(ca.orders, o)
+		id := len(ca.orders) - 1
+		ca.Unlock()
+		ca.writeOrder(w, http.StatusCreated, id)
+	case strings.HasPrefix(p, "/order/"):
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(p, "/order/"), "%d", &id)
+		ca.writeOrder(w, http.StatusOK, id)
+	case strings.HasPrefix(p, "/authz/"):
+		domain := strings.TrimPrefix(p, "/authz/")
+		writeJSON(w, http.StatusOK, ca.authz(domain))
+	case strings.HasPrefix(p, "/challenge/"):
+		domain := strings.TrimPrefix(p, "/challenge/")
+		if err := ca.validate(domain); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, ca.authz(domain)["challenges"].([]map[string]string)[0])
+	case strings.HasPrefix(p, "/finalize/"):
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(p, "/finalize/"), "%d", &id)
+		var req struct{ CSR string }
+		if err := decodeJWSPayload(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ca.issue(id, req.CSR); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		ca.writeOrder(w, http.StatusOK, id)
+	case strings.HasPrefix(p, "/cert/"):
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(p, "/cert/"), "%d", &id)
+		ca.Lock()
+		leaf := ca.orders[id].leaf
+		ca.Unlock()
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: leaf})
+		pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: ca.rootDER})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ca *acmeStandIn) authz(domain string) map[string]interface{} {
+	ca.Lock()
+	defer ca.Unlock()
+	status := acme.StatusPending
+	if ca.valid[domain] {
+		status = acme.StatusValid
+	}
+	return map[string]interface{}{
+		"status":     status,
+		"identifier": map[string]string{"type": "dns", "value": domain},
+		"challenges": []map[string]string{{
+			"type":   "tls-alpn-01",
+			"url":    ca.url("/challenge/%s", domain),
+			"token":  "token-" + domain,
+			"status": status,
+		}},
+	}
+}
+
+func (ca *acmeStandIn) writeOrder(w http.ResponseWriter, code, id int) {
+	ca.Lock()
+	defer ca.Unlock()
+	if id < 0 || id >= len(ca.orders) {
+		http.Error(w, "no such order", http.StatusNotFound)
+		return
+	}
+	o := ca.orders[id]
+	status := acme.StatusReady
+	var authz []string
+	for _, d := range o.domains {
+		authz = append(authz, ca.url("/authz/%s", d))
+		if !ca.valid[d] {
+			status = acme.StatusPending
+		}
+	}
+	resp := map[string]interface{}{
+		"status":         status,
+		"authorizations": authz,
+		"finalize":       ca.url("/finalize/%d", id),
+	}
+	if o.leaf != nil {
+		resp["status"] = acme.StatusValid
+		resp["certificate"] = ca.url("/cert/%d", id)
+	}
+	w.Header().Set("Location", ca.url("/order/%d", id))
+	writeJSON(w, code, resp)
+}
+
+// validate answers the tls-alpn-01 challenge of the domain by connecting to
+// the address the domain resolves to
+func (ca *acmeStandIn) validate(domain string) error {
+	ca.Lock()
+	addr, ok := ca.resolve[domain]
+	ca.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown domain %s", domain)
+	}
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		ServerName:         domain,
+		NextProtos:         []string{acme.ALPNProto},
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	if state.NegotiatedProtocol != acme.ALPNProto {
+		return fmt.Errorf("negotiated %q instead of %q", state.NegotiatedProtocol, acme.ALPNProto)
+	}
+	cert := state.PeerCertificates[0]
+	if cert.VerifyHostname(domain) != nil {
+		return fmt.Errorf("challenge certificate not valid for %s", domain)
+	}
+	found := false
+	for _, ext := range cert.Extensions {
+		for _, id := range acmeIdentifiers {
+			found = found || ext.Id.Equal(id)
+		}
+	}
+	if !found {
+		return errors.New("challenge certificate without acme identifier")
+	}
+	ca.Lock()
+	ca.valid[domain] = true
+	ca.Unlock()
+	return nil
+}
+
+func (ca *acmeStandIn) issue(id int, b64CSR string) error {
+	der, err := base64.RawURLEncoding.DecodeString(b64CSR)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return err
+	}
+	names := csr.DNSNames
+	if len(names) == 0 {
+		names = []string{csr.Subject.CommonName}
+	}
+	ca.Lock()
+	defer ca.Unlock()
+	for _, d := range names {
+		if !ca.valid[d] {
+			return fmt.Errorf("domain %s not validated", d)
+		}
+	}
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(id + 2)),
+		Subject:      pkix.Name{CommonName: csr.Subject.CommonName},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	ca.orders[id].leaf, err = x509.CreateCertificate(rand.Reader, leaf, ca.rootCert, csr.PublicKey, ca.rootKey)
+	return err
+}
+
+func decodeJWSPayload(r *http.Request, v interface{}) error {
+	var jws struct{ Payload string }
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, v)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestListenerACME(t *testing.T) {
+	addr := "127.0.0.1:4006"
+	domain := "drand.test"
+	ca := newACMEStandIn(t)
+	defer ca.Close()
+	ca.resolve[domain] = addr
+
+	tmpDir, err := ioutil.TempDir("", "drand-net-acme")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	certs := NewACMECertSource(ACMEConfig{
+		Domains:      []string{domain},
+		CacheDir:     tmpDir,
+		DirectoryURL: ca.url("/directory"),
+	})
+
+	randServer := &testRandomnessServer{round: 42}
+	lis, err := NewTLSGrpcListenerFor(addr, certs, randServer, PublicServices, nil, nil)
+	require.NoError(t, err)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	// the certificate is obtained at the first connection for the domain
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: ca.roots, ServerName: domain},
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, network, addr)
+			},
+		},
+	}
+	resp, err := client.Get("https://" + domain + "/api/public")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "42")
+	require.Equal(t, domain, resp.TLS.PeerCertificates[0].DNSNames[0])
+
+	// other names are not served
+	_, err = tls.Dial("tcp", addr, &tls.Config{RootCAs: ca.roots, ServerName: "other.test"})
+	require.Error(t, err)
+}
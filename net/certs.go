@@ -1,9 +1,13 @@
 package net
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/nikkolasg/slog"
 )
@@ -43,3 +47,132 @@ func (p *CertManager) Add(certPath string) error {
 	slog.Infof("peer cert: storing server certificate %s", certPath)
 	return nil
 }
+
+// CertSource provides the certificate presented by a TLS listener at each
+// handshake, allowing to renew the certificate without restarting the
+// listener.
+type CertSource interface {
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+}
+
+// KeyPairReloader is a CertSource serving the certificate and private key
+// stored at the given paths. The files are loaded again when Reload is called,
+// for example upon SIGHUP, or when they change if Watch is running. A key pair
+// failing to load leaves the current one in place so a renewal half written
+// to disk does not take the node down.
+type KeyPairReloader struct {
+	certPath string
+	keyPath  string
+
+	sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+	done    chan bool
+}
+
+// NewKeyPairReloader returns a KeyPairReloader serving the key pair at the
+// given paths, which must be valid.
+func NewKeyPairReloader(certPath, keyPath string) (*KeyPairReloader, error) {
+	k := &KeyPairReloader{certPath: certPath, keyPath: keyPath}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload loads the key pair from its files.
+func (k *KeyPairReloader) Reload() error {
+	modTime, err := k.lastModification()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(k.certPath, k.keyPath)
+	if err != nil {
+		return err
+	}
+	k.Lock()
+	defer k.Unlock()
+	k.cert = &cert
+	k.modTime = modTime
+	return nil
+}
+
+// Watch checks the files of the key pair at each period and reloads them when
+// they have been modified, until Stop is called.
+func (k *KeyPairReloader) Watch(period time.Duration) {
+	k.Lock()
+	defer k.Unlock()
+	if k.done != nil {
+		return
+	}
+	k.done = make(chan bool)
+	go k.watch(period, k.done)
+}
+
+func (k *KeyPairReloader) watch(period time.Duration, done chan bool) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+		modTime, err := k.lastModification()
+		if err != nil {
+			continue
+		}
+		k.RLock()
+		modified := !modTime.Equal(k.modTime)
+		k.RUnlock()
+		if !modified {
+			continue
+		}
+		if err := k.Reload(); err != nil {
+			slog.Infof("tls: reloading certificate %s failed: %s", k.certPath, err)
+			continue
+		}
+		slog.Infof("tls: reloaded certificate %s", k.certPath)
+	}
+}
+
+// Stop stops watching the files of the key pair.
+func (k *KeyPairReloader) Stop() {
+	k.Lock()
+	defer k.Unlock()
+	if k.done != nil {
+		close(k.done)
+		k.done = nil
+	}
+}
+
+// lastModification returns the latest modification time of the files of the
+// key pair
+func (k *KeyPairReloader) lastModification() (time.Time, error) {
+	var last time.Time
+	for _, p := range []string{k.certPath, k.keyPath} {
+		info, err := os.Stat(p)
+		if err != nil {
+			return last, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
+
+// GetCertificate implements the CertSource interface.
+func (k *KeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	k.RLock()
+	defer k.RUnlock()
+	return k.cert, nil
+}
+
+// GetClientCertificate returns the key pair to present as a client, to the
+// nodes requiring mutual TLS.
+func (k *KeyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	k.RLock()
+	defer k.RUnlock()
+	return k.cert, nil
+}
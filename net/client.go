@@ -21,6 +21,8 @@ type ProtocolClient interface {
 	NewBeacon(p Peer, in *drand.BeaconPacket, opts ...CallOption) (*drand.Empty, error)
 	Setup(p Peer, in *drand.SetupPacket, opts ...CallOption) (*drand.Empty, error)
 	Reshare(p Peer, in *drand.ResharePacket, opts ...CallOption) (*drand.Empty, error)
+	AnnounceAddress(p Peer, in *drand.AddressAnnouncement, opts ...CallOption) (*drand.Empty, error)
+	Gossip(p Peer, in *drand.GossipPacket, opts ...CallOption) (*drand.Empty, error)
 	SetTimeout(time.Duration)
 }
 
@@ -2,6 +2,8 @@ package net
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"strings"
@@ -12,6 +14,8 @@ import (
 	"github.com/nikkolasg/slog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
 )
 
 var _ Client = (*grpcClient)(nil)
@@ -28,6 +32,10 @@ type grpcClient struct {
 	timeout  time.Duration
 	manager  *CertManager
 	failFast grpc.CallOption
+	// certificate presented to the nodes requiring mutual TLS
+	certs *KeyPairReloader
+	// health of the peers, skipping the ones down
+	health *healthTracker
 }
 
 var defaultTimeout = 1 * time.Minute
@@ -35,11 +43,13 @@ var defaultTimeout = 1 * time.Minute
 // NewGrpcClient returns an implementation of an InternalClient  and
 // ExternalClient using gRPC connections
 func NewGrpcClient(opts ...grpc.DialOption) Client {
-	return &grpcClient{
+	client := &grpcClient{
 		opts:    opts,
 		conns:   make(map[string]*grpc.ClientConn),
 		timeout: defaultTimeout,
 	}
+	client.health = newHealthTracker(DefaultHealthConfig, client.deleteAddr)
+	return client
 }
 
 // NewGrpcClientFromCertManager returns a Client using gRPC with the given trust
@@ -50,6 +60,15 @@ func NewGrpcClientFromCertManager(c *CertManager, opts ...grpc.DialOption) Clien
 	return client
 }
 
+// NewGrpcClientWithCertificate returns a Client using gRPC with the given trust
+// store of certificates, presenting the current certificate of the given key
+// pair to the nodes requiring mutual TLS.
+func NewGrpcClientWithCertificate(c *CertManager, certs *KeyPairReloader, opts ...grpc.DialOption) Client {
+	client := NewGrpcClientFromCertManager(c, opts...).(*grpcClient)
+	client.certs = certs
+	return client
+}
+
 // NewGrpcClientWithTimeout returns a Client using gRPC using fixed timeout for
 // method calls.
 func NewGrpcClientWithTimeout(timeout time.Duration, opts ...grpc.DialOption) Client {
@@ -178,6 +197,28 @@ func (g *grpcClient) Reshare(p Peer, in *drand.ResharePacket, opts ...CallOption
 	return resp, err
 }
 
+func (g *grpcClient) AnnounceAddress(p Peer, in *drand.AddressAnnouncement, opts ...CallOption) (*drand.Empty, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewProtocolClient(c)
+	ctx, cancel := g.getTimeoutContext(context.Background())
+	defer cancel()
+	return client.AnnounceAddress(ctx, in, opts...)
+}
+
+func (g *grpcClient) Gossip(p Peer, in *drand.GossipPacket, opts ...CallOption) (*drand.Empty, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewGossipClient(c)
+	ctx, cancel := g.getTimeoutContext(context.Background())
+	defer cancel()
+	return client.Beacon(ctx, in, opts...)
+}
+
 func (g *grpcClient) NewBeacon(p Peer, in *drand.BeaconPacket, opts ...CallOption) (*drand.Empty, error) {
 	do := func() (*drand.Empty, error) {
 		c, err := g.conn(p)
@@ -247,9 +288,28 @@ func (g *grpcClient) Home(p Peer, in *drand.HomeRequest) (*drand.HomeResponse, e
 }
 
 func (g *grpcClient) deleteConn(p Peer) {
+	g.deleteAddr(p.Address())
+}
+
+// deleteAddr closes and forgets the connection to the given address, so the
+// next call dials it again
+func (g *grpcClient) deleteAddr(addr string) {
 	g.Lock()
 	defer g.Unlock()
-	delete(g.conns, p.Address())
+	if c, ok := g.conns[addr]; ok && c != nil {
+		c.Close()
+	}
+	delete(g.conns, addr)
+}
+
+// PeerStatus returns the health of the connection to the given address
+func (g *grpcClient) PeerStatus(addr string) PeerStatus {
+	return g.health.status(addr)
+}
+
+// PeerAlive resets the health of the connection to the given address
+func (g *grpcClient) PeerAlive(addr string) {
+	g.health.reset(addr)
 }
 
 // conn retrieve an already existing conn to the given peer or create a new one
@@ -260,22 +320,54 @@ func (g *grpcClient) conn(p Peer) (*grpc.ClientConn, error) {
 	c, ok := g.conns[p.Address()]
 	if !ok {
 		slog.Debugf("grpc-client: attempting connection to %s (TLS %v)", p.Address(), p.IsTLS())
+		// the options given to the client take precedence
+		opts := append(g.health.conf.dialOptions(), g.opts...)
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(g.health.unaryInterceptor(p.Address())),
+			grpc.WithChainStreamInterceptor(g.health.streamInterceptor(p.Address())))
+		target := p.Address()
+		if alt, ok := p.(AlternatePeer); ok && len(alt.AlternateAddresses()) > 0 {
+			target, opts = withAlternates(p.Address(), alt.AlternateAddresses(), opts)
+		}
 		if !p.IsTLS() {
-			c, err = grpc.Dial(p.Address(), append(g.opts, grpc.WithInsecure())...)
+			c, err = grpc.Dial(target, append(opts, grpc.WithInsecure())...)
 		} else {
-			opts := g.opts
-			if g.manager != nil {
+			if g.certs != nil {
+				var pool *x509.CertPool
+				if g.manager != nil {
+					pool = g.manager.Pool()
+				}
+				creds := credentials.NewTLS(&tls.Config{RootCAs: pool, GetClientCertificate: g.certs.GetClientCertificate})
+				opts = append(opts, grpc.WithTransportCredentials(creds))
+			} else if g.manager != nil {
 				pool := g.manager.Pool()
 				creds := credentials.NewClientTLSFromCert(pool, "")
-				opts = append(g.opts, grpc.WithTransportCredentials(creds))
+				opts = append(opts, grpc.WithTransportCredentials(creds))
 			}
-			c, err = grpc.Dial(p.Address(), opts...)
+			c, err = grpc.Dial(target, opts...)
 		}
 		g.conns[p.Address()] = c
 	}
 	return c, err
 }
 
+// alternatesScheme is the scheme of the resolver returning the main and the
+// alternate addresses of a peer
+const alternatesScheme = "drand-alternates"
+
+// withAlternates returns the target and the options to dial a peer reachable
+// at several addresses. The connection uses the first address reachable, in
+// order, and each address is authenticated with its own host name over TLS.
+func withAlternates(addr string, alternates []string, opts []grpc.DialOption) (string, []grpc.DialOption) {
+	addrs := make([]resolver.Address, 0, len(alternates)+1)
+	for _, a := range append([]string{addr}, alternates...) {
+		addrs = append(addrs, resolver.Address{Addr: a, ServerName: a})
+	}
+	r := manual.NewBuilderWithScheme(alternatesScheme)
+	r.InitialState(resolver.State{Addresses: addrs})
+	return alternatesScheme + ":///" + addr, append(opts, grpc.WithResolvers(r))
+}
+
 // proxyClient is used by the gRPC json gateway to dispatch calls to the
 // underlying gRPC server. It needs only to implement the public facing API
 type proxyClient struct {
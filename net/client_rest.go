@@ -4,17 +4,23 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/nikkolasg/hexjson"
 
 	"github.com/drand/drand/protobuf/drand"
 )
 
+// DefaultStreamRetryPeriod is the time the REST client waits before
+// reconnecting a randomness stream that was cut
+var DefaultStreamRetryPeriod = 1 * time.Second
+
 var _ PublicClient = (*restClient)(nil)
 
 type restClient struct {
@@ -39,8 +45,93 @@ func NewRestClientFromCertManager(c *CertManager) PublicClient {
 	return client
 }
 
+// PublicRandStream follows the chunked responses of the stream endpoint of the
+// REST API. When the request gives a round, the server sends the beacons from
+// this round first. A stream that is cut is reconnected from the round
+// expected next, so the channel delivers the rounds in order without
+// duplicates until the context is done.
 func (r *restClient) PublicRandStream(ctx context.Context, p Peer, in *drand.PublicRandRequest, opts ...CallOption) (chan *drand.PublicRandResponse, error) {
-	return nil, errors.New("stream on http client is not supported yet")
+	if _, err := r.httpClient(p); err != nil {
+		return nil, err
+	}
+	outCh := make(chan *drand.PublicRandResponse, 10)
+	go r.followStream(ctx, p, in.GetRound(), outCh)
+	return outCh, nil
+}
+
+// followStream sends the beacons of the stream, reconnecting it until the
+// context is done. next is the round expected next, 0 when the stream only
+// follows the new beacons.
+func (r *restClient) followStream(ctx context.Context, p Peer, next uint64, outCh chan *drand.PublicRandResponse) {
+	defer close(outCh)
+	for {
+		if body, err := r.openStream(ctx, p, next); err == nil {
+			next = r.readStream(ctx, body, next, outCh)
+			body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DefaultStreamRetryPeriod):
+		}
+	}
+}
+
+// readStream sends the beacons of the stream until it is cut and returns the
+// round expected next
+func (r *restClient) readStream(ctx context.Context, body io.Reader, next uint64, outCh chan *drand.PublicRandResponse) uint64 {
+	dec := json.NewDecoder(body)
+	for ctx.Err() == nil {
+		// a chunk holds either a result or the error ending the stream
+		var chunk struct {
+			Result *drand.PublicRandResponse `json:"result"`
+		}
+		if err := dec.Decode(&chunk); err != nil || chunk.Result == nil {
+			return next
+		}
+		next = sendNext(ctx, chunk.Result, next, outCh)
+	}
+	return next
+}
+
+// sendNext sends the beacon if it is not older than the round expected next
+// and returns the round expected after the beacons sent
+func sendNext(ctx context.Context, resp *drand.PublicRandResponse, next uint64, outCh chan *drand.PublicRandResponse) uint64 {
+	if resp.GetRound() < next {
+		return next
+	}
+	select {
+	case outCh <- resp:
+		return resp.GetRound() + 1
+	case <-ctx.Done():
+		return next
+	}
+}
+
+// openStream requests the stream endpoint from the given round, or from the
+// next beacon produced for the round 0, and returns the body of the response
+func (r *restClient) openStream(ctx context.Context, p Peer, from uint64) (io.ReadCloser, error) {
+	url := restAddr(p) + "/api/public/stream"
+	if from != 0 {
+		url = fmt.Sprintf("%s/%d", url, from)
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client, err := r.httpClient(p)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("stream on http client: %s", resp.Status)
+	}
+	return resp.Body, nil
 }
 
 func (r *restClient) PublicRand(p Peer, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
@@ -149,8 +240,22 @@ func (r *restClient) Home(p Peer, in *drand.HomeRequest) (*drand.HomeResponse, e
 
 func (r *restClient) doRequest(remote Peer, req *http.Request) ([]byte, error) {
 	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+	client, err := r.httpClient(remote)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
 
+// httpClient returns a client verifying the certificate of the remote when
+// it uses TLS
+func (r *restClient) httpClient(remote Peer) (*http.Client, error) {
+	client := &http.Client{}
 	if remote.IsTLS() {
 		h, _, err := net.SplitHostPort(remote.Address())
 		if err != nil {
@@ -165,11 +270,7 @@ func (r *restClient) doRequest(remote Peer, req *http.Request) ([]byte, error) {
 		}
 		client.Transport = &http.Transport{TLSClientConfig: conf}
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	return ioutil.ReadAll(resp.Body)
+	return client, nil
 }
 
 func restAddr(p Peer) string {
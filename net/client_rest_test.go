@@ -0,0 +1,148 @@
+package net
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+)
+
+// chainServer serves a chain of beacons and streams the new ones
+type chainServer struct {
+	*testRandomnessServer
+	sync.Mutex
+	chain  map[uint64]*drand.PublicRandResponse
+	latest uint64
+	// new beacons, sent to the current stream
+	beacons chan *drand.PublicRandResponse
+	// cuts the current stream
+	cut chan bool
+}
+
+func newChainServer(rounds ...uint64) *chainServer {
+	c := &chainServer{
+		testRandomnessServer: &testRandomnessServer{},
+		chain:                make(map[uint64]*drand.PublicRandResponse),
+		beacons:              make(chan *drand.PublicRandResponse),
+		cut:                  make(chan bool),
+	}
+	for _, r := range rounds {
+		c.add(r)
+	}
+	return c
+}
+
+// add appends the round to the chain and returns its beacon
+func (c *chainServer) add(round uint64) *drand.PublicRandResponse {
+	c.Lock()
+	defer c.Unlock()
+	b := &drand.PublicRandResponse{PreviousRound: c.latest, Round: round}
+	c.chain[round] = b
+	c.latest = round
+	return b
+}
+
+func (c *chainServer) PublicRand(ctx context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	round := in.GetRound()
+	if round == 0 {
+		round = c.latest
+	}
+	return c.chain[round], nil
+}
+
+// PublicRandStream replays the chain from the requested round, if any, then
+// sends the new beacons
+func (c *chainServer) PublicRandStream(in *drand.PublicRandRequest, stream drand.Public_PublicRandStreamServer) error {
+	if in.GetRound() != 0 {
+		c.Lock()
+		var replay []*drand.PublicRandResponse
+		for round := in.GetRound(); round <= c.latest; round++ {
+			if b, ok := c.chain[round]; ok {
+				replay = append(replay, b)
+			}
+		}
+		c.Unlock()
+		for _, b := range replay {
+			if err := stream.Send(b); err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		select {
+		case b := <-c.beacons:
+			if err := stream.Send(b); err != nil {
+				return err
+			}
+		case <-c.cut:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func TestRestClientStream(t *testing.T) {
+	DefaultStreamRetryPeriod = 50 * time.Millisecond
+	addr := "127.0.0.1:4010"
+	peer := &testPeer{addr, false}
+	// round 4 is missing, as when the group was down
+	server := newChainServer(1, 2, 3, 5)
+	lis := NewTCPGrpcListener(addr, server)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	expect := func(ch chan *drand.PublicRandResponse, rounds ...uint64) {
+		for _, round := range rounds {
+			select {
+			case resp := <-ch:
+				require.Equal(t, round, resp.GetRound())
+			case <-time.After(5 * time.Second):
+				t.Fatalf("round %d not received", round)
+			}
+		}
+	}
+
+	client := NewRestClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	// the stream starts with the beacons of the chain from round 2
+	ch, err := client.PublicRandStream(ctx, peer, &drand.PublicRandRequest{Round: 2})
+	require.NoError(t, err)
+	expect(ch, 2, 3, 5)
+	server.beacons <- server.add(6)
+	expect(ch, 6)
+
+	// the beacons missed while the stream is cut are replayed on reconnection
+	server.cut <- true
+	server.add(7)
+	server.add(8)
+	expect(ch, 7, 8)
+	server.beacons <- server.add(9)
+	expect(ch, 9)
+	require.Len(t, ch, 0)
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not closed")
+	}
+
+	// without any round, only the new beacons are streamed, once the server
+	// ended the previous stream
+	time.Sleep(100 * time.Millisecond)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ch, err = client.PublicRandStream(ctx, peer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	server.beacons <- server.add(10)
+	expect(ch, 10)
+	require.Len(t, ch, 0)
+}
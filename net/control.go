@@ -3,7 +3,10 @@ package net
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 
 	control "github.com/drand/drand/protobuf/drand"
 
@@ -15,6 +18,8 @@ import (
 type ControlListener struct {
 	conns *grpc.Server
 	lis   net.Listener
+	// path of the Unix socket to remove once stopped, if any
+	socket string
 }
 
 //NewTCPGrpcControlListener registers the pairing between a ControlServer and a grpx server
@@ -29,6 +34,69 @@ func NewTCPGrpcControlListener(s control.ControlServer, port string) ControlList
 	return ControlListener{conns: grpcServer, lis: lis}
 }
 
+// NewTCPGrpcControlListenerWithToken returns a control listener on the given
+// localhost port only serving the clients presenting the given token.
+func NewTCPGrpcControlListenerWithToken(s control.ControlServer, port, token string) ControlListener {
+	lis, err := net.Listen("tcp", controlListenAddr(port))
+	if err != nil {
+		slog.Fatalf("grpc listener failure: %s", err)
+		return ControlListener{}
+	}
+	auth := &tokenAuth{token: token}
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor))
+	control.RegisterControlServer(grpcServer, s)
+	return ControlListener{conns: grpcServer, lis: lis}
+}
+
+// NewUnixGrpcControlListener returns a control listener on a Unix socket at
+// the given path, only accessible by the user running the daemon. A socket
+// left at this path by a previous daemon is replaced.
+func NewUnixGrpcControlListener(s control.ControlServer, socketPath string) (ControlListener, error) {
+	if fi, err := os.Lstat(socketPath); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return ControlListener{}, fmt.Errorf("control: %s exists and is not a socket", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return ControlListener{}, err
+		}
+	}
+	lis, err := listenPrivateUnix(socketPath)
+	if err != nil {
+		return ControlListener{}, err
+	}
+	grpcServer := grpc.NewServer()
+	control.RegisterControlServer(grpcServer, s)
+	return ControlListener{conns: grpcServer, lis: lis, socket: socketPath}, nil
+}
+
+// listenPrivateUnix listens on a Unix socket at the given path, only
+// accessible by the current user. The socket is created and restricted inside
+// a directory private to the user, then moved to its path, so no other user
+// can connect to it in between.
+func listenPrivateUnix(socketPath string) (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(socketPath), ".control")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, filepath.Base(socketPath))
+	lis, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, socketPath); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return lis, nil
+}
+
 // Start the listener for the control commands
 func (g *ControlListener) Start() {
 	if err := g.conns.Serve(g.lis); err != nil {
@@ -39,6 +107,9 @@ func (g *ControlListener) Start() {
 // Stop the listener and connections
 func (g *ControlListener) Stop() {
 	g.conns.Stop()
+	if g.socket != "" {
+		os.Remove(g.socket)
+	}
 }
 
 //ControlClient is a struct that implement control.ControlClient and is used to
@@ -61,41 +132,92 @@ func NewControlClient(port string) (*ControlClient, error) {
 	return &ControlClient{conn: conn, client: c}, nil
 }
 
+// NewControlClientWithToken creates a client issuing control commands to a
+// localhost running drand node requiring the given token.
+func NewControlClientWithToken(port, token string) (*ControlClient, error) {
+	conn, err := grpc.Dial(controlListenAddr(port), grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(&tokenCredentials{token: token}))
+	if err != nil {
+		return nil, err
+	}
+	c := control.NewControlClient(conn)
+	return &ControlClient{conn: conn, client: c}, nil
+}
+
+// NewUnixControlClient creates a client issuing control commands to a drand
+// node over the Unix socket at the given path. It refuses a socket accessible
+// by other users than its owner, which the daemon never creates.
+func NewUnixControlClient(socketPath string) (*ControlClient, error) {
+	fi, err := os.Stat(socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("control: no socket at %s: is the daemon running?", socketPath)
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return nil, fmt.Errorf("control: %s is not a socket", socketPath)
+	}
+	if fi.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("control: socket %s is accessible by other users (%s)", socketPath, fi.Mode().Perm())
+	}
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", addr)
+	}
+	conn, err := grpc.Dial(socketPath, grpc.WithInsecure(), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, err
+	}
+	c := control.NewControlClient(conn)
+	return &ControlClient{conn: conn, client: c}, nil
+}
+
 // Ping the drand daemon to check if it's up and running
 func (c *ControlClient) Ping() error {
 	_, err := c.client.PingPong(context.Background(), &control.Ping{})
 	return err
 }
 
+// DKGRunning returns true if the daemon has set up a DKG and is waiting for the
+// packets of the other nodes.
+func (c *ControlClient) DKGRunning() (bool, error) {
+	pong, err := c.client.PingPong(context.Background(), &control.Ping{})
+	if err != nil {
+		return false, err
+	}
+	return pong.GetDkgRunning(), nil
+}
+
 // InitReshare sets up the node to be ready for a resharing protocol.
-// oldPath and newPath represents the paths in the filesystems of the old group
-// and the new group respectively. Leader is true if the destination node should
-// start the protocol.
-// NOTE: only group referral via filesystem path is supported at the moment.
+// old and new represents the location of the old group and the new group
+// respectively. old can be nil, in which case the daemon uses its current
+// group. Leader is true if the destination node should start the protocol.
 // XXX Might be best to move to core/
-func (c *ControlClient) InitReshare(oldPath, newPath string, leader bool, timeout string) (*control.Empty, error) {
+func (c *ControlClient) InitReshare(old, new *control.GroupInfo, leader bool, timeout string) (*control.Empty, error) {
 	request := &control.InitResharePacket{
-		Old: &control.GroupInfo{
-			Location: &control.GroupInfo_Path{Path: oldPath},
-		},
-		New: &control.GroupInfo{
-			Location: &control.GroupInfo_Path{Path: newPath},
-		},
+		Old:      old,
+		New:      new,
 		IsLeader: leader,
 		Timeout:  timeout,
 	}
 	return c.client.InitReshare(context.Background(), request)
 }
 
+// ReshareDryRun asks the node to check the resharing from the old group to
+// the new group without running it. old can be nil, in which case the daemon
+// uses its current group.
+func (c *ControlClient) ReshareDryRun(old, new *control.GroupInfo, timeout string) (*control.ReshareDryRunResponse, error) {
+	request := &control.InitResharePacket{
+		Old:     old,
+		New:     new,
+		Timeout: timeout,
+	}
+	return c.client.ReshareDryRun(context.Background(), request)
+}
+
 // InitDKG sets up the node to be ready for a first DKG protocol.
-// groupPart
-// NOTE: only group referral via filesystem path is supported at the moment.
 // XXX Might be best to move to core/
-func (c *ControlClient) InitDKG(groupPath string, leader bool, timeout string, entropy *control.EntropyInfo) (*control.Empty, error) {
+func (c *ControlClient) InitDKG(group *control.GroupInfo, leader bool, timeout string, entropy *control.EntropyInfo) (*control.Empty, error) {
 	request := &control.InitDKGPacket{
-		DkgGroup: &control.GroupInfo{
-			Location: &control.GroupInfo_Path{Path: groupPath},
-		},
+		DkgGroup: group,
 		IsLeader: leader,
 		Timeout:  timeout,
 		Entropy:  entropy,
@@ -103,6 +225,25 @@ func (c *ControlClient) InitDKG(groupPath string, leader bool, timeout string, e
 	return c.client.InitDKG(context.Background(), request)
 }
 
+// GroupFromPath returns the location of a group file stored on the filesystem
+// of the daemon. If hash is not empty, the daemon rejects any group with a
+// different hash.
+func GroupFromPath(path, hash string) *control.GroupInfo {
+	return &control.GroupInfo{
+		Location: &control.GroupInfo_Path{Path: path},
+		Hash:     hash,
+	}
+}
+
+// GroupFromURL returns the location of a group file served over http(s). If
+// hash is not empty, the daemon rejects any group with a different hash.
+func GroupFromURL(url, hash string) *control.GroupInfo {
+	return &control.GroupInfo{
+		Location: &control.GroupInfo_Url{Url: url},
+		Hash:     hash,
+	}
+}
+
 // Share returns the share of the remote node
 func (c ControlClient) Share() (*control.ShareResponse, error) {
 	return c.client.Share(context.Background(), &control.ShareRequest{})
@@ -128,6 +269,18 @@ func (c ControlClient) GroupFile() (*control.GroupTOMLResponse, error) {
 	return c.client.GroupFile(context.Background(), &control.GroupTOMLRequest{})
 }
 
+// UpdateAddress updates the addresses and metadata of the node and announces
+// them to the group
+func (c ControlClient) UpdateAddress(in *control.UpdateAddressRequest) (*control.UpdateAddressResponse, error) {
+	return c.client.UpdateAddress(context.Background(), in)
+}
+
+// PeerStatus returns the health of the connections of the node to the other
+// nodes of its group
+func (c ControlClient) PeerStatus() (*control.PeerStatusResponse, error) {
+	return c.client.PeerStatus(context.Background(), &control.PeerStatusRequest{})
+}
+
 // Shutdown stops the daemon
 func (c ControlClient) Shutdown() (*control.ShutdownResponse, error) {
 	return c.client.Shutdown(context.Background(), &control.ShutdownRequest{})
@@ -0,0 +1,75 @@
+package net
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testControlServer struct {
+	*EmptyServer
+}
+
+func (t *testControlServer) PingPong(context.Context, *drand.Ping) (*drand.Pong, error) {
+	return new(drand.Pong), nil
+}
+
+func TestControlUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-control")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := path.Join(dir, "control.sock")
+
+	lis, err := NewUnixGrpcControlListener(&testControlServer{}, socketPath)
+	require.NoError(t, err)
+	go lis.Start()
+	defer lis.Stop()
+
+	fi, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	// the private directory the socket is created in is removed
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	client, err := NewUnixControlClient(socketPath)
+	require.NoError(t, err)
+	require.NoError(t, client.Ping())
+
+	// a socket accessible by other users is refused
+	require.NoError(t, os.Chmod(socketPath, 0666))
+	_, err = NewUnixControlClient(socketPath)
+	require.Error(t, err)
+
+	lis.Stop()
+	_, err = os.Stat(socketPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestControlToken(t *testing.T) {
+	port := "4002"
+	token := "secret-token"
+	lis := NewTCPGrpcControlListenerWithToken(&testControlServer{}, port, token)
+	go lis.Start()
+	defer lis.Stop()
+
+	client, err := NewControlClient(port)
+	require.NoError(t, err)
+	require.Equal(t, codes.Unauthenticated, status.Code(client.Ping()))
+
+	client, err = NewControlClientWithToken(port, "wrong-token")
+	require.NoError(t, err)
+	require.Equal(t, codes.Unauthenticated, status.Code(client.Ping()))
+
+	client, err = NewControlClientWithToken(port, token)
+	require.NoError(t, err)
+	require.NoError(t, client.Ping())
+}
@@ -74,6 +74,11 @@ func (s *EmptyServer) InitReshare(context.Context, *drand.InitResharePacket) (*d
 	return nil, nil
 }
 
+// ReshareDryRun ...
+func (s *EmptyServer) ReshareDryRun(context.Context, *drand.InitResharePacket) (*drand.ReshareDryRunResponse, error) {
+	return nil, nil
+}
+
 // Share ...
 func (s *EmptyServer) Share(context.Context, *drand.ShareRequest) (*drand.ShareResponse, error) {
 	return nil, nil
@@ -103,3 +108,23 @@ func (s *EmptyServer) GroupFile(context.Context, *drand.GroupTOMLRequest) (*dran
 func (s *EmptyServer) Shutdown(context.Context, *drand.ShutdownRequest) (*drand.ShutdownResponse, error) {
 	return nil, nil
 }
+
+// AnnounceAddress ...
+func (s *EmptyServer) AnnounceAddress(context.Context, *drand.AddressAnnouncement) (*drand.Empty, error) {
+	return nil, nil
+}
+
+// Beacon ...
+func (s *EmptyServer) Beacon(context.Context, *drand.GossipPacket) (*drand.Empty, error) {
+	return nil, nil
+}
+
+// UpdateAddress ...
+func (s *EmptyServer) UpdateAddress(context.Context, *drand.UpdateAddressRequest) (*drand.UpdateAddressResponse, error) {
+	return nil, nil
+}
+
+// PeerStatus ...
+func (s *EmptyServer) PeerStatus(context.Context, *drand.PeerStatusRequest) (*drand.PeerStatusResponse, error) {
+	return nil, nil
+}
@@ -19,6 +19,9 @@ import (
 type Gateway struct {
 	Listener
 	ProtocolClient
+	// PublicListener serves the public methods on their own address when
+	// set, the Listener then only serving the protocol methods
+	PublicListener Listener
 }
 
 // CallOption is simply a wrapper around the grpc options
@@ -36,6 +39,7 @@ type Service interface {
 	drand.PublicServer
 	drand.ControlServer
 	drand.ProtocolServer
+	drand.GossipServer
 }
 
 // NewGrpcGatewayInsecure returns a grpc Gateway listening on "listen" for the
@@ -51,12 +55,16 @@ func NewGrpcGatewayInsecure(listen string, s Service, opts ...grpc.DialOption) G
 // NewGrpcGatewayFromCertManager returns a grpc gateway using the TLS
 // certificate manager
 func NewGrpcGatewayFromCertManager(listen string, certPath, keyPath string, certs *CertManager, s Service, opts ...grpc.DialOption) Gateway {
-	l, err := NewTLSGrpcListener(listen, certPath, keyPath, s, grpc.ConnectionTimeout(500*time.Millisecond))
+	keyPair, err := NewKeyPairReloader(certPath, keyPath)
+	if err != nil {
+		panic(err)
+	}
+	l, err := NewTLSGrpcListenerFor(listen, keyPair, s, AllServices, nil, nil, grpc.ConnectionTimeout(500*time.Millisecond))
 	if err != nil {
 		panic(err)
 	}
 	return Gateway{
-		ProtocolClient: NewGrpcClientFromCertManager(certs, opts...),
+		ProtocolClient: NewGrpcClientWithCertificate(certs, keyPair, opts...),
 		Listener:       l,
 	}
 }
@@ -64,9 +72,15 @@ func NewGrpcGatewayFromCertManager(listen string, certPath, keyPath string, cert
 // StartAll starts the control and public functionalities of the node
 func (g Gateway) StartAll() {
 	go g.Listener.Start()
+	if g.PublicListener != nil {
+		go g.PublicListener.Start()
+	}
 }
 
 // StopAll stops the control and public functionalities of the node
 func (g Gateway) StopAll() {
 	g.Listener.Stop()
+	if g.PublicListener != nil {
+		g.PublicListener.Stop()
+	}
 }
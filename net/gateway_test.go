@@ -1,9 +1,14 @@
 package net
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"path"
@@ -14,6 +19,8 @@ import (
 	"github.com/drand/drand/protobuf/drand"
 	"github.com/kabukky/httpscerts"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type testPeer struct {
@@ -80,6 +87,33 @@ func TestListener(t *testing.T) {
 	require.Equal(t, expected.GetRound(), resp.GetRound())
 }
 
+type testAlternatePeer struct {
+	testPeer
+	alternates []string
+}
+
+func (t *testAlternatePeer) AlternateAddresses() []string {
+	return t.alternates
+}
+
+func TestListenerAlternateAddresses(t *testing.T) {
+	// nobody listens on the main address
+	addr := "127.0.0.1:4002"
+	alternate := "127.0.0.1:4003"
+	peer := &testAlternatePeer{testPeer{addr, false}, []string{alternate}}
+	randServer := &testRandomnessServer{round: 42}
+
+	lis := NewTCPGrpcListener(alternate, randServer)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient()
+	resp, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+}
+
 // ref https://bbengfort.github.io/programmer/2017/03/03/secure-grpc.html
 func TestListenerTLS(t *testing.T) {
 	if run.GOOS == "windows" {
@@ -124,3 +158,168 @@ func TestListenerTLS(t *testing.T) {
 	expected = &drand.PublicRandResponse{Round: randServer.round}
 	require.Equal(t, expected.GetRound(), resp.GetRound())
 }
+
+func TestListenerMutualTLS(t *testing.T) {
+	if run.GOOS == "windows" {
+		t.Skip("crypto/x509: system root pool is not available on Windows")
+	}
+	addr1 := "127.0.0.1:4001"
+	peer1 := &testPeer{addr1, true}
+
+	tmpDir := path.Join(os.TempDir(), "drand-net-mtls")
+	require.NoError(t, os.MkdirAll(tmpDir, 0766))
+	defer os.RemoveAll(tmpDir)
+	certPath := path.Join(tmpDir, "server.crt")
+	keyPath := path.Join(tmpDir, "server.key")
+	h, _, _ := net.SplitHostPort(addr1)
+	require.NoError(t, httpscerts.Generate(certPath, keyPath, h))
+
+	certManager := NewCertManager()
+	require.NoError(t, certManager.Add(certPath))
+	var allowed []Peer
+	peers := func() []Peer { return allowed }
+
+	keyPair, err := NewKeyPairReloader(certPath, keyPath)
+	require.NoError(t, err)
+
+	randServer := &testRandomnessServer{round: 42}
+	lis1, err := NewMutualTLSGrpcListener(addr1, keyPair, certManager, peers, randServer, AllServices, nil, nil)
+	require.NoError(t, err)
+	go lis1.Start()
+	defer lis1.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	// the public methods are open to clients without certificate
+	anonymous := NewGrpcClientFromCertManager(certManager)
+	resp, err := anonymous.PublicRand(peer1, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+	_, err = anonymous.NewBeacon(peer1, &drand.BeaconPacket{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// a trusted certificate of a node outside of the group is rejected
+	client := NewGrpcClientWithCertificate(certManager, keyPair)
+	_, err = client.NewBeacon(peer1, &drand.BeaconPacket{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// the identity of the node must bind the key of its certificate
+	allowed = []Peer{peer1}
+	_, err = client.NewBeacon(peer1, &drand.BeaconPacket{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	cert, err := keyPair.GetCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	hash := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+	other := sha256.Sum256([]byte("another node on the same host"))
+	allowed = []Peer{&boundPeer{testPeer: peer1, hash: other[:]}}
+	_, err = client.NewBeacon(peer1, &drand.BeaconPacket{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// the call reaches the service once the node is in the group, the host of
+	// the certificate matching one of the alternate addresses of the node
+	moved := &testPeer{"drand.invalid:4001", true}
+	allowed = []Peer{&boundPeer{testPeer: moved, hash: hash[:], alternates: []string{addr1}}}
+	_, err = client.NewBeacon(peer1, &drand.BeaconPacket{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no beacon")
+}
+
+// boundPeer is a peer binding the key of its TLS certificate
+type boundPeer struct {
+	*testPeer
+	hash       []byte
+	alternates []string
+}
+
+func (b *boundPeer) BoundTLSKey() []byte {
+	return b.hash
+}
+
+func (b *boundPeer) AlternateAddresses() []string {
+	return b.alternates
+}
+
+func TestListenerServices(t *testing.T) {
+	protoAddr := "127.0.0.1:4003"
+	publicAddr := "127.0.0.1:4004"
+	protoPeer := &testPeer{protoAddr, false}
+	publicPeer := &testPeer{publicAddr, false}
+	randServer := &testRandomnessServer{round: 42}
+
+	protoLis := NewTCPGrpcListenerFor(protoAddr, randServer, ProtocolServices, nil, nil)
+	go protoLis.Start()
+	defer protoLis.Stop()
+	publicLis := NewTCPGrpcListenerFor(publicAddr, randServer, PublicServices, nil, nil)
+	go publicLis.Start()
+	defer publicLis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient()
+	_, err := client.PublicRand(protoPeer, &drand.PublicRandRequest{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = client.NewBeacon(protoPeer, &drand.BeaconPacket{})
+	require.Error(t, err)
+	require.NotEqual(t, codes.Unimplemented, status.Code(err))
+
+	resp, err := client.PublicRand(publicPeer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+	_, err = client.NewBeacon(publicPeer, &drand.BeaconPacket{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	rest := NewRestClient()
+	resp, err = rest.PublicRand(publicPeer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+	_, err = rest.PublicRand(protoPeer, &drand.PublicRandRequest{})
+	require.Error(t, err)
+}
+
+func TestListenerCertificateReload(t *testing.T) {
+	addr1 := "127.0.0.1:4005"
+	tmpDir := path.Join(os.TempDir(), "drand-net-reload")
+	require.NoError(t, os.MkdirAll(tmpDir, 0766))
+	defer os.RemoveAll(tmpDir)
+	certPath := path.Join(tmpDir, "server.crt")
+	keyPath := path.Join(tmpDir, "server.key")
+	h, _, _ := net.SplitHostPort(addr1)
+	require.NoError(t, httpscerts.Generate(certPath, keyPath, h))
+
+	keyPair, err := NewKeyPairReloader(certPath, keyPath)
+	require.NoError(t, err)
+	keyPair.Watch(50 * time.Millisecond)
+	defer keyPair.Stop()
+
+	randServer := &testRandomnessServer{round: 42}
+	lis1, err := NewTLSGrpcListenerFor(addr1, keyPair, randServer, AllServices, nil, nil)
+	require.NoError(t, err)
+	go lis1.Start()
+	defer lis1.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	served := func() []byte {
+		conn, err := tls.Dial("tcp", addr1, &tls.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Raw
+	}
+	first := served()
+
+	// a renewed certificate is served without restarting the listener
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, httpscerts.Generate(certPath, keyPath, h))
+	var renewed []byte
+	for i := 0; i < 40; i++ {
+		if renewed = served(); !bytes.Equal(first, renewed) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.NotEqual(t, first, renewed)
+
+	// a key pair failing to load leaves the current one in place
+	require.NoError(t, ioutil.WriteFile(keyPath, []byte("garbage"), 0600))
+	require.Error(t, keyPair.Reload())
+	require.Equal(t, renewed, served())
+}
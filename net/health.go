@@ -0,0 +1,291 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// States of the connection to a peer
+const (
+	// PeerUnknown is the state of a peer not contacted yet
+	PeerUnknown = "unknown"
+	// PeerHealthy is the state of a peer whose last call succeeded
+	PeerHealthy = "healthy"
+	// PeerDegraded is the state of a peer whose last calls failed, less than
+	// the failures allowed before skipping it
+	PeerDegraded = "degraded"
+	// PeerDown is the state of a peer whose calls are skipped until its next
+	// retry
+	PeerDown = "down"
+)
+
+// PeerStatus is the health of the connection to a peer, as seen by the client
+// when calling it.
+type PeerStatus struct {
+	Address string
+	State   string
+	// Failures is the number of consecutive failed calls
+	Failures    int
+	LastSuccess time.Time
+	LastFailure time.Time
+	LastError   string
+	// RetryAt is the time at which a peer down is contacted again
+	RetryAt time.Time
+}
+
+// HealthReporter is implemented by the clients tracking the health of the
+// connections to their peers.
+type HealthReporter interface {
+	PeerStatus(addr string) PeerStatus
+	// PeerAlive resets the health of the peer, known to be reachable again
+	// for example because it contacted this node
+	PeerAlive(addr string)
+}
+
+// HealthConfig configures the tracking of the health of the peers.
+type HealthConfig struct {
+	// MaxFailures is the number of consecutive failed calls after which a
+	// peer is considered down and its calls are skipped
+	MaxFailures int
+	// BaseDelay is the time a peer down is skipped for the first time, doubled
+	// each time it is still down when retried
+	BaseDelay time.Duration
+	// MaxDelay bounds the time a peer down is skipped for
+	MaxDelay time.Duration
+	// KeepaliveTime is the period of the pings checking idle connections
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is the time after which a connection whose ping is not
+	// answered is closed
+	KeepaliveTimeout time.Duration
+}
+
+// DefaultHealthConfig is the health tracking used by the gRPC client
+var DefaultHealthConfig = HealthConfig{
+	MaxFailures:      3,
+	BaseDelay:        1 * time.Second,
+	MaxDelay:         1 * time.Minute,
+	KeepaliveTime:    30 * time.Second,
+	KeepaliveTimeout: 10 * time.Second,
+}
+
+// keepaliveMinTime is the minimal period of the pings accepted by the
+// listeners, below the one of the clients
+var keepaliveMinTime = 10 * time.Second
+
+// keepaliveServerOptions returns the options allowing the clients to ping
+// their idle connections
+func keepaliveServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// dialOptions returns the keepalive and reconnection options of the
+// connections to the peers
+func (c HealthConfig) dialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                c.KeepaliveTime,
+			Timeout:             c.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  c.BaseDelay,
+				Multiplier: 2,
+				Jitter:     0.2,
+				MaxDelay:   c.MaxDelay,
+			},
+			MinConnectTimeout: c.BaseDelay,
+		}),
+	}
+}
+
+// peerHealth is a circuit breaker on the calls to a peer: after MaxFailures
+// consecutive failures, the calls fail immediately until the retry time, with
+// an exponential backoff between the retries. The first call after the retry
+// time goes through and closes the circuit if it succeeds.
+type peerHealth struct {
+	failures    int
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	delay       time.Duration
+	retryAt     time.Time
+	// probing is true while the call retrying a peer down is running
+	probing bool
+}
+
+// healthTracker tracks the health of the peers of a client
+type healthTracker struct {
+	sync.Mutex
+	conf  HealthConfig
+	peers map[string]*peerHealth
+	now   func() time.Time
+	// onDown is called when a peer goes down, to drop its connection
+	onDown func(addr string)
+}
+
+func newHealthTracker(conf HealthConfig, onDown func(string)) *healthTracker {
+	return &healthTracker{
+		conf:   conf,
+		peers:  make(map[string]*peerHealth),
+		now:    time.Now,
+		onDown: onDown,
+	}
+}
+
+// allow returns an error if the calls to the peer are skipped
+func (h *healthTracker) allow(addr string) error {
+	h.Lock()
+	defer h.Unlock()
+	p, ok := h.peers[addr]
+	if !ok || p.failures < h.conf.MaxFailures {
+		return nil
+	}
+	if p.probing || h.now().Before(p.retryAt) {
+		return &peerDownError{addr: addr, retryAt: p.retryAt}
+	}
+	// let this call probe the peer
+	p.probing = true
+	return nil
+}
+
+// record updates the health of the peer with the result of a call
+func (h *healthTracker) record(addr string, err error) {
+	h.Lock()
+	p, ok := h.peers[addr]
+	if !ok {
+		p = new(peerHealth)
+		h.peers[addr] = p
+	}
+	p.probing = false
+	if !isConnectionFailure(err) {
+		p.failures = 0
+		p.delay = 0
+		p.lastSuccess = h.now()
+		h.Unlock()
+		return
+	}
+	p.failures++
+	p.lastFailure = h.now()
+	p.lastError = err.Error()
+	down := p.failures >= h.conf.MaxFailures
+	if down {
+		if p.delay == 0 {
+			p.delay = h.conf.BaseDelay
+		} else if p.delay *= 2; p.delay > h.conf.MaxDelay {
+			p.delay = h.conf.MaxDelay
+		}
+		p.retryAt = p.lastFailure.Add(p.delay)
+	}
+	h.Unlock()
+	if down && h.onDown != nil {
+		h.onDown(addr)
+	}
+}
+
+// reset forgets the failures of the peer
+func (h *healthTracker) reset(addr string) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.peers, addr)
+}
+
+// status returns the health of the peer
+func (h *healthTracker) status(addr string) PeerStatus {
+	h.Lock()
+	defer h.Unlock()
+	s := PeerStatus{Address: addr, State: PeerUnknown}
+	p, ok := h.peers[addr]
+	if !ok {
+		return s
+	}
+	s.Failures = p.failures
+	s.LastSuccess = p.lastSuccess
+	s.LastFailure = p.lastFailure
+	s.LastError = p.lastError
+	switch {
+	case p.failures == 0:
+		s.State = PeerHealthy
+	case p.failures < h.conf.MaxFailures:
+		s.State = PeerDegraded
+	default:
+		s.State = PeerDown
+		s.RetryAt = p.retryAt
+	}
+	return s
+}
+
+// unaryInterceptor applies the circuit breaker of the peer to the unary calls
+// made on its connection
+func (h *healthTracker) unaryInterceptor(addr string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := h.allow(addr); err != nil {
+			return err
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		h.record(addr, err)
+		return err
+	}
+}
+
+// streamInterceptor applies the circuit breaker of the peer to the opening of
+// the streams on its connection
+func (h *healthTracker) streamInterceptor(addr string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := h.allow(addr); err != nil {
+			return nil, err
+		}
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		h.record(addr, err)
+		return s, err
+	}
+}
+
+// isConnectionFailure returns true if the error shows the peer could not be
+// reached or did not answer in time, as opposed to an error returned by the
+// peer itself
+func isConnectionFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// peerDownError is returned for the calls skipped because the peer is down
+type peerDownError struct {
+	addr    string
+	retryAt time.Time
+}
+
+func (e *peerDownError) Error() string {
+	return fmt.Sprintf("net: peer %s is down, next retry at %s", e.addr, e.retryAt.Format(time.RFC3339))
+}
+
+// GRPCStatus makes the error an Unavailable gRPC status
+func (e *peerDownError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.Error())
+}
+
+// IsPeerDown returns true if the error comes from a call skipped because the
+// peer is down.
+func IsPeerDown(err error) bool {
+	_, ok := err.(*peerDownError)
+	return ok
+}
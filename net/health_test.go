@@ -0,0 +1,88 @@
+package net
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealthTracker(t *testing.T) {
+	addr := "127.0.0.1:1234"
+	var downs int
+	h := newHealthTracker(HealthConfig{MaxFailures: 2, BaseDelay: time.Second, MaxDelay: 3 * time.Second}, func(string) { downs++ })
+	now := time.Now()
+	h.now = func() time.Time { return now }
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	require.Equal(t, PeerUnknown, h.status(addr).State)
+	require.NoError(t, h.allow(addr))
+	h.record(addr, unavailable)
+	require.Equal(t, PeerDegraded, h.status(addr).State)
+
+	// errors returned by the peer itself don't count
+	h.record(addr, errors.New("invalid packet"))
+	require.Equal(t, PeerHealthy, h.status(addr).State)
+
+	h.record(addr, unavailable)
+	h.record(addr, unavailable)
+	s := h.status(addr)
+	require.Equal(t, PeerDown, s.State)
+	require.Equal(t, now.Add(time.Second), s.RetryAt)
+	require.Equal(t, 1, downs)
+	err := h.allow(addr)
+	require.True(t, IsPeerDown(err))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// a single call probes the peer once the delay elapsed, the delay doubles
+	// and is bounded if it still fails
+	for _, delay := range []time.Duration{2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		now = s.RetryAt
+		require.NoError(t, h.allow(addr))
+		require.True(t, IsPeerDown(h.allow(addr)))
+		h.record(addr, unavailable)
+		s = h.status(addr)
+		require.Equal(t, now.Add(delay), s.RetryAt)
+	}
+
+	// a successful probe closes the circuit
+	now = s.RetryAt
+	require.NoError(t, h.allow(addr))
+	h.record(addr, nil)
+	require.Equal(t, PeerHealthy, h.status(addr).State)
+	require.NoError(t, h.allow(addr))
+}
+
+func TestClientPeerHealth(t *testing.T) {
+	addr := "127.0.0.1:4007"
+	peer := &testPeer{addr, false}
+	client := NewGrpcClient().(*grpcClient)
+	client.health.conf.BaseDelay = 100 * time.Millisecond
+	client.SetTimeout(time.Second)
+
+	// nobody listens: the node goes down after the allowed failures
+	for i := 0; i < client.health.conf.MaxFailures; i++ {
+		_, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+		require.Error(t, err)
+		require.False(t, IsPeerDown(err))
+	}
+	require.Equal(t, PeerDown, client.PeerStatus(addr).State)
+	_, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+	require.True(t, IsPeerDown(err))
+
+	// the node is contacted again after the backoff and back to healthy
+	randServer := &testRandomnessServer{round: 42}
+	lis := NewTCPGrpcListener(addr, randServer)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(200 * time.Millisecond)
+
+	resp, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+	require.Equal(t, PeerHealthy, client.PeerStatus(addr).State)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/soheilhy/cmux"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 // grpcInsecureListener implements Listener using gRPC connections and regular HTTP
@@ -30,10 +32,46 @@ type grpcInsecureListener struct {
 	lis        net.Listener
 }
 
+// ServiceSet selects the services a listener serves
+type ServiceSet int
+
+const (
+	// PublicServices is the public gRPC service and its REST API, along with
+	// the gossip service reachable by the relays
+	PublicServices ServiceSet = 1 << iota
+	// ProtocolServices is the gRPC service between the nodes
+	ProtocolServices
+	// AllServices are the public and protocol services on the same listener
+	AllServices = PublicServices | ProtocolServices
+)
+
+func (s ServiceSet) has(o ServiceSet) bool {
+	return s&o != 0
+}
+
+// register registers the selected services on the server
+func (s ServiceSet) register(grpcServer *grpc.Server, service Service) {
+	if s.has(ProtocolServices) {
+		drand.RegisterProtocolServer(grpcServer, service)
+	}
+	if s.has(PublicServices) {
+		drand.RegisterPublicServer(grpcServer, service)
+		drand.RegisterGossipServer(grpcServer, service)
+	}
+}
+
 // NewTCPGrpcListener returns a gRPC listener using plain TCP connections
 // without TLS. The listener will bind to the given address:port
 // tuple.
 func NewTCPGrpcListener(addr string, s Service, opts ...grpc.ServerOption) Listener {
+	return NewTCPGrpcListenerFor(addr, s, AllServices, nil, nil, opts...)
+}
+
+// NewTCPGrpcListenerFor returns a gRPC listener using plain TCP connections
+// without TLS serving only the given services. The REST config sets the
+// headers of its REST API, DefaultRESTConfig if nil. The limiter, if not nil,
+// bounds the use of its gRPC and REST API.
+func NewTCPGrpcListenerFor(addr string, s Service, services ServiceSet, rest *RESTConfig, limiter *RateLimiter, opts ...grpc.ServerOption) Listener {
 	l, err := net.Listen("tcp", addr)
 	if err != nil {
 		panic("tcp listener: " + err.Error())
@@ -41,26 +79,27 @@ func NewTCPGrpcListener(addr string, s Service, opts ...grpc.ServerOption) Liste
 
 	mux := cmux.New(l)
 
+	if limiter != nil {
+		opts = append(opts, limiter.serverOptions(services)...)
+	}
+
 	// grpc API
-	grpcServer := grpc.NewServer(opts...)
+	grpcServer := grpc.NewServer(append(keepaliveServerOptions(), opts...)...)
 
 	// REST api
 	o := runtime.WithMarshalerOption("*", defaultJSONMarshaller)
-	gwMux := runtime.NewServeMux(o)
+	// the stream is defined after /api/public/{round}, which also matches it
+	gwMux := runtime.NewServeMux(o, runtime.WithLastMatchWins())
 	//proxyClient := newProxyClient(s)
 	proxyClient := &drandProxy{s}
 	ctx := context.TODO()
-	if err := drand.RegisterPublicHandlerClient(ctx, gwMux, proxyClient); err != nil {
-		panic(err)
+	if services.has(PublicServices) {
+		if err := drand.RegisterPublicHandlerClient(ctx, gwMux, proxyClient); err != nil {
+			panic(err)
+		}
 	}
 	restRouter := http.NewServeMux()
-	restRouter.Handle("/", gwMux)
-	//newHandler := func(w http.ResponseWriter, r *http.Request) {
-	//w.Header().Set("Access-Control-Allow-Origin", "*")
-	//gwMux.ServeHTTP(w, r)
-	//}
-
-	//restRouter.Handle("/", http.HandlerFunc(newHandler))
+	restRouter.Handle("/", restHandler(rest, limiter, gwMux))
 	restServer := &http.Server{
 		Addr:    addr,
 		Handler: grpcHandlerFunc(grpcServer, restRouter),
@@ -73,8 +112,7 @@ func NewTCPGrpcListener(addr string, s Service, opts ...grpc.ServerOption) Liste
 		mux:        mux,
 		lis:        l,
 	}
-	drand.RegisterProtocolServer(g.grpcServer, g.Service)
-	drand.RegisterPublicServer(g.grpcServer, g.Service)
+	services.register(g.grpcServer, g.Service)
 	return g
 }
 
@@ -106,68 +144,103 @@ type grpcTLSListener struct {
 
 // NewTLSGrpcListener brings...
 func NewTLSGrpcListener(bindingAddr string, certPath, keyPath string, s Service, opts ...grpc.ServerOption) (Listener, error) {
-	lis, err := net.Listen("tcp", bindingAddr)
+	certs, err := NewKeyPairReloader(certPath, keyPath)
 	if err != nil {
 		return nil, err
 	}
+	return newTLSGrpcListener(bindingAddr, certs, s, AllServices, nil, nil, nil, opts...)
+}
 
-	x509KeyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
-	if err != nil {
-		return nil, err
+// NewTLSGrpcListenerFor returns a TLS listener presenting the certificates of
+// the given source and serving only the given services. The REST config sets
+// the headers of its REST API, DefaultRESTConfig if nil. The limiter, if not
+// nil, bounds the use of its gRPC and REST API.
+func NewTLSGrpcListenerFor(bindingAddr string, certs CertSource, s Service, services ServiceSet, rest *RESTConfig, limiter *RateLimiter, opts ...grpc.ServerOption) (Listener, error) {
+	return newTLSGrpcListener(bindingAddr, certs, s, services, nil, rest, limiter, opts...)
+}
+
+// NewMutualTLSGrpcListener returns a TLS listener requiring the callers of the
+// protocol methods to present a certificate trusted by the cert manager and
+// belonging to one of the nodes returned by peers. The public methods, if
+// served, stay open to any client. The REST config sets the headers of its
+// REST API, DefaultRESTConfig if nil. The limiter, if not nil, bounds the use
+// of its gRPC and REST API.
+func NewMutualTLSGrpcListener(bindingAddr string, certs CertSource, trusted *CertManager, peers PeersFunc, s Service, services ServiceSet, rest *RESTConfig, limiter *RateLimiter, opts ...grpc.ServerOption) (Listener, error) {
+	m := &mutualTLS{pool: trusted.Pool(), peers: peers}
+	return newTLSGrpcListener(bindingAddr, certs, s, services, m, rest, limiter, opts...)
+}
+
+func newTLSGrpcListener(bindingAddr string, certs CertSource, s Service, services ServiceSet, m *mutualTLS, rest *RESTConfig, limiter *RateLimiter, opts ...grpc.ServerOption) (Listener, error) {
+	clientAuth := tls.NoClientCert
+	if m != nil {
+		// the certificates are verified by the interceptors, only for the
+		// protocol methods
+		clientAuth = tls.RequestClientCert
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(m.unaryInterceptor),
+			grpc.ChainStreamInterceptor(m.streamInterceptor))
+	}
+	if limiter != nil {
+		opts = append(opts, limiter.serverOptions(services)...)
 	}
+	tlsConfig := &tls.Config{
+		// From https://blog.cloudflare.com/exposing-go-on-the-internet/
 
-	grpcCreds, err := credentials.NewServerTLSFromFile(certPath, keyPath)
-	if err != nil {
-		return nil, err
+		// Causes servers to use Go's default ciphersuite preferences,
+		// which are tuned to avoid attacks. Does nothing on clients.
+		PreferServerCipherSuites: true,
+
+		// Only use curves which have assembly implementations
+		CurvePreferences: []tls.CurveID{
+			tls.CurveP256,
+			tls.X25519,
+		},
+
+		// Drand clients and servers are all modern software, and so we
+		// can require TLS 1.2 and the best cipher suites.
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		},
+		// End Cloudflare recommendations.
+
+		// the certificate is fetched at each handshake so it can be renewed
+		// without restarting the listener
+		GetCertificate: certs.GetCertificate,
+		NextProtos:     nextProtos(certs),
+		ClientAuth:     clientAuth,
 	}
-	serverOpts := append(opts, grpc.Creds(grpcCreds))
+	serverOpts := append(keepaliveServerOptions(), opts...)
+	serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
 	grpcServer := grpc.NewServer(serverOpts...)
-	drand.RegisterPublicServer(grpcServer, s)
-	drand.RegisterProtocolServer(grpcServer, s)
+	services.register(grpcServer, s)
 
 	o := runtime.WithMarshalerOption("*", defaultJSONMarshaller)
-	gwMux := runtime.NewServeMux(o)
-	proxy := &drandProxy{s}
-	err = drand.RegisterPublicHandlerClient(context.Background(), gwMux, proxy)
-	if err != nil {
-		return nil, err
+	// the stream is defined after /api/public/{round}, which also matches it
+	gwMux := runtime.NewServeMux(o, runtime.WithLastMatchWins())
+	if services.has(PublicServices) {
+		proxy := &drandProxy{s}
+		if err := drand.RegisterPublicHandlerClient(context.Background(), gwMux, proxy); err != nil {
+			return nil, err
+		}
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/", gwMux)
+	mux.Handle("/", restHandler(rest, limiter, gwMux))
 	server := &http.Server{
-		Handler: grpcHandlerFunc(grpcServer, mux),
-		TLSConfig: &tls.Config{
-			// From https://blog.cloudflare.com/exposing-go-on-the-internet/
-
-			// Causes servers to use Go's default ciphersuite preferences,
-			// which are tuned to avoid attacks. Does nothing on clients.
-			PreferServerCipherSuites: true,
-
-			// Only use curves which have assembly implementations
-			CurvePreferences: []tls.CurveID{
-				tls.CurveP256,
-				tls.X25519,
-			},
-
-			// Drand clients and servers are all modern software, and so we
-			// can require TLS 1.2 and the best cipher suites.
-			MinVersion: tls.VersionTLS12,
-			CipherSuites: []uint16{
-				tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
-				tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
-				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			},
-			// End Cloudflare recommendations.
-
-			Certificates: []tls.Certificate{x509KeyPair},
-			NextProtos:   []string{"h2"},
-		},
+		Handler:   grpcHandlerFunc(grpcServer, mux),
+		TLSConfig: tlsConfig,
 	}
 
+	lis, err := net.Listen("tcp", bindingAddr)
+	if err != nil {
+		return nil, err
+	}
 	tlsListener := tls.NewListener(lis, server.TLSConfig)
 	g := &grpcTLSListener{
 		Service:    s,
@@ -207,9 +280,21 @@ var _ drand.PublicClient = (*drandProxy)(nil)
 func (d *drandProxy) PublicRand(c context.Context, r *drand.PublicRandRequest, opts ...grpc.CallOption) (*drand.PublicRandResponse, error) {
 	return d.r.PublicRand(c, r)
 }
+
+// PublicRandStream runs the stream of the service, whose beacons the gateway
+// sends as the chunks of the HTTP response
 func (d *drandProxy) PublicRandStream(ctx context.Context, in *drand.PublicRandRequest, opts ...grpc.CallOption) (drand.Public_PublicRandStreamClient, error) {
-	return nil, errors.New("streaming is not supported on HTTP endpoint")
+	s := &proxyStream{
+		ctx:     ctx,
+		beacons: make(chan *drand.PublicRandResponse),
+		done:    make(chan error, 1),
+	}
+	go func() {
+		s.done <- d.r.PublicRandStream(in, s)
+	}()
+	return s, nil
 }
+
 func (d *drandProxy) PrivateRand(c context.Context, r *drand.PrivateRandRequest, opts ...grpc.CallOption) (*drand.PrivateRandResponse, error) {
 	return d.r.PrivateRand(c, r)
 }
@@ -229,8 +314,6 @@ func (d *drandProxy) Group(c context.Context, r *drand.GroupRequest, opts ...grp
 // taken from https://github.com/philips/grpc-gateway-example
 func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 		// TODO(tamird): point to merged gRPC code rather than a PR.
 		// This is a partial recreation of gRPC's internal checks https://github.com/grpc/grpc-go/pull/514/files#diff-95e9a25b738459a2d3030e1e6fa2a718R61
 		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
@@ -240,3 +323,48 @@ func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Ha
 		}
 	})
 }
+
+// proxyStream is both the server side of the stream given to the service and
+// the client side read by the gateway
+type proxyStream struct {
+	ctx     context.Context
+	beacons chan *drand.PublicRandResponse
+	// error returned by the service when the stream ends
+	done chan error
+}
+
+var _ drand.Public_PublicRandStreamServer = (*proxyStream)(nil)
+var _ drand.Public_PublicRandStreamClient = (*proxyStream)(nil)
+
+func (s *proxyStream) Send(b *drand.PublicRandResponse) error {
+	select {
+	case s.beacons <- b:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *proxyStream) Recv() (*drand.PublicRandResponse, error) {
+	select {
+	case b := <-s.beacons:
+		return b, nil
+	case err := <-s.done:
+		if err == nil {
+			err = io.EOF
+		}
+		return nil, err
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *proxyStream) Context() context.Context     { return s.ctx }
+func (s *proxyStream) SendMsg(m interface{}) error  { return errors.New("not supported") }
+func (s *proxyStream) RecvMsg(m interface{}) error  { return errors.New("not supported") }
+func (s *proxyStream) SetHeader(metadata.MD) error  { return nil }
+func (s *proxyStream) SendHeader(metadata.MD) error { return nil }
+func (s *proxyStream) SetTrailer(metadata.MD)       {}
+func (s *proxyStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *proxyStream) Trailer() metadata.MD         { return nil }
+func (s *proxyStream) CloseSend() error             { return nil }
@@ -0,0 +1,118 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// protocolMethods is the prefix of the full names of the methods of the
+// Protocol service, reserved to the nodes of the group with mutual TLS
+const protocolMethods = "/drand.Protocol/"
+
+// PeersFunc returns the nodes allowed to call the protocol methods: the nodes
+// of the current group and of the group being set up, if any.
+type PeersFunc func() []Peer
+
+// TLSKeyPeer is a peer whose identity binds the public key of its TLS
+// certificate, as the SHA-256 hash of the DER encoded SubjectPublicKeyInfo.
+type TLSKeyPeer interface {
+	Peer
+	BoundTLSKey() []byte
+}
+
+// mutualTLS authenticates the callers of the protocol methods with the
+// certificate they present during the TLS handshake. The certificate must be
+// trusted by the cert manager, valid for the host of one of the addresses of
+// an allowed node and carry the TLS key bound to the identity of this node.
+// The public methods stay open to any client.
+//
+// A certificate only names hosts, so the nodes sharing a host, or the hosts
+// covered by a wildcard certificate, could call the protocol methods in the
+// name of each other without the binding. The nodes whose identity does not
+// bind a TLS key are refused.
+type mutualTLS struct {
+	pool  *x509.CertPool
+	peers PeersFunc
+}
+
+func (m *mutualTLS) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, protocolMethods) {
+		if err := m.authorize(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (m *mutualTLS) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if strings.HasPrefix(info.FullMethod, protocolMethods) {
+		if err := m.authorize(ss.Context()); err != nil {
+			return err
+		}
+	}
+	return handler(srv, ss)
+}
+
+// authorize checks that the caller presented a trusted certificate valid for
+// the host of one of the allowed nodes and bound to its identity
+func (m *mutualTLS) authorize(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "mtls: unknown peer")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return status.Error(codes.Unauthenticated, "mtls: no client certificate")
+	}
+	certs := info.State.PeerCertificates
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         m.pool,
+		Intermediates: intermediates,
+		// nodes present the certificate they serve with
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "mtls: invalid client certificate: %s", err)
+	}
+	hash := sha256.Sum256(certs[0].RawSubjectPublicKeyInfo)
+	for _, node := range m.peers() {
+		bound, ok := node.(TLSKeyPeer)
+		if !ok || !bytes.Equal(bound.BoundTLSKey(), hash[:]) {
+			continue
+		}
+		for _, addr := range peerAddresses(node) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				continue
+			}
+			if certs[0].VerifyHostname(host) == nil {
+				return nil
+			}
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "mtls: certificate of %s does not belong to a node of the group", p.Addr)
+}
+
+// peerAddresses returns the main address of the peer followed by its
+// alternate addresses, if any
+func peerAddresses(p Peer) []string {
+	addrs := []string{p.Address()}
+	if alt, ok := p.(AlternatePeer); ok {
+		addrs = append(addrs, alt.AlternateAddresses()...)
+	}
+	return addrs
+}
@@ -26,3 +26,11 @@ type Peer interface {
 	Address() string
 	IsTLS() bool
 }
+
+// AlternatePeer is a peer reachable at other addresses besides its main one.
+// The client falls back to these addresses, in order, when the main one is
+// unreachable.
+type AlternatePeer interface {
+	Peer
+	AlternateAddresses() []string
+}
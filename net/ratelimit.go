@@ -0,0 +1,295 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitConfig bounds the use of the public API and of the gossip service
+// of a node. A zero value disables the corresponding limit.
+type RateLimitConfig struct {
+	// Rate is the number of requests per second allowed to each IP address
+	Rate float64
+	// Burst is the number of requests an IP address can make at once, Rate
+	// rounded up by default
+	Burst int
+	// GlobalRate is the number of requests per second allowed to all the
+	// clients together
+	GlobalRate float64
+	// GlobalBurst is the number of requests all the clients can make at once,
+	// GlobalRate rounded up by default
+	GlobalBurst int
+	// MaxStreams is the number of randomness streams opened at the same time
+	MaxStreams int
+	// MaxRequestSize is the size in bytes of the largest request accepted.
+	// The listeners serving only the public API refuse the larger gRPC
+	// messages before reading them; the ones also serving the nodes of the
+	// group, whose packets can be larger, refuse them once decoded.
+	MaxRequestSize int
+}
+
+// maxTrackedIPs is the number of IP addresses above which the buckets of the
+// clients that did not make requests recently are dropped
+const maxTrackedIPs = 10000
+
+// publicMethodPrefix is the prefix of the gRPC methods of the public service
+const publicMethodPrefix = "/drand.Public/"
+
+// gossipMethodPrefix is the prefix of the gRPC methods of the gossip service,
+// open to any caller like the public service
+const gossipMethodPrefix = "/drand.Gossip/"
+
+// limited returns true for the methods open to any caller, the only ones
+// limited: the nodes of the group are not.
+func limited(method string) bool {
+	return strings.HasPrefix(method, publicMethodPrefix) ||
+		strings.HasPrefix(method, gossipMethodPrefix)
+}
+
+// RateLimiter enforces a RateLimitConfig on the gRPC and REST requests made to
+// the public API and on the gossiped beacons. A single limiter shared by
+// several listeners applies the limits to all of them together.
+type RateLimiter struct {
+	sync.Mutex
+	conf    RateLimitConfig
+	global  *tokenBucket
+	ips     map[string]*tokenBucket
+	streams int
+	now     func() time.Time
+}
+
+// NewRateLimiter returns a rate limiter enforcing the given limits
+func NewRateLimiter(conf RateLimitConfig) *RateLimiter {
+	r := &RateLimiter{
+		conf: conf,
+		ips:  make(map[string]*tokenBucket),
+		now:  time.Now,
+	}
+	if conf.GlobalRate > 0 {
+		r.global = newTokenBucket(conf.GlobalRate, conf.GlobalBurst, r.now())
+	}
+	return r
+}
+
+// tokenBucket allows rate requests per second on average and burst requests
+// at once
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(burst)
+	if burst <= 0 {
+		b = float64(int(rate + 0.999999))
+	}
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// refill adds the tokens accumulated since the last request
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// allow takes a token if one is available
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// full returns true if the bucket is back to its burst, in which case
+// dropping it makes no difference
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+}
+
+// errRateLimited is returned to the clients exceeding the limits
+var errRateLimited = status.Error(codes.ResourceExhausted, "net: rate limit exceeded")
+
+// errTooManyStreams is returned when all the streams are in use
+var errTooManyStreams = status.Error(codes.ResourceExhausted, "net: too many streams")
+
+// allow returns an error if the client at the given IP address exceeds its
+// rate or the global one
+func (r *RateLimiter) allow(ip string) error {
+	r.Lock()
+	defer r.Unlock()
+	now := r.now()
+	if r.conf.Rate > 0 {
+		b, ok := r.ips[ip]
+		if !ok {
+			if len(r.ips) >= maxTrackedIPs {
+				r.sweep(now)
+			}
+			b = newTokenBucket(r.conf.Rate, r.conf.Burst, now)
+			r.ips[ip] = b
+		}
+		if !b.allow(now) {
+			return errRateLimited
+		}
+	}
+	if r.global != nil && !r.global.allow(now) {
+		return errRateLimited
+	}
+	return nil
+}
+
+// sweep drops the buckets back to their burst
+func (r *RateLimiter) sweep(now time.Time) {
+	for ip, b := range r.ips {
+		if b.full(now) {
+			delete(r.ips, ip)
+		}
+	}
+}
+
+// acquireStream reserves one of the streams
+func (r *RateLimiter) acquireStream() error {
+	r.Lock()
+	defer r.Unlock()
+	if r.conf.MaxStreams > 0 && r.streams >= r.conf.MaxStreams {
+		return errTooManyStreams
+	}
+	r.streams++
+	return nil
+}
+
+func (r *RateLimiter) releaseStream() {
+	r.Lock()
+	defer r.Unlock()
+	r.streams--
+}
+
+// serverOptions returns the options of a gRPC server applying the limits to
+// the given services
+func (r *RateLimiter) serverOptions(services ServiceSet) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(r.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(r.StreamInterceptor),
+	}
+	if r.conf.MaxRequestSize > 0 && !services.has(ProtocolServices) {
+		opts = append(opts, grpc.MaxRecvMsgSize(r.conf.MaxRequestSize))
+	}
+	return opts
+}
+
+// checkSize returns an error if the request is larger than the maximum size
+func (r *RateLimiter) checkSize(req interface{}) error {
+	if r.conf.MaxRequestSize <= 0 {
+		return nil
+	}
+	m, ok := req.(proto.Message)
+	if !ok || proto.Size(m) <= r.conf.MaxRequestSize {
+		return nil
+	}
+	return status.Errorf(codes.ResourceExhausted, "net: request larger than %d bytes", r.conf.MaxRequestSize)
+}
+
+// peerIP returns the IP address of the caller of a gRPC method
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return hostOf(p.Addr.String())
+}
+
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// UnaryInterceptor rate limits the calls to the public and gossip methods
+func (r *RateLimiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !limited(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if err := r.checkSize(req); err != nil {
+		return nil, err
+	}
+	if err := r.allow(peerIP(ctx)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rate limits the opening of the public streams and bounds
+// the number of streams running at the same time
+func (r *RateLimiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !limited(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	if err := r.allow(peerIP(ss.Context())); err != nil {
+		return err
+	}
+	if err := r.acquireStream(); err != nil {
+		return err
+	}
+	defer r.releaseStream()
+	return handler(srv, &sizedStream{ServerStream: ss, r: r})
+}
+
+// sizedStream checks the size of the requests received on a stream
+type sizedStream struct {
+	grpc.ServerStream
+	r *RateLimiter
+}
+
+func (s *sizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.r.checkSize(m)
+}
+
+// Handler wraps the given REST handler with the limits, answering 429 Too
+// Many Requests to the clients exceeding them or the maximum number of streams
+// and 413 Request Entity Too Large to the requests exceeding the maximum size
+func (r *RateLimiter) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if max := int64(r.conf.MaxRequestSize); max > 0 {
+			if req.ContentLength > max || int64(len(req.URL.RequestURI())) > max {
+				http.Error(w, fmt.Sprintf("request larger than %d bytes", max), http.StatusRequestEntityTooLarge)
+				return
+			}
+			req.Body = http.MaxBytesReader(w, req.Body, max)
+		}
+		if err := r.allow(hostOf(req.RemoteAddr)); err != nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		if strings.HasPrefix(req.URL.Path, publicStreamPath) {
+			if err := r.acquireStream(); err != nil {
+				http.Error(w, "too many streams", http.StatusTooManyRequests)
+				return
+			}
+			defer r.releaseStream()
+		}
+		h.ServeHTTP(w, req)
+	})
+}
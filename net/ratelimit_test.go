@@ -0,0 +1,134 @@
+package net
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := NewRateLimiter(RateLimitConfig{Rate: 1, Burst: 2, GlobalRate: 2})
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.global.last = now
+
+	require.NoError(t, r.allow("1.1.1.1"))
+	require.NoError(t, r.allow("1.1.1.1"))
+	err := r.allow("1.1.1.1")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the global limit applies to all the clients together
+	require.Equal(t, codes.ResourceExhausted, status.Code(r.allow("2.2.2.2")))
+
+	// the tokens come back with time
+	now = now.Add(time.Second)
+	require.NoError(t, r.allow("1.1.1.1"))
+	require.NoError(t, r.allow("2.2.2.2"))
+	require.Error(t, r.allow("1.1.1.1"))
+
+	r.conf.MaxStreams = 1
+	require.NoError(t, r.acquireStream())
+	require.Equal(t, codes.ResourceExhausted, status.Code(r.acquireStream()))
+	r.releaseStream()
+	require.NoError(t, r.acquireStream())
+}
+
+// streamServer sends a randomness and keeps its streams open
+type streamServer struct {
+	*testRandomnessServer
+}
+
+func (s *streamServer) PublicRandStream(req *drand.PublicRandRequest, stream drand.Public_PublicRandStreamServer) error {
+	if err := stream.Send(&drand.PublicRandResponse{Round: s.round}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestListenerMaxRecvSize(t *testing.T) {
+	addr := "127.0.0.1:4011"
+	peer := &testPeer{addr, false}
+	limiter := NewRateLimiter(RateLimitConfig{MaxRequestSize: 128})
+	lis := NewTCPGrpcListenerFor(addr, &testRandomnessServer{round: 42}, PublicServices, nil, limiter)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	// the public listener refuses the large messages before decoding them
+	client := NewGrpcClient()
+	_, err := client.PrivateRand(peer, &drand.PrivateRandRequest{})
+	require.NoError(t, err)
+	_, err = client.PrivateRand(peer, &drand.PrivateRandRequest{Request: &drand.ECIES{Ciphertext: make([]byte, 256)}})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Contains(t, err.Error(), "received message larger than max")
+}
+
+func TestListenerRateLimit(t *testing.T) {
+	addr := "127.0.0.1:4008"
+	peer := &testPeer{addr, false}
+	limiter := NewRateLimiter(RateLimitConfig{Rate: 1, Burst: 2, MaxStreams: 1, MaxRequestSize: 128})
+	randServer := &streamServer{&testRandomnessServer{round: 42}}
+	lis := NewTCPGrpcListenerFor(addr, randServer, AllServices, nil, limiter)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient()
+	for i := 0; i < 2; i++ {
+		_, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+		require.NoError(t, err)
+	}
+	_, err := client.PublicRand(peer, &drand.PublicRandRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// the gossip service, open to any caller, shares the limits too
+	_, err = client.Gossip(peer, &drand.GossipPacket{Round: 1})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the REST API shares the limits of the gRPC one
+	resp, err := http.Get("http://" + addr + "/api/public")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	// the requests larger than the maximum size are refused
+	limiter.Lock()
+	limiter.conf.Rate = 0
+	limiter.Unlock()
+	_, err = client.PrivateRand(peer, &drand.PrivateRandRequest{})
+	require.NoError(t, err)
+	_, err = client.PrivateRand(peer, &drand.PrivateRandRequest{Request: &drand.ECIES{Ciphertext: make([]byte, 256)}})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// a single stream runs at a time
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.PublicRandStream(ctx, peer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, (<-ch).GetRound())
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := drand.NewPublicClient(conn).PublicRandStream(context.Background(), &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	cancel()
+	require.Eventually(t, func() bool {
+		stream, err := drand.NewPublicClient(conn).PublicRandStream(context.Background(), &drand.PublicRandRequest{})
+		if err != nil {
+			return false
+		}
+		_, err = stream.Recv()
+		return err == nil
+	}, 2*time.Second, 50*time.Millisecond)
+}
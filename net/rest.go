@@ -0,0 +1,210 @@
+package net
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+// RESTConfig configures the headers of the REST API, for the browsers and the
+// caches in front of the node.
+type RESTConfig struct {
+	// AllowedOrigins are the origins allowed to call the REST API from a
+	// browser, any origin when it contains "*"
+	AllowedOrigins []string
+	// NextRound returns the time of the next round, used to cache the latest
+	// randomness until then. The latest randomness is revalidated at each
+	// request when it is nil or returns the zero time.
+	NextRound func() time.Time
+}
+
+// DefaultRESTConfig allows any origin and revalidates the latest randomness at
+// each request
+var DefaultRESTConfig = RESTConfig{AllowedOrigins: []string{"*"}}
+
+// immutableCache is the Cache-Control of the randomness of a given round,
+// which never changes
+const immutableCache = "public, max-age=31536000, immutable"
+
+// publicRandPath is the path of the REST randomness endpoints
+const publicRandPath = "/api/public"
+
+// publicStreamPath is the path of the REST randomness stream
+const publicStreamPath = publicRandPath + "/stream"
+
+// restHandler wraps the REST gateway with the CORS and the caching headers
+// of the config, DefaultRESTConfig if nil, and with the limits of the limiter,
+// if not nil
+func restHandler(rest *RESTConfig, limiter *RateLimiter, gateway http.Handler) http.Handler {
+	conf := DefaultRESTConfig
+	if rest != nil {
+		conf = *rest
+	}
+	h := cacheHandler(conf.NextRound, gateway)
+	if limiter != nil {
+		h = limiter.Handler(h)
+	}
+	return corsHandler(conf.AllowedOrigins, peerHandler(h))
+}
+
+// peerHandler records the address of the client in the context of the
+// request, as gRPC does for the calls of the services
+func peerHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		ctx := peer.NewContext(r.Context(), &peer.Peer{Addr: addr})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// corsHandler sets the CORS headers of the allowed origins and answers the
+// preflight requests
+func corsHandler(origins []string, h http.Handler) http.Handler {
+	any := false
+	allowed := make(map[string]bool)
+	for _, o := range origins {
+		any = any || o == "*"
+		allowed[o] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		switch {
+		case any:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case origin != "" && allowed[origin]:
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		default:
+			if origin != "" {
+				w.Header().Add("Vary", "Origin")
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, If-None-Match")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// cacheHandler sets the ETag and Cache-Control of the randomness responses
+// and answers 304 Not Modified to the requests whose If-None-Match matches.
+// The randomness of a given round is immutable, the latest one is cached
+// until the next round.
+func cacheHandler(nextRound func() time.Time, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		round, ok := randRound(r)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		b := &bufferedResponse{header: make(http.Header), code: http.StatusOK}
+		h.ServeHTTP(b, r)
+		for k, v := range b.header {
+			w.Header()[k] = v
+		}
+		if b.code != http.StatusOK {
+			w.WriteHeader(b.code)
+			w.Write(b.body.Bytes())
+			return
+		}
+		sum := sha256.Sum256(b.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", cacheControl(round, nextRound))
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(b.code)
+		w.Write(b.body.Bytes())
+	})
+}
+
+// randRound returns the round requested from the randomness endpoint, 0 for
+// the latest one, and false for the other requests
+func randRound(r *http.Request) (uint64, bool) {
+	if r.Method != http.MethodGet {
+		return 0, false
+	}
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	if p == publicRandPath {
+		return 0, true
+	}
+	if !strings.HasPrefix(p, publicRandPath+"/") {
+		return 0, false
+	}
+	round, err := strconv.ParseUint(strings.TrimPrefix(p, publicRandPath+"/"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return round, true
+}
+
+// cacheControl returns the Cache-Control of the randomness of the round
+func cacheControl(round uint64, nextRound func() time.Time) string {
+	if round != 0 {
+		return immutableCache
+	}
+	if nextRound == nil {
+		return "no-cache"
+	}
+	next := nextRound()
+	if next.IsZero() {
+		return "no-cache"
+	}
+	maxAge := int(time.Until(next).Seconds())
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d, must-revalidate", maxAge)
+}
+
+// etagMatches returns true if the If-None-Match header contains the etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == etag || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse keeps a response in memory until its headers are known
+type bufferedResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	b.code = code
+}
@@ -0,0 +1,91 @@
+package net
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	serve := func(origins []string, method, origin string) *http.Response {
+		r := httptest.NewRequest(method, "/api/public", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		w := httptest.NewRecorder()
+		corsHandler(origins, ok).ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	resp := serve(DefaultRESTConfig.AllowedOrigins, http.MethodGet, "https://any.test")
+	require.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	origins := []string{"https://a.test"}
+	resp = serve(origins, http.MethodGet, "https://a.test")
+	require.Equal(t, "https://a.test", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Origin", resp.Header.Get("Vary"))
+	resp = serve(origins, http.MethodGet, "https://b.test")
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	resp = serve(origins, http.MethodOptions, "https://a.test")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), http.MethodGet)
+}
+
+func TestListenerCacheHeaders(t *testing.T) {
+	addr := "127.0.0.1:4009"
+	randServer := &testRandomnessServer{round: 42}
+	next := time.Now().Add(10 * time.Second)
+	lis := NewTCPGrpcListenerFor(addr, randServer, AllServices, &RESTConfig{
+		NextRound: func() time.Time { return next },
+	}, nil)
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	get := func(path, etag string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, "http://"+addr+path, nil)
+		require.NoError(t, err)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	// a given round never changes
+	resp := get("/api/public/42", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, immutableCache, resp.Header.Get("Cache-Control"))
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp = get("/api/public/42", etag)
+	require.Equal(t, http.StatusNotModified, resp.StatusCode)
+	require.Equal(t, etag, resp.Header.Get("ETag"))
+	resp = get("/api/public/42", `"other"`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the latest one is cached until the next round
+	resp = get("/api/public", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	cache := resp.Header.Get("Cache-Control")
+	require.True(t, strings.HasPrefix(cache, "public, max-age="), cache)
+	require.NotEqual(t, "public, max-age=0, must-revalidate", cache)
+	require.Equal(t, etag, resp.Header.Get("ETag"))
+
+	// without the time of the next round, it is revalidated at each request
+	require.Equal(t, "no-cache", cacheControl(0, nil))
+	require.Equal(t, "no-cache", cacheControl(0, func() time.Time { return time.Time{} }))
+}
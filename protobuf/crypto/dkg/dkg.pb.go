@@ -21,11 +21,13 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-// Packet is a wrapper around the three different types of DKG messages
+// Packet is a wrapper around the different types of DKG messages
 type Packet struct {
 	Deal                 *Deal          `protobuf:"bytes,1,opt,name=deal,proto3" json:"deal,omitempty"`
 	Response             *Response      `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
 	Justification        *Justification `protobuf:"bytes,3,opt,name=justification,proto3" json:"justification,omitempty"`
+	Echo                 *Echo          `protobuf:"bytes,4,opt,name=echo,proto3" json:"echo,omitempty"`
+	Reveal               *Reveal        `protobuf:"bytes,5,opt,name=reveal,proto3" json:"reveal,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -77,6 +79,20 @@ func (m *Packet) GetJustification() *Justification {
 	return nil
 }
 
+func (m *Packet) GetEcho() *Echo {
+	if m != nil {
+		return m.Echo
+	}
+	return nil
+}
+
+func (m *Packet) GetReveal() *Reveal {
+	if m != nil {
+		return m.Reveal
+	}
+	return nil
+}
+
 // Deal contains a share for a participant.
 type Deal struct {
 	// index of the dealer, the issuer of the share
@@ -89,10 +105,12 @@ type Deal struct {
 	// issue this deal, so another one is required. Best would be to merge vss
 	// and dkg so we could use only one field of signature. For future work...
 	// :)
-	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// commitment of the dealer to its user entropy, if any
+	Entropy              *EntropyCommitment `protobuf:"bytes,4,opt,name=entropy,proto3" json:"entropy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *Deal) Reset()         { *m = Deal{} }
@@ -141,6 +159,116 @@ func (m *Deal) GetSignature() []byte {
 	return nil
 }
 
+func (m *Deal) GetEntropy() *EntropyCommitment {
+	if m != nil {
+		return m.Entropy
+	}
+	return nil
+}
+
+// EntropyCommitment is the commitment of a dealer to the seed of the user
+// entropy it mixes with its local randomness to create its secret during a
+// fresh DKG.
+type EntropyCommitment struct {
+	// hash commitment of the seed
+	Commitment []byte `protobuf:"bytes,1,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	// schnorr signature of the dealer over its index and the commitment
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EntropyCommitment) Reset()         { *m = EntropyCommitment{} }
+func (m *EntropyCommitment) String() string { return proto.CompactTextString(m) }
+func (*EntropyCommitment) ProtoMessage()    {}
+func (*EntropyCommitment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2cd2862d3a18e91b, []int{2}
+}
+
+func (m *EntropyCommitment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntropyCommitment.Unmarshal(m, b)
+}
+func (m *EntropyCommitment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntropyCommitment.Marshal(b, m, deterministic)
+}
+func (m *EntropyCommitment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntropyCommitment.Merge(m, src)
+}
+func (m *EntropyCommitment) XXX_Size() int {
+	return xxx_messageInfo_EntropyCommitment.Size(m)
+}
+func (m *EntropyCommitment) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntropyCommitment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntropyCommitment proto.InternalMessageInfo
+
+func (m *EntropyCommitment) GetCommitment() []byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *EntropyCommitment) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// Reveal is sent by a dealer that has finished the DKG to reveal the seed of
+// its user entropy.
+type Reveal struct {
+	// index of the dealer
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// seed of the user entropy
+	Seed                 []byte   `protobuf:"bytes,2,opt,name=seed,proto3" json:"seed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Reveal) Reset()         { *m = Reveal{} }
+func (m *Reveal) String() string { return proto.CompactTextString(m) }
+func (*Reveal) ProtoMessage()    {}
+func (*Reveal) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2cd2862d3a18e91b, []int{3}
+}
+
+func (m *Reveal) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Reveal.Unmarshal(m, b)
+}
+func (m *Reveal) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Reveal.Marshal(b, m, deterministic)
+}
+func (m *Reveal) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reveal.Merge(m, src)
+}
+func (m *Reveal) XXX_Size() int {
+	return xxx_messageInfo_Reveal.Size(m)
+}
+func (m *Reveal) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reveal.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Reveal proto.InternalMessageInfo
+
+func (m *Reveal) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Reveal) GetSeed() []byte {
+	if m != nil {
+		return m.Seed
+	}
+	return nil
+}
+
 // Response holds the response that a participant broadcast after having
 // received a deal.
 type Response struct {
@@ -157,7 +285,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2cd2862d3a18e91b, []int{2}
+	return fileDescriptor_2cd2862d3a18e91b, []int{4}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -208,7 +336,7 @@ func (m *Justification) Reset()         { *m = Justification{} }
 func (m *Justification) String() string { return proto.CompactTextString(m) }
 func (*Justification) ProtoMessage()    {}
 func (*Justification) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2cd2862d3a18e91b, []int{3}
+	return fileDescriptor_2cd2862d3a18e91b, []int{5}
 }
 
 func (m *Justification) XXX_Unmarshal(b []byte) error {
@@ -243,11 +371,76 @@ func (m *Justification) GetJustification() *vss.Justification {
 	return nil
 }
 
+// Echo is sent by a participant of the new group to every other participants
+// when the echo broadcast is enabled. It contains a response or a
+// justification received by the participant, so other nodes can check they
+// all received the same one before processing it.
+type Echo struct {
+	// index of the echoing participant in the new group
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// packet echoed - only responses and justifications are echoed
+	Packet *Packet `protobuf:"bytes,2,opt,name=packet,proto3" json:"packet,omitempty"`
+	// schnorr signature of the echoing participant over the hash of the packet
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Echo) Reset()         { *m = Echo{} }
+func (m *Echo) String() string { return proto.CompactTextString(m) }
+func (*Echo) ProtoMessage()    {}
+func (*Echo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2cd2862d3a18e91b, []int{6}
+}
+
+func (m *Echo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Echo.Unmarshal(m, b)
+}
+func (m *Echo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Echo.Marshal(b, m, deterministic)
+}
+func (m *Echo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Echo.Merge(m, src)
+}
+func (m *Echo) XXX_Size() int {
+	return xxx_messageInfo_Echo.Size(m)
+}
+func (m *Echo) XXX_DiscardUnknown() {
+	xxx_messageInfo_Echo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Echo proto.InternalMessageInfo
+
+func (m *Echo) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Echo) GetPacket() *Packet {
+	if m != nil {
+		return m.Packet
+	}
+	return nil
+}
+
+func (m *Echo) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Packet)(nil), "dkg.Packet")
 	proto.RegisterType((*Deal)(nil), "dkg.Deal")
+	proto.RegisterType((*EntropyCommitment)(nil), "dkg.EntropyCommitment")
+	proto.RegisterType((*Reveal)(nil), "dkg.Reveal")
 	proto.RegisterType((*Response)(nil), "dkg.Response")
 	proto.RegisterType((*Justification)(nil), "dkg.Justification")
+	proto.RegisterType((*Echo)(nil), "dkg.Echo")
 }
 
 func init() {
@@ -255,21 +448,29 @@ func init() {
 }
 
 var fileDescriptor_2cd2862d3a18e91b = []byte{
-	// 250 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x49, 0x2e, 0xaa, 0x2c,
-	0x28, 0xc9, 0xd7, 0x4f, 0xc9, 0x4e, 0x07, 0x61, 0xbd, 0x82, 0xa2, 0xfc, 0x92, 0x7c, 0x21, 0xe6,
-	0x94, 0xec, 0x74, 0x29, 0x98, 0x54, 0x59, 0x71, 0x31, 0x08, 0x43, 0xa4, 0x94, 0x7a, 0x18, 0xb9,
-	0xd8, 0x02, 0x12, 0x93, 0xb3, 0x53, 0x4b, 0x84, 0x64, 0xb9, 0x58, 0x52, 0x52, 0x13, 0x73, 0x24,
-	0x18, 0x15, 0x18, 0x35, 0xb8, 0x8d, 0x38, 0xf5, 0x40, 0xfa, 0x5d, 0x52, 0x13, 0x73, 0x82, 0xc0,
-	0xc2, 0x42, 0x9a, 0x5c, 0x1c, 0x45, 0xa9, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x12, 0x4c, 0x60,
-	0x25, 0xbc, 0x60, 0x25, 0x41, 0x50, 0xc1, 0x20, 0xb8, 0xb4, 0x90, 0x05, 0x17, 0x6f, 0x56, 0x69,
-	0x71, 0x49, 0x66, 0x5a, 0x66, 0x72, 0x62, 0x49, 0x66, 0x7e, 0x9e, 0x04, 0x33, 0x58, 0xbd, 0x10,
-	0x58, 0xbd, 0x17, 0xb2, 0x4c, 0x10, 0xaa, 0x42, 0xa5, 0x24, 0x2e, 0x16, 0x90, 0x95, 0x42, 0x22,
-	0x5c, 0xac, 0x99, 0x79, 0x29, 0xa9, 0x15, 0x60, 0xc7, 0xf0, 0x06, 0x41, 0x38, 0x42, 0x6a, 0x50,
-	0x17, 0x32, 0x41, 0x8d, 0x03, 0x79, 0xc3, 0x35, 0x0f, 0xec, 0xaf, 0xd4, 0x14, 0x24, 0xa7, 0xca,
-	0x70, 0x71, 0x16, 0x67, 0xa6, 0xe7, 0x25, 0x96, 0x94, 0x16, 0xa5, 0x82, 0xed, 0xe6, 0x09, 0x42,
-	0x08, 0x28, 0x79, 0x73, 0x71, 0xc0, 0xdc, 0x8c, 0xc3, 0x1e, 0x6c, 0x5e, 0x05, 0xd9, 0x85, 0xe9,
-	0x55, 0xa5, 0x78, 0x2e, 0x5e, 0x14, 0x0f, 0xe1, 0x30, 0x11, 0x23, 0x44, 0x90, 0xbd, 0x80, 0x2f,
-	0x44, 0x9c, 0x58, 0xa3, 0x40, 0xb1, 0x97, 0xc4, 0x06, 0x8e, 0x2e, 0x63, 0x40, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x01, 0x82, 0x40, 0x8e, 0xe1, 0x01, 0x00, 0x00,
+	// 375 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0xc1, 0x4e, 0xc2, 0x40,
+	0x10, 0x4d, 0x4b, 0xa9, 0x30, 0xd0, 0x44, 0x37, 0xc4, 0x34, 0x46, 0x8d, 0x29, 0x89, 0xd1, 0x0b,
+	0x1a, 0xbc, 0x78, 0x56, 0xb9, 0xe0, 0xc5, 0xec, 0xc5, 0xc4, 0x0b, 0xa9, 0xed, 0x08, 0x15, 0xe8,
+	0x36, 0xdd, 0x85, 0xc8, 0x7f, 0xf8, 0x61, 0x7e, 0x92, 0xd9, 0xe9, 0x82, 0x25, 0x58, 0x0e, 0x9b,
+	0xec, 0xbe, 0xf7, 0x76, 0xf3, 0x66, 0xe6, 0x2d, 0x74, 0xa2, 0x7c, 0x95, 0x29, 0x71, 0x13, 0x4f,
+	0xc7, 0x7a, 0xf5, 0xb2, 0x5c, 0x28, 0xc1, 0x6a, 0xf1, 0x74, 0x7c, 0xb2, 0xa6, 0x96, 0x52, 0xea,
+	0x55, 0x50, 0xc1, 0x8f, 0x05, 0xee, 0x4b, 0x18, 0x4d, 0x51, 0xb1, 0x33, 0x70, 0x62, 0x0c, 0x67,
+	0xbe, 0x75, 0x61, 0x5d, 0xb5, 0xfa, 0xcd, 0x9e, 0xbe, 0xff, 0x84, 0xe1, 0x8c, 0x13, 0xcc, 0xae,
+	0xa1, 0x91, 0xa3, 0xcc, 0x44, 0x2a, 0xd1, 0xb7, 0x49, 0xe2, 0x91, 0x84, 0x1b, 0x90, 0x6f, 0x68,
+	0x76, 0x0f, 0xde, 0xe7, 0x42, 0xaa, 0xe4, 0x23, 0x89, 0x42, 0x95, 0x88, 0xd4, 0xaf, 0x91, 0x9e,
+	0x91, 0x7e, 0x58, 0x66, 0xf8, 0xb6, 0x50, 0x7b, 0xc0, 0x68, 0x22, 0x7c, 0xa7, 0xe4, 0x61, 0x10,
+	0x4d, 0x04, 0x27, 0x98, 0x75, 0xc1, 0xcd, 0x71, 0xa9, 0x4d, 0xd6, 0x49, 0xd0, 0x32, 0x0e, 0x34,
+	0xc4, 0x0d, 0x15, 0x7c, 0x5b, 0xe0, 0x68, 0xdf, 0xac, 0x03, 0xf5, 0x24, 0x8d, 0xf1, 0x8b, 0x2a,
+	0xf2, 0x78, 0x71, 0x60, 0x97, 0xa6, 0x4c, 0xdb, 0x78, 0xd2, 0xbd, 0x18, 0xa4, 0xd4, 0x1c, 0x8c,
+	0x4b, 0xf5, 0x9e, 0x42, 0x53, 0x26, 0xe3, 0x34, 0x54, 0x8b, 0x1c, 0xa9, 0x80, 0x36, 0xff, 0x03,
+	0xd8, 0x2d, 0x1c, 0x60, 0xaa, 0x72, 0x91, 0xad, 0x8c, 0xd7, 0xe3, 0xc2, 0x6b, 0x81, 0x3d, 0x8a,
+	0xf9, 0x3c, 0x51, 0x73, 0x4c, 0x15, 0x5f, 0xcb, 0x82, 0x57, 0x38, 0xda, 0x61, 0xd9, 0x39, 0x40,
+	0xb4, 0x39, 0x91, 0xcf, 0x36, 0x2f, 0x21, 0xfb, 0x4d, 0x0c, 0x9d, 0x86, 0x7d, 0x58, 0x0b, 0xfa,
+	0xe0, 0x16, 0x1d, 0xa8, 0x28, 0x98, 0x81, 0x23, 0x11, 0x63, 0x2a, 0xb8, 0xcd, 0x69, 0x1f, 0x3c,
+	0x43, 0x63, 0x3d, 0xb7, 0x8a, 0x5b, 0xff, 0x8d, 0x5b, 0xb7, 0x6a, 0x77, 0xdc, 0xc1, 0x08, 0xbc,
+	0xad, 0xa1, 0x56, 0xbc, 0xb8, 0x93, 0x8a, 0xf2, 0x04, 0xf6, 0xa5, 0x22, 0x18, 0x81, 0xa3, 0x43,
+	0x50, 0xf1, 0x6e, 0x17, 0xdc, 0x8c, 0x12, 0xec, 0xdb, 0xa5, 0x50, 0x14, 0xa1, 0xe6, 0x86, 0xda,
+	0xdf, 0xc8, 0x87, 0xfa, 0x9b, 0xfe, 0x22, 0xef, 0x2e, 0xfd, 0x89, 0xbb, 0xdf, 0x01, 0x00, 0x24,
+	0x55, 0xda, 0x5d, 0x46, 0x03, 0x00, 0x00,
 }
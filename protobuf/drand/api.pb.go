@@ -317,7 +317,9 @@ func (m *DistKeyRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_DistKeyRequest proto.InternalMessageInfo
 
 type DistKeyResponse struct {
-	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Key []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// name of the scheme of the key
+	Scheme               string   `protobuf:"bytes,3,opt,name=scheme,proto3" json:"scheme,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -355,6 +357,13 @@ func (m *DistKeyResponse) GetKey() []byte {
 	return nil
 }
 
+func (m *DistKeyResponse) GetScheme() string {
+	if m != nil {
+		return m.Scheme
+	}
+	return ""
+}
+
 type HomeRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -461,9 +470,17 @@ var xxx_messageInfo_GroupRequest proto.InternalMessageInfo
 type GroupResponse struct {
 	Threshold uint32 `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
 	// in ms
-	Period               uint32   `protobuf:"varint,3,opt,name=period,proto3" json:"period,omitempty"`
-	Nodes                []*Node  `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
-	Distkey              []string `protobuf:"bytes,5,rep,name=distkey,proto3" json:"distkey,omitempty"`
+	Period  uint32   `protobuf:"varint,3,opt,name=period,proto3" json:"period,omitempty"`
+	Nodes   []*Node  `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Distkey []string `protobuf:"bytes,5,rep,name=distkey,proto3" json:"distkey,omitempty"`
+	// name of the scheme of the chain: pairing curve and group of the
+	// signatures
+	Scheme string `protobuf:"bytes,6,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	// true if the beacons only sign their round, without the previous
+	// signature
+	Unchained bool `protobuf:"varint,7,opt,name=unchained,proto3" json:"unchained,omitempty"`
+	// name of the hash function deriving the randomness from the signatures
+	RandomnessHash       string   `protobuf:"bytes,8,opt,name=randomness_hash,json=randomnessHash,proto3" json:"randomness_hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -522,14 +539,38 @@ func (m *GroupResponse) GetDistkey() []string {
 	return nil
 }
 
+func (m *GroupResponse) GetScheme() string {
+	if m != nil {
+		return m.Scheme
+	}
+	return ""
+}
+
+func (m *GroupResponse) GetUnchained() bool {
+	if m != nil {
+		return m.Unchained
+	}
+	return false
+}
+
+func (m *GroupResponse) GetRandomnessHash() string {
+	if m != nil {
+		return m.RandomnessHash
+	}
+	return ""
+}
+
 // Node represents the information about a drand's node
 type Node struct {
-	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	TLS                  bool     `protobuf:"varint,3,opt,name=TLS,proto3" json:"TLS,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Key     string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	TLS     bool   `protobuf:"varint,3,opt,name=TLS,proto3" json:"TLS,omitempty"`
+	// other addresses the node is reachable at
+	Addresses            []string      `protobuf:"bytes,4,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Metadata             *NodeMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Node) Reset()         { *m = Node{} }
@@ -578,6 +619,76 @@ func (m *Node) GetTLS() bool {
 	return false
 }
 
+func (m *Node) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *Node) GetMetadata() *NodeMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// NodeMetadata contains optional information about the operator of a node
+type NodeMetadata struct {
+	Operator             string   `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Contact              string   `protobuf:"bytes,2,opt,name=contact,proto3" json:"contact,omitempty"`
+	Region               string   `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeMetadata) Reset()         { *m = NodeMetadata{} }
+func (m *NodeMetadata) String() string { return proto.CompactTextString(m) }
+func (*NodeMetadata) ProtoMessage()    {}
+func (*NodeMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0cff3fc81cf7d79, []int{12}
+}
+
+func (m *NodeMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeMetadata.Unmarshal(m, b)
+}
+func (m *NodeMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeMetadata.Marshal(b, m, deterministic)
+}
+func (m *NodeMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeMetadata.Merge(m, src)
+}
+func (m *NodeMetadata) XXX_Size() int {
+	return xxx_messageInfo_NodeMetadata.Size(m)
+}
+func (m *NodeMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeMetadata proto.InternalMessageInfo
+
+func (m *NodeMetadata) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *NodeMetadata) GetContact() string {
+	if m != nil {
+		return m.Contact
+	}
+	return ""
+}
+
+func (m *NodeMetadata) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*PublicRandRequest)(nil), "drand.PublicRandRequest")
 	proto.RegisterType((*PublicRandResponse)(nil), "drand.PublicRandResponse")
@@ -591,6 +702,7 @@ func init() {
 	proto.RegisterType((*GroupRequest)(nil), "drand.GroupRequest")
 	proto.RegisterType((*GroupResponse)(nil), "drand.GroupResponse")
 	proto.RegisterType((*Node)(nil), "drand.Node")
+	proto.RegisterType((*NodeMetadata)(nil), "drand.NodeMetadata")
 }
 
 func init() {
@@ -598,49 +710,56 @@ func init() {
 }
 
 var fileDescriptor_c0cff3fc81cf7d79 = []byte{
-	// 663 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdd, 0x6e, 0xd3, 0x4a,
-	0x10, 0x96, 0x9b, 0xbf, 0x66, 0xe2, 0xb4, 0xcd, 0xa4, 0xed, 0x49, 0xad, 0xe8, 0x28, 0xc7, 0x07,
-	0xaa, 0x50, 0x89, 0x06, 0x85, 0x1b, 0x84, 0x40, 0x48, 0xd0, 0x0a, 0x50, 0x11, 0xaa, 0x36, 0x5c,
-	0x15, 0x21, 0xe4, 0xd6, 0x4b, 0x62, 0x91, 0x78, 0xcd, 0xee, 0xa6, 0xa2, 0x42, 0xdc, 0x94, 0x47,
-	0xe0, 0x79, 0xe0, 0x25, 0x78, 0x05, 0x1e, 0x04, 0xed, 0x78, 0x1d, 0x3b, 0x6d, 0xaf, 0xb8, 0xdb,
-	0xf9, 0x66, 0xbe, 0x6f, 0x7e, 0x3c, 0x63, 0x58, 0x0f, 0x65, 0x10, 0x87, 0x83, 0x20, 0x89, 0xf6,
-	0x13, 0x29, 0xb4, 0xc0, 0x0a, 0x01, 0x5e, 0x77, 0x2c, 0xc4, 0x78, 0xca, 0x8d, 0x63, 0x10, 0xc4,
-	0xb1, 0xd0, 0x81, 0x8e, 0x44, 0xac, 0xd2, 0x20, 0xff, 0x0e, 0xb4, 0x8e, 0xe7, 0xa7, 0xd3, 0xe8,
-	0x8c, 0x05, 0x71, 0xc8, 0xf8, 0xa7, 0x39, 0x57, 0x1a, 0x37, 0xa1, 0x22, 0xc5, 0x3c, 0x0e, 0x3b,
-	0x4e, 0xcf, 0xe9, 0x97, 0x59, 0x6a, 0xf8, 0x3f, 0x1d, 0xc0, 0x62, 0xac, 0x4a, 0x44, 0xac, 0xf8,
-	0xcd, 0xc1, 0xd8, 0x85, 0xba, 0x8a, 0xc6, 0x71, 0xa0, 0xe7, 0x92, 0x77, 0x56, 0x7a, 0x4e, 0xdf,
-	0x65, 0x39, 0x80, 0xb7, 0x61, 0x2d, 0x91, 0xfc, 0x3c, 0x12, 0x73, 0xf5, 0x3e, 0x25, 0x97, 0x88,
-	0xdc, 0xcc, 0x50, 0x46, 0x22, 0x77, 0x01, 0x17, 0x61, 0xb9, 0x5a, 0x99, 0xd4, 0x5a, 0x99, 0x67,
-	0xb4, 0x50, 0xfd, 0x17, 0xc0, 0x74, 0x2c, 0x66, 0x31, 0x57, 0xaa, 0x53, 0xa1, 0xb0, 0x02, 0xe2,
-	0x3f, 0x02, 0x3c, 0x96, 0xd1, 0x79, 0xa0, 0x79, 0xb1, 0xd9, 0x5d, 0xa8, 0xc9, 0xf4, 0x49, 0x75,
-	0x36, 0x86, 0xee, 0x3e, 0x0d, 0x6e, 0xff, 0xf0, 0xd9, 0xcb, 0xc3, 0x11, 0xcb, 0x9c, 0xfe, 0x13,
-	0x68, 0x2f, 0xb1, 0x6d, 0xfb, 0x7d, 0x58, 0x95, 0xf6, 0x4d, 0x13, 0xb8, 0xca, 0x5f, 0x78, 0xfd,
-	0xb7, 0x50, 0x21, 0xc8, 0xcc, 0x86, 0x27, 0x13, 0x3e, 0xe3, 0x32, 0x98, 0x12, 0xc7, 0x65, 0x39,
-	0x60, 0xba, 0x38, 0x8b, 0x92, 0x09, 0x97, 0x9a, 0x7f, 0xd6, 0x76, 0x74, 0x05, 0xc4, 0xcc, 0x3b,
-	0x16, 0xf1, 0x19, 0xa7, 0x91, 0xb9, 0x2c, 0x35, 0xfc, 0x0d, 0x58, 0x3b, 0x88, 0x94, 0x3e, 0xe2,
-	0x17, 0xb6, 0x2f, 0xff, 0x7f, 0x58, 0x5f, 0x20, 0xb6, 0xd6, 0x0d, 0x28, 0x7d, 0xe4, 0x17, 0x56,
-	0xd3, 0x3c, 0xfd, 0x26, 0x34, 0x5e, 0x88, 0x19, 0xcf, 0x38, 0xbb, 0xe0, 0xa6, 0xa6, 0x25, 0x6c,
-	0x43, 0x55, 0xe9, 0x40, 0xcf, 0x15, 0x95, 0x59, 0x67, 0xd6, 0xf2, 0xd7, 0xc0, 0x7d, 0x2e, 0xc5,
-	0x3c, 0xc9, 0x78, 0x97, 0x0e, 0x34, 0x2d, 0x60, 0x99, 0x5d, 0xa8, 0xeb, 0x89, 0xe4, 0x6a, 0x22,
-	0xa6, 0x21, 0x25, 0x6c, 0xb2, 0x1c, 0x30, 0xba, 0x09, 0x97, 0x91, 0x48, 0xbf, 0x7b, 0x93, 0x59,
-	0x0b, 0xff, 0x33, 0xbd, 0x85, 0x5c, 0x75, 0xca, 0xbd, 0x52, 0xbf, 0x31, 0x6c, 0xd8, 0x49, 0xbe,
-	0x16, 0x21, 0x67, 0xa9, 0x07, 0x3b, 0x50, 0x0b, 0x23, 0xa5, 0x4d, 0x1f, 0x95, 0x5e, 0xa9, 0x5f,
-	0x67, 0x99, 0xe9, 0x1f, 0x40, 0xd9, 0x04, 0x9a, 0x88, 0x20, 0x0c, 0xa5, 0xd9, 0x81, 0xb4, 0xea,
-	0xcc, 0x2c, 0xf6, 0x5f, 0xa7, 0xfe, 0x0d, 0xf2, 0xe6, 0xd5, 0x88, 0xaa, 0x58, 0x65, 0xe6, 0x39,
-	0xfc, 0x51, 0x86, 0x6a, 0xba, 0xe5, 0x38, 0x03, 0xc8, 0xf7, 0x1d, 0x3b, 0xb6, 0x98, 0x6b, 0xe7,
-	0xe2, 0xed, 0xdc, 0xe0, 0xb1, 0xdf, 0x7c, 0xef, 0xf2, 0xd7, 0xef, 0xef, 0x2b, 0xb7, 0xb0, 0x41,
-	0xe7, 0x97, 0x50, 0xc0, 0xc9, 0x16, 0xb6, 0x0b, 0xe6, 0xe0, 0x0b, 0x5d, 0xc0, 0x57, 0xfc, 0xe6,
-	0xc0, 0x46, 0x2e, 0x31, 0xd2, 0x92, 0x07, 0xb3, 0xbf, 0xcb, 0xfa, 0x80, 0xb2, 0x0e, 0x11, 0x8b,
-	0x69, 0x14, 0x09, 0x9e, 0x74, 0xd1, 0xbb, 0x8e, 0x66, 0x35, 0xdc, 0x73, 0xf0, 0x1d, 0x34, 0x0a,
-	0x6b, 0x8e, 0x8b, 0x2c, 0xd7, 0x0e, 0xc7, 0xf3, 0x6e, 0x72, 0xd9, 0x0a, 0xfe, 0xa1, 0x0a, 0x5a,
-	0xbe, 0x9b, 0xe6, 0x4a, 0x23, 0x1e, 0x3a, 0x7b, 0x78, 0x04, 0x15, 0x5a, 0x14, 0x6c, 0x5b, 0x76,
-	0x71, 0x8f, 0xbc, 0xcd, 0x65, 0x70, 0x59, 0x0c, 0xd7, 0x49, 0x2c, 0x8a, 0x3f, 0x88, 0xc1, 0x98,
-	0x34, 0x46, 0x50, 0xb3, 0x2b, 0x8e, 0x5b, 0x96, 0xb9, 0x7c, 0x04, 0xde, 0xf6, 0x55, 0xd8, 0x4a,
-	0xee, 0x90, 0x64, 0x1b, 0x5b, 0xb9, 0xa4, 0x5d, 0x23, 0x7c, 0x0c, 0x65, 0x73, 0x03, 0x88, 0x96,
-	0x5a, 0xb8, 0x0f, 0xaf, 0xbd, 0x84, 0x59, 0x2d, 0x97, 0xb4, 0xaa, 0x58, 0x36, 0x5a, 0x4f, 0x6b,
-	0x27, 0xe9, 0x7f, 0xf7, 0xb4, 0x4a, 0x3f, 0xd8, 0xfb, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0xe4,
-	0xd3, 0xc9, 0x67, 0x98, 0x05, 0x00, 0x00,
+	// 781 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdd, 0x6e, 0xdb, 0x36,
+	0x14, 0x86, 0xe2, 0xff, 0x63, 0xf9, 0x8f, 0x4e, 0x32, 0x45, 0x30, 0x06, 0x4f, 0xd8, 0x32, 0x2f,
+	0xc0, 0xe2, 0xc1, 0xbb, 0x19, 0xf6, 0x83, 0x01, 0xdb, 0x82, 0x65, 0xc8, 0x36, 0x04, 0xf4, 0xae,
+	0xb2, 0x0d, 0x01, 0x63, 0xb1, 0x96, 0x50, 0x9b, 0x54, 0x49, 0x2a, 0x68, 0x50, 0xf4, 0xa6, 0x7d,
+	0x84, 0x3c, 0x4f, 0xfb, 0x12, 0x7d, 0x82, 0x02, 0x7d, 0x90, 0x82, 0x14, 0x65, 0xc9, 0x8d, 0xaf,
+	0x7a, 0xc7, 0xf3, 0x9d, 0x73, 0xbe, 0xf3, 0x9d, 0x23, 0xf2, 0x08, 0x7a, 0xa1, 0x20, 0x2c, 0x9c,
+	0x92, 0x24, 0x3e, 0x4d, 0x04, 0x57, 0x1c, 0xd5, 0x0c, 0xe0, 0x8f, 0x96, 0x9c, 0x2f, 0x57, 0x54,
+	0x3b, 0xa6, 0x84, 0x31, 0xae, 0x88, 0x8a, 0x39, 0x93, 0x59, 0x50, 0xf0, 0x15, 0x0c, 0x2e, 0xd3,
+	0x9b, 0x55, 0xbc, 0xc0, 0x84, 0x85, 0x98, 0x3e, 0x49, 0xa9, 0x54, 0x68, 0x1f, 0x6a, 0x82, 0xa7,
+	0x2c, 0xf4, 0x9c, 0xb1, 0x33, 0xa9, 0xe2, 0xcc, 0x08, 0x5e, 0x3b, 0x80, 0xca, 0xb1, 0x32, 0xe1,
+	0x4c, 0xd2, 0xdd, 0xc1, 0x68, 0x04, 0x2d, 0x19, 0x2f, 0x19, 0x51, 0xa9, 0xa0, 0xde, 0xde, 0xd8,
+	0x99, 0xb8, 0xb8, 0x00, 0xd0, 0x17, 0xd0, 0x4d, 0x04, 0xbd, 0x8d, 0x79, 0x2a, 0xaf, 0xb3, 0xe4,
+	0x8a, 0x49, 0xee, 0xe4, 0x28, 0x36, 0x24, 0x5f, 0x03, 0xda, 0x84, 0x15, 0x6c, 0x55, 0xc3, 0x36,
+	0xc8, 0x3d, 0xf3, 0x0d, 0xeb, 0xa7, 0x00, 0xba, 0x63, 0xbe, 0x66, 0x54, 0x4a, 0xaf, 0x66, 0xc2,
+	0x4a, 0x48, 0xf0, 0x23, 0xa0, 0x4b, 0x11, 0xdf, 0x12, 0x45, 0xcb, 0xcd, 0x1e, 0x43, 0x43, 0x64,
+	0x47, 0xa3, 0xb3, 0x3d, 0x73, 0x4f, 0xcd, 0xe0, 0x4e, 0xcf, 0x7e, 0xfd, 0xe3, 0x6c, 0x8e, 0x73,
+	0x67, 0xf0, 0x33, 0x0c, 0xb7, 0xb2, 0x6d, 0xfb, 0x13, 0x68, 0x0a, 0x7b, 0xf6, 0x9c, 0x1d, 0xf9,
+	0x1b, 0x6f, 0xf0, 0x2f, 0xd4, 0x0c, 0xa4, 0x67, 0x43, 0x93, 0x88, 0xae, 0xa9, 0x20, 0x2b, 0x93,
+	0xe3, 0xe2, 0x02, 0xd0, 0x5d, 0x2c, 0xe2, 0x24, 0xa2, 0x42, 0xd1, 0xa7, 0xca, 0x8e, 0xae, 0x84,
+	0xe8, 0x79, 0x33, 0xce, 0x16, 0xd4, 0x8c, 0xcc, 0xc5, 0x99, 0x11, 0xf4, 0xa1, 0xfb, 0x5b, 0x2c,
+	0xd5, 0x05, 0xbd, 0xb3, 0x7d, 0x05, 0x3f, 0x40, 0x6f, 0x83, 0x58, 0xad, 0x7d, 0xa8, 0x3c, 0xa6,
+	0x77, 0x96, 0x53, 0x1f, 0xd1, 0x21, 0xd4, 0xe5, 0x42, 0x57, 0x36, 0x6c, 0x2d, 0x6c, 0xad, 0xa0,
+	0x03, 0xed, 0x73, 0xbe, 0xa6, 0x39, 0xd7, 0x31, 0xb8, 0x99, 0x69, 0x89, 0x74, 0x9a, 0x22, 0x2a,
+	0x95, 0x9e, 0x63, 0xd3, 0x8c, 0x15, 0x74, 0xc1, 0xfd, 0x5d, 0xf0, 0x34, 0xc9, 0xf3, 0xde, 0x3a,
+	0xd0, 0xb1, 0x80, 0xcd, 0x1c, 0x41, 0x4b, 0x45, 0x82, 0xca, 0x88, 0xaf, 0x42, 0x23, 0xa4, 0x83,
+	0x0b, 0x40, 0xf3, 0x26, 0x54, 0xc4, 0x3c, 0xbb, 0x0f, 0x1d, 0x6c, 0x2d, 0xf4, 0x99, 0xee, 0x39,
+	0xa4, 0xd2, 0xab, 0x8e, 0x2b, 0x93, 0xf6, 0xac, 0x6d, 0x27, 0xfc, 0x37, 0x0f, 0x29, 0xce, 0x3c,
+	0xc8, 0x83, 0x46, 0x18, 0x4b, 0xa5, 0xfb, 0xab, 0x8d, 0x2b, 0x93, 0x16, 0xce, 0xcd, 0x52, 0x8f,
+	0xf5, 0x72, 0x8f, 0x5a, 0x4a, 0xca, 0x16, 0x11, 0x89, 0x19, 0x0d, 0xbd, 0xc6, 0xd8, 0x99, 0x34,
+	0x71, 0x01, 0xa0, 0x2f, 0xa1, 0x57, 0x5c, 0x9d, 0xeb, 0x88, 0xc8, 0xc8, 0x6b, 0x9a, 0xf4, 0x6e,
+	0x01, 0x9f, 0x13, 0x19, 0x05, 0xf7, 0x0e, 0x54, 0xb5, 0x10, 0xad, 0x80, 0x84, 0xa1, 0xa0, 0x32,
+	0x9f, 0x4a, 0x6e, 0x96, 0xe7, 0xde, 0xca, 0xe6, 0xde, 0x87, 0xca, 0x3f, 0x7f, 0xce, 0x4d, 0x97,
+	0x4d, 0xac, 0x8f, 0x5a, 0x8d, 0x0d, 0xb7, 0x6d, 0xb6, 0x70, 0x01, 0xa0, 0x29, 0x34, 0xd7, 0x54,
+	0x91, 0x90, 0x28, 0x62, 0x2e, 0x76, 0x7b, 0x36, 0x2c, 0xcd, 0xe0, 0x2f, 0xeb, 0xc2, 0x9b, 0xa0,
+	0xe0, 0x3f, 0x70, 0xcb, 0x1e, 0xe4, 0x43, 0x93, 0x27, 0x54, 0x10, 0xc5, 0x85, 0x55, 0xb7, 0xb1,
+	0xb5, 0xf0, 0x05, 0x67, 0x8a, 0x2c, 0x94, 0x95, 0x98, 0x9b, 0x7a, 0x74, 0x82, 0x2e, 0x63, 0xce,
+	0xf2, 0xeb, 0x91, 0x59, 0xb3, 0x57, 0x55, 0xa8, 0x67, 0xab, 0x00, 0xad, 0x01, 0x8a, 0xa5, 0x80,
+	0x3c, 0xab, 0xea, 0xc1, 0x4e, 0xf1, 0x8f, 0x76, 0x78, 0xec, 0xc3, 0x38, 0x79, 0xf1, 0xe6, 0xdd,
+	0xfd, 0xde, 0xe7, 0xa8, 0x6d, 0x76, 0x54, 0x62, 0x02, 0xae, 0x0e, 0xd0, 0xb0, 0x64, 0x4e, 0x9f,
+	0x99, 0x35, 0xf1, 0x1c, 0xbd, 0x74, 0xa0, 0x5f, 0x50, 0xcc, 0x95, 0xa0, 0x64, 0xfd, 0x71, 0x55,
+	0xbf, 0x33, 0x55, 0x67, 0x08, 0x95, 0xcb, 0x48, 0x43, 0x78, 0x35, 0x42, 0xfe, 0x43, 0x34, 0xd7,
+	0xf0, 0x8d, 0x83, 0xfe, 0x87, 0x76, 0x69, 0x17, 0xa0, 0x4d, 0x95, 0x07, 0xdb, 0xc5, 0xf7, 0x77,
+	0xb9, 0xac, 0x82, 0x4f, 0x8c, 0x82, 0x41, 0xe0, 0x66, 0xb5, 0xb2, 0x88, 0xef, 0x9d, 0x13, 0x74,
+	0x01, 0x35, 0xf3, 0x6a, 0x50, 0xfe, 0x91, 0xcb, 0x8f, 0xca, 0xdf, 0xdf, 0x06, 0xb7, 0xc9, 0x50,
+	0xcf, 0x90, 0xc5, 0xec, 0x11, 0x9f, 0x2e, 0x0d, 0xc7, 0x1c, 0x1a, 0x76, 0x0f, 0xa0, 0x03, 0x9b,
+	0xb9, 0xbd, 0x29, 0xfc, 0xc3, 0x0f, 0x61, 0x4b, 0x79, 0x64, 0x28, 0x87, 0x68, 0x50, 0x50, 0xe6,
+	0x6f, 0xea, 0x27, 0xa8, 0xea, 0x85, 0x80, 0x90, 0x4d, 0x2d, 0x2d, 0x0b, 0x7f, 0xb8, 0x85, 0x59,
+	0x2e, 0xd7, 0x70, 0xd5, 0x51, 0x55, 0x73, 0xfd, 0xd2, 0xb8, 0xca, 0x7e, 0x4e, 0x37, 0x75, 0xf3,
+	0x17, 0xfa, 0xf6, 0xfd, 0x00, 0xef, 0xa3, 0xed, 0xdc, 0xbd, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -24,6 +24,267 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// UpdateAddressRequest contains the new addresses and metadata of the node.
+// Empty fields are left unchanged.
+type UpdateAddressRequest struct {
+	Address              string        `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Addresses            []string      `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Metadata             *NodeMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UpdateAddressRequest) Reset()         { *m = UpdateAddressRequest{} }
+func (m *UpdateAddressRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateAddressRequest) ProtoMessage()    {}
+func (*UpdateAddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{0}
+}
+
+func (m *UpdateAddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAddressRequest.Unmarshal(m, b)
+}
+func (m *UpdateAddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAddressRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateAddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAddressRequest.Merge(m, src)
+}
+func (m *UpdateAddressRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateAddressRequest.Size(m)
+}
+func (m *UpdateAddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAddressRequest proto.InternalMessageInfo
+
+func (m *UpdateAddressRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *UpdateAddressRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *UpdateAddressRequest) GetMetadata() *NodeMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// UpdateAddressResponse lists the nodes of the group that didn't accept the
+// announcement
+type UpdateAddressResponse struct {
+	Failed               []string `protobuf:"bytes,1,rep,name=failed,proto3" json:"failed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateAddressResponse) Reset()         { *m = UpdateAddressResponse{} }
+func (m *UpdateAddressResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateAddressResponse) ProtoMessage()    {}
+func (*UpdateAddressResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{1}
+}
+
+func (m *UpdateAddressResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAddressResponse.Unmarshal(m, b)
+}
+func (m *UpdateAddressResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAddressResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateAddressResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAddressResponse.Merge(m, src)
+}
+func (m *UpdateAddressResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateAddressResponse.Size(m)
+}
+func (m *UpdateAddressResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAddressResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAddressResponse proto.InternalMessageInfo
+
+func (m *UpdateAddressResponse) GetFailed() []string {
+	if m != nil {
+		return m.Failed
+	}
+	return nil
+}
+
+type PeerStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerStatusRequest) Reset()         { *m = PeerStatusRequest{} }
+func (m *PeerStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*PeerStatusRequest) ProtoMessage()    {}
+func (*PeerStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{2}
+}
+
+func (m *PeerStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerStatusRequest.Unmarshal(m, b)
+}
+func (m *PeerStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *PeerStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerStatusRequest.Merge(m, src)
+}
+func (m *PeerStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_PeerStatusRequest.Size(m)
+}
+func (m *PeerStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerStatusRequest proto.InternalMessageInfo
+
+// PeerStatus is the health of the connection to a node, as seen when calling it
+type PeerStatus struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// state is "unknown" for a node not contacted yet, "healthy", "degraded"
+	// after a failed call or "down" while the calls to the node are skipped
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// consecutive failed calls
+	Failures uint32 `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	// unix times of the last successful and failed calls, 0 if none
+	LastSuccess int64  `protobuf:"varint,4,opt,name=last_success,json=lastSuccess,proto3" json:"last_success,omitempty"`
+	LastFailure int64  `protobuf:"varint,5,opt,name=last_failure,json=lastFailure,proto3" json:"last_failure,omitempty"`
+	LastError   string `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// unix time at which a node down is contacted again
+	RetryAt              int64    `protobuf:"varint,7,opt,name=retry_at,json=retryAt,proto3" json:"retry_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerStatus) Reset()         { *m = PeerStatus{} }
+func (m *PeerStatus) String() string { return proto.CompactTextString(m) }
+func (*PeerStatus) ProtoMessage()    {}
+func (*PeerStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{3}
+}
+
+func (m *PeerStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerStatus.Unmarshal(m, b)
+}
+func (m *PeerStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerStatus.Marshal(b, m, deterministic)
+}
+func (m *PeerStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerStatus.Merge(m, src)
+}
+func (m *PeerStatus) XXX_Size() int {
+	return xxx_messageInfo_PeerStatus.Size(m)
+}
+func (m *PeerStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerStatus proto.InternalMessageInfo
+
+func (m *PeerStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *PeerStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *PeerStatus) GetFailures() uint32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetLastSuccess() int64 {
+	if m != nil {
+		return m.LastSuccess
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetLastFailure() int64 {
+	if m != nil {
+		return m.LastFailure
+	}
+	return 0
+}
+
+func (m *PeerStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *PeerStatus) GetRetryAt() int64 {
+	if m != nil {
+		return m.RetryAt
+	}
+	return 0
+}
+
+type PeerStatusResponse struct {
+	Peers                []*PeerStatus `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *PeerStatusResponse) Reset()         { *m = PeerStatusResponse{} }
+func (m *PeerStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*PeerStatusResponse) ProtoMessage()    {}
+func (*PeerStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{4}
+}
+
+func (m *PeerStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerStatusResponse.Unmarshal(m, b)
+}
+func (m *PeerStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *PeerStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerStatusResponse.Merge(m, src)
+}
+func (m *PeerStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_PeerStatusResponse.Size(m)
+}
+func (m *PeerStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerStatusResponse proto.InternalMessageInfo
+
+func (m *PeerStatusResponse) GetPeers() []*PeerStatus {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
 type InitDKGPacket struct {
 	DkgGroup *GroupInfo `protobuf:"bytes,1,opt,name=dkg_group,json=dkgGroup,proto3" json:"dkg_group,omitempty"`
 	IsLeader bool       `protobuf:"varint,2,opt,name=is_leader,json=isLeader,proto3" json:"is_leader,omitempty"`
@@ -39,7 +300,7 @@ func (m *InitDKGPacket) Reset()         { *m = InitDKGPacket{} }
 func (m *InitDKGPacket) String() string { return proto.CompactTextString(m) }
 func (*InitDKGPacket) ProtoMessage()    {}
 func (*InitDKGPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{0}
+	return fileDescriptor_2dd5961950a69ad7, []int{5}
 }
 
 func (m *InitDKGPacket) XXX_Unmarshal(b []byte) error {
@@ -103,7 +364,7 @@ func (m *EntropyInfo) Reset()         { *m = EntropyInfo{} }
 func (m *EntropyInfo) String() string { return proto.CompactTextString(m) }
 func (*EntropyInfo) ProtoMessage()    {}
 func (*EntropyInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{1}
+	return fileDescriptor_2dd5961950a69ad7, []int{6}
 }
 
 func (m *EntropyInfo) XXX_Unmarshal(b []byte) error {
@@ -159,7 +420,7 @@ func (m *InitResharePacket) Reset()         { *m = InitResharePacket{} }
 func (m *InitResharePacket) String() string { return proto.CompactTextString(m) }
 func (*InitResharePacket) ProtoMessage()    {}
 func (*InitResharePacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{2}
+	return fileDescriptor_2dd5961950a69ad7, []int{7}
 }
 
 func (m *InitResharePacket) XXX_Unmarshal(b []byte) error {
@@ -208,21 +469,135 @@ func (m *InitResharePacket) GetTimeout() string {
 	return ""
 }
 
+// ReshareDryRunResponse describes the changes a resharing would make.
+type ReshareDryRunResponse struct {
+	// nodes present in both the old and new group
+	Staying []*Node `protobuf:"bytes,1,rep,name=staying,proto3" json:"staying,omitempty"`
+	// nodes of the old group absent from the new group
+	Leaving []*Node `protobuf:"bytes,2,rep,name=leaving,proto3" json:"leaving,omitempty"`
+	// nodes of the new group absent from the old group
+	Joining      []*Node `protobuf:"bytes,3,rep,name=joining,proto3" json:"joining,omitempty"`
+	OldThreshold uint32  `protobuf:"varint,4,opt,name=old_threshold,json=oldThreshold,proto3" json:"old_threshold,omitempty"`
+	NewThreshold uint32  `protobuf:"varint,5,opt,name=new_threshold,json=newThreshold,proto3" json:"new_threshold,omitempty"`
+	// round at which the new group starts generating randomness
+	TransitionRound uint64 `protobuf:"varint,6,opt,name=transition_round,json=transitionRound,proto3" json:"transition_round,omitempty"`
+	// time of the transition round
+	TransitionTime int64 `protobuf:"varint,7,opt,name=transition_time,json=transitionTime,proto3" json:"transition_time,omitempty"`
+	// problems that prevent the resharing from happening safely
+	Problems []string `protobuf:"bytes,8,rep,name=problems,proto3" json:"problems,omitempty"`
+	// warnings that do not prevent the resharing
+	Warnings             []string `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReshareDryRunResponse) Reset()         { *m = ReshareDryRunResponse{} }
+func (m *ReshareDryRunResponse) String() string { return proto.CompactTextString(m) }
+func (*ReshareDryRunResponse) ProtoMessage()    {}
+func (*ReshareDryRunResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{8}
+}
+
+func (m *ReshareDryRunResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReshareDryRunResponse.Unmarshal(m, b)
+}
+func (m *ReshareDryRunResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReshareDryRunResponse.Marshal(b, m, deterministic)
+}
+func (m *ReshareDryRunResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReshareDryRunResponse.Merge(m, src)
+}
+func (m *ReshareDryRunResponse) XXX_Size() int {
+	return xxx_messageInfo_ReshareDryRunResponse.Size(m)
+}
+func (m *ReshareDryRunResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReshareDryRunResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReshareDryRunResponse proto.InternalMessageInfo
+
+func (m *ReshareDryRunResponse) GetStaying() []*Node {
+	if m != nil {
+		return m.Staying
+	}
+	return nil
+}
+
+func (m *ReshareDryRunResponse) GetLeaving() []*Node {
+	if m != nil {
+		return m.Leaving
+	}
+	return nil
+}
+
+func (m *ReshareDryRunResponse) GetJoining() []*Node {
+	if m != nil {
+		return m.Joining
+	}
+	return nil
+}
+
+func (m *ReshareDryRunResponse) GetOldThreshold() uint32 {
+	if m != nil {
+		return m.OldThreshold
+	}
+	return 0
+}
+
+func (m *ReshareDryRunResponse) GetNewThreshold() uint32 {
+	if m != nil {
+		return m.NewThreshold
+	}
+	return 0
+}
+
+func (m *ReshareDryRunResponse) GetTransitionRound() uint64 {
+	if m != nil {
+		return m.TransitionRound
+	}
+	return 0
+}
+
+func (m *ReshareDryRunResponse) GetTransitionTime() int64 {
+	if m != nil {
+		return m.TransitionTime
+	}
+	return 0
+}
+
+func (m *ReshareDryRunResponse) GetProblems() []string {
+	if m != nil {
+		return m.Problems
+	}
+	return nil
+}
+
+func (m *ReshareDryRunResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type GroupInfo struct {
 	// Types that are valid to be assigned to Location:
 	//	*GroupInfo_Path
 	//	*GroupInfo_Url
-	Location             isGroupInfo_Location `protobuf_oneof:"location"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Location isGroupInfo_Location `protobuf_oneof:"location"`
+	// optional hex-encoded hash of the group that the drand node expects. The
+	// group is rejected if its hash is different.
+	Hash                 string   `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GroupInfo) Reset()         { *m = GroupInfo{} }
 func (m *GroupInfo) String() string { return proto.CompactTextString(m) }
 func (*GroupInfo) ProtoMessage()    {}
 func (*GroupInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{3}
+	return fileDescriptor_2dd5961950a69ad7, []int{9}
 }
 
 func (m *GroupInfo) XXX_Unmarshal(b []byte) error {
@@ -280,6 +655,13 @@ func (m *GroupInfo) GetUrl() string {
 	return ""
 }
 
+func (m *GroupInfo) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*GroupInfo) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -299,7 +681,7 @@ func (m *ShareRequest) Reset()         { *m = ShareRequest{} }
 func (m *ShareRequest) String() string { return proto.CompactTextString(m) }
 func (*ShareRequest) ProtoMessage()    {}
 func (*ShareRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{4}
+	return fileDescriptor_2dd5961950a69ad7, []int{10}
 }
 
 func (m *ShareRequest) XXX_Unmarshal(b []byte) error {
@@ -333,7 +715,7 @@ func (m *ShareResponse) Reset()         { *m = ShareResponse{} }
 func (m *ShareResponse) String() string { return proto.CompactTextString(m) }
 func (*ShareResponse) ProtoMessage()    {}
 func (*ShareResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{5}
+	return fileDescriptor_2dd5961950a69ad7, []int{11}
 }
 
 func (m *ShareResponse) XXX_Unmarshal(b []byte) error {
@@ -378,7 +760,7 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{6}
+	return fileDescriptor_2dd5961950a69ad7, []int{12}
 }
 
 func (m *Ping) XXX_Unmarshal(b []byte) error {
@@ -400,6 +782,8 @@ func (m *Ping) XXX_DiscardUnknown() {
 var xxx_messageInfo_Ping proto.InternalMessageInfo
 
 type Pong struct {
+	// true when a DKG is set up and waiting for the packets of the other nodes
+	DkgRunning           bool     `protobuf:"varint,1,opt,name=dkg_running,json=dkgRunning,proto3" json:"dkg_running,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -409,7 +793,7 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{7}
+	return fileDescriptor_2dd5961950a69ad7, []int{13}
 }
 
 func (m *Pong) XXX_Unmarshal(b []byte) error {
@@ -430,6 +814,13 @@ func (m *Pong) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Pong proto.InternalMessageInfo
 
+func (m *Pong) GetDkgRunning() bool {
+	if m != nil {
+		return m.DkgRunning
+	}
+	return false
+}
+
 // PublicKeyRequest requests the public key of a drand node
 type PublicKeyRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -441,7 +832,7 @@ func (m *PublicKeyRequest) Reset()         { *m = PublicKeyRequest{} }
 func (m *PublicKeyRequest) String() string { return proto.CompactTextString(m) }
 func (*PublicKeyRequest) ProtoMessage()    {}
 func (*PublicKeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{8}
+	return fileDescriptor_2dd5961950a69ad7, []int{14}
 }
 
 func (m *PublicKeyRequest) XXX_Unmarshal(b []byte) error {
@@ -474,7 +865,7 @@ func (m *PublicKeyResponse) Reset()         { *m = PublicKeyResponse{} }
 func (m *PublicKeyResponse) String() string { return proto.CompactTextString(m) }
 func (*PublicKeyResponse) ProtoMessage()    {}
 func (*PublicKeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{9}
+	return fileDescriptor_2dd5961950a69ad7, []int{15}
 }
 
 func (m *PublicKeyResponse) XXX_Unmarshal(b []byte) error {
@@ -513,7 +904,7 @@ func (m *PrivateKeyRequest) Reset()         { *m = PrivateKeyRequest{} }
 func (m *PrivateKeyRequest) String() string { return proto.CompactTextString(m) }
 func (*PrivateKeyRequest) ProtoMessage()    {}
 func (*PrivateKeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{10}
+	return fileDescriptor_2dd5961950a69ad7, []int{16}
 }
 
 func (m *PrivateKeyRequest) XXX_Unmarshal(b []byte) error {
@@ -546,7 +937,7 @@ func (m *PrivateKeyResponse) Reset()         { *m = PrivateKeyResponse{} }
 func (m *PrivateKeyResponse) String() string { return proto.CompactTextString(m) }
 func (*PrivateKeyResponse) ProtoMessage()    {}
 func (*PrivateKeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{11}
+	return fileDescriptor_2dd5961950a69ad7, []int{17}
 }
 
 func (m *PrivateKeyResponse) XXX_Unmarshal(b []byte) error {
@@ -585,7 +976,7 @@ func (m *CokeyRequest) Reset()         { *m = CokeyRequest{} }
 func (m *CokeyRequest) String() string { return proto.CompactTextString(m) }
 func (*CokeyRequest) ProtoMessage()    {}
 func (*CokeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{12}
+	return fileDescriptor_2dd5961950a69ad7, []int{18}
 }
 
 func (m *CokeyRequest) XXX_Unmarshal(b []byte) error {
@@ -618,7 +1009,7 @@ func (m *CokeyResponse) Reset()         { *m = CokeyResponse{} }
 func (m *CokeyResponse) String() string { return proto.CompactTextString(m) }
 func (*CokeyResponse) ProtoMessage()    {}
 func (*CokeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{13}
+	return fileDescriptor_2dd5961950a69ad7, []int{19}
 }
 
 func (m *CokeyResponse) XXX_Unmarshal(b []byte) error {
@@ -656,7 +1047,7 @@ func (m *GroupTOMLRequest) Reset()         { *m = GroupTOMLRequest{} }
 func (m *GroupTOMLRequest) String() string { return proto.CompactTextString(m) }
 func (*GroupTOMLRequest) ProtoMessage()    {}
 func (*GroupTOMLRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{14}
+	return fileDescriptor_2dd5961950a69ad7, []int{20}
 }
 
 func (m *GroupTOMLRequest) XXX_Unmarshal(b []byte) error {
@@ -689,7 +1080,7 @@ func (m *GroupTOMLResponse) Reset()         { *m = GroupTOMLResponse{} }
 func (m *GroupTOMLResponse) String() string { return proto.CompactTextString(m) }
 func (*GroupTOMLResponse) ProtoMessage()    {}
 func (*GroupTOMLResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{15}
+	return fileDescriptor_2dd5961950a69ad7, []int{21}
 }
 
 func (m *GroupTOMLResponse) XXX_Unmarshal(b []byte) error {
@@ -727,7 +1118,7 @@ func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
 func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
 func (*ShutdownRequest) ProtoMessage()    {}
 func (*ShutdownRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{16}
+	return fileDescriptor_2dd5961950a69ad7, []int{22}
 }
 
 func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
@@ -758,7 +1149,7 @@ func (m *ShutdownResponse) Reset()         { *m = ShutdownResponse{} }
 func (m *ShutdownResponse) String() string { return proto.CompactTextString(m) }
 func (*ShutdownResponse) ProtoMessage()    {}
 func (*ShutdownResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{17}
+	return fileDescriptor_2dd5961950a69ad7, []int{23}
 }
 
 func (m *ShutdownResponse) XXX_Unmarshal(b []byte) error {
@@ -780,9 +1171,15 @@ func (m *ShutdownResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_ShutdownResponse proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterType((*UpdateAddressRequest)(nil), "drand.UpdateAddressRequest")
+	proto.RegisterType((*UpdateAddressResponse)(nil), "drand.UpdateAddressResponse")
+	proto.RegisterType((*PeerStatusRequest)(nil), "drand.PeerStatusRequest")
+	proto.RegisterType((*PeerStatus)(nil), "drand.PeerStatus")
+	proto.RegisterType((*PeerStatusResponse)(nil), "drand.PeerStatusResponse")
 	proto.RegisterType((*InitDKGPacket)(nil), "drand.InitDKGPacket")
 	proto.RegisterType((*EntropyInfo)(nil), "drand.EntropyInfo")
 	proto.RegisterType((*InitResharePacket)(nil), "drand.InitResharePacket")
+	proto.RegisterType((*ReshareDryRunResponse)(nil), "drand.ReshareDryRunResponse")
 	proto.RegisterType((*GroupInfo)(nil), "drand.GroupInfo")
 	proto.RegisterType((*ShareRequest)(nil), "drand.ShareRequest")
 	proto.RegisterType((*ShareResponse)(nil), "drand.ShareResponse")
@@ -805,48 +1202,75 @@ func init() {
 }
 
 var fileDescriptor_2dd5961950a69ad7 = []byte{
-	// 652 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xdf, 0x4f, 0xd3, 0x50,
-	0x14, 0xde, 0xdc, 0xd8, 0xda, 0xb3, 0x4d, 0xd9, 0x61, 0x81, 0x5a, 0x63, 0x42, 0x6e, 0xa2, 0x21,
-	0x11, 0x21, 0x41, 0x13, 0x1f, 0xd4, 0x44, 0x40, 0x45, 0x02, 0x86, 0xa5, 0xf0, 0xe4, 0x0b, 0x29,
-	0xed, 0x75, 0x34, 0xeb, 0xee, 0xad, 0xed, 0x2d, 0xb8, 0x7f, 0xc3, 0x17, 0xff, 0x00, 0xff, 0x51,
-	0x73, 0x7f, 0xb4, 0x6b, 0x19, 0x3c, 0x35, 0xdf, 0x77, 0xee, 0xf9, 0xf1, 0x9d, 0xfb, 0xdd, 0xc2,
-	0x5a, 0x98, 0xfa, 0x2c, 0xdc, 0x0d, 0x38, 0x13, 0x29, 0x8f, 0x77, 0x92, 0x94, 0x0b, 0x8e, 0x2b,
-	0x8a, 0x74, 0x87, 0x3a, 0x46, 0x67, 0x89, 0x98, 0xeb, 0x08, 0xf9, 0xd7, 0x84, 0xc1, 0x31, 0x8b,
-	0xc4, 0xe7, 0x93, 0xa3, 0xb1, 0x1f, 0x4c, 0xa9, 0xc0, 0xd7, 0x60, 0x87, 0xd3, 0xc9, 0xe5, 0x24,
-	0xe5, 0x79, 0xe2, 0x34, 0x37, 0x9b, 0x5b, 0xbd, 0xbd, 0xd5, 0x1d, 0x95, 0xb8, 0x73, 0x24, 0xb9,
-	0x63, 0xf6, 0x93, 0x7b, 0x56, 0x38, 0x9d, 0x28, 0x84, 0xcf, 0xc0, 0x8e, 0xb2, 0xcb, 0x98, 0xfa,
-	0x21, 0x4d, 0x9d, 0x47, 0x9b, 0xcd, 0x2d, 0xcb, 0xb3, 0xa2, 0xec, 0x54, 0x61, 0x74, 0xa0, 0x2b,
-	0xa2, 0x19, 0xe5, 0xb9, 0x70, 0x5a, 0x9b, 0xcd, 0x2d, 0xdb, 0x2b, 0x20, 0x6e, 0x43, 0x97, 0xca,
-	0x09, 0x93, 0xb9, 0xd3, 0x56, 0x3d, 0xd0, 0xf4, 0xf8, 0xa2, 0x59, 0xd5, 0xa5, 0x38, 0x42, 0xf6,
-	0xa1, 0x57, 0xe1, 0x71, 0x1d, 0x3a, 0x59, 0x90, 0x46, 0x89, 0x50, 0xf3, 0xd9, 0x9e, 0x41, 0xe8,
-	0x82, 0x95, 0x67, 0x34, 0x3d, 0x63, 0xf1, 0xdc, 0x01, 0x3d, 0x4a, 0x81, 0xc9, 0x9f, 0x26, 0x0c,
-	0xa5, 0x50, 0x8f, 0x66, 0xd7, 0x7e, 0x4a, 0x8d, 0x58, 0x02, 0x2d, 0x1e, 0x87, 0x0f, 0xca, 0x94,
-	0x41, 0x79, 0x86, 0xd1, 0x5b, 0xa5, 0xed, 0xde, 0x33, 0x8c, 0xde, 0xd6, 0xb7, 0xd0, 0x7a, 0x78,
-	0x0b, 0xed, 0xda, 0x16, 0xc8, 0x3e, 0xd8, 0x65, 0x21, 0x1c, 0x41, 0x3b, 0xf1, 0xc5, 0xb5, 0xd6,
-	0xf4, 0xad, 0xe1, 0x29, 0x84, 0x08, 0xad, 0x3c, 0x8d, 0x55, 0x77, 0x49, 0x4a, 0x70, 0x00, 0x60,
-	0xc5, 0x3c, 0xf0, 0x45, 0xc4, 0x19, 0x79, 0x0c, 0xfd, 0x73, 0x29, 0xc8, 0xa3, 0xbf, 0x72, 0x9a,
-	0x09, 0xf2, 0x1e, 0x06, 0x06, 0x67, 0x09, 0x67, 0x19, 0xc5, 0x11, 0xac, 0x44, 0x2c, 0xa4, 0xbf,
-	0x55, 0x89, 0x81, 0xa7, 0x81, 0x64, 0xd5, 0x1e, 0xd4, 0xb0, 0x7d, 0x4f, 0x03, 0xd2, 0x81, 0xf6,
-	0x38, 0x62, 0x13, 0xf5, 0xe5, 0x6c, 0x42, 0x10, 0x56, 0xc7, 0xf9, 0x55, 0x1c, 0x05, 0x27, 0x74,
-	0x5e, 0x34, 0x78, 0x05, 0xc3, 0x0a, 0x67, 0x9a, 0xac, 0x43, 0x27, 0xc9, 0xaf, 0x4e, 0xe8, 0x5c,
-	0x75, 0xe9, 0x7b, 0x06, 0x91, 0x35, 0x18, 0x8e, 0xd3, 0xe8, 0xc6, 0x17, 0xb4, 0x52, 0x61, 0x1b,
-	0xb0, 0x4a, 0x56, 0x4a, 0xa4, 0x51, 0xb5, 0x84, 0x42, 0x52, 0xe0, 0x21, 0x9f, 0x2e, 0xb2, 0x5f,
-	0xc0, 0xc0, 0xe0, 0x85, 0xc0, 0x80, 0x2f, 0xf2, 0x34, 0x90, 0xa3, 0xab, 0xd5, 0x5e, 0x9c, 0x7d,
-	0x3f, 0x2d, 0x52, 0xf7, 0x60, 0x58, 0xe1, 0x4c, 0xfa, 0x73, 0x00, 0xe5, 0xf5, 0x4b, 0xc1, 0x67,
-	0xb1, 0x31, 0x94, 0xad, 0x98, 0x0b, 0x3e, 0x8b, 0xc9, 0x10, 0x9e, 0x9c, 0x5f, 0xe7, 0x22, 0xe4,
-	0xb7, 0xac, 0x28, 0x83, 0xb0, 0xba, 0xa0, 0x74, 0x95, 0xbd, 0xbf, 0x6d, 0xe8, 0x1e, 0xea, 0x37,
-	0x87, 0x2f, 0xc1, 0x92, 0x5b, 0x94, 0x1b, 0xc4, 0x9e, 0xf1, 0x8b, 0x24, 0xdc, 0x12, 0xc8, 0xdd,
-	0x36, 0x70, 0x17, 0xba, 0xe6, 0xe9, 0xe1, 0xc8, 0x44, 0x6a, 0x4f, 0xd1, 0xed, 0x17, 0x6f, 0x42,
-	0x3e, 0x58, 0xd2, 0xc0, 0x77, 0xd0, 0xab, 0x58, 0x18, 0x9d, 0x4a, 0x52, 0xcd, 0xd6, 0x4b, 0x89,
-	0x6f, 0x61, 0x45, 0x99, 0x02, 0xd7, 0x4c, 0xa0, 0x6a, 0x19, 0x77, 0x54, 0x27, 0xb5, 0x22, 0xd2,
-	0xc0, 0x4f, 0x60, 0x97, 0x37, 0x8d, 0x1b, 0xc5, 0xec, 0x77, 0xfc, 0xe0, 0x3a, 0xcb, 0x81, 0xb2,
-	0xc2, 0x21, 0xc0, 0xe2, 0xa6, 0xcb, 0x79, 0x97, 0x1c, 0xe1, 0x3e, 0xbd, 0x27, 0x52, 0x16, 0xf9,
-	0x20, 0x2f, 0x3c, 0x8e, 0x69, 0x20, 0xa2, 0x1b, 0x55, 0xa7, 0x10, 0x51, 0xb5, 0x45, 0x29, 0xa2,
-	0xe6, 0x0d, 0x2d, 0x42, 0xdd, 0xf9, 0xd7, 0x28, 0xa6, 0xa5, 0x88, 0xbb, 0xce, 0x28, 0x45, 0x2c,
-	0xd9, 0x83, 0x34, 0xf0, 0x23, 0x58, 0xc5, 0x75, 0xe3, 0x7a, 0xb9, 0xaa, 0x9a, 0x25, 0xdc, 0x8d,
-	0x25, 0xbe, 0x48, 0x3f, 0xe8, 0xfe, 0xd0, 0x7f, 0xdf, 0xab, 0x8e, 0xfa, 0xe3, 0xbe, 0xf9, 0x1f,
-	0x00, 0x00, 0xff, 0xff, 0xa3, 0xc9, 0x24, 0xf8, 0xa2, 0x05, 0x00, 0x00,
+	// 1087 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0xcb, 0x72, 0xdb, 0x36,
+	0x14, 0x95, 0x2c, 0xc9, 0x12, 0xaf, 0xa4, 0xd8, 0x82, 0x95, 0x84, 0x61, 0x9c, 0xa9, 0xcb, 0x4e,
+	0x6a, 0x77, 0x9a, 0xda, 0x33, 0x6a, 0x67, 0xba, 0x68, 0x33, 0x53, 0xc7, 0x79, 0x79, 0xec, 0x34,
+	0x1e, 0xda, 0xd9, 0x74, 0xa3, 0x81, 0x45, 0x44, 0x62, 0x45, 0x01, 0x2c, 0x00, 0x5a, 0xd5, 0xaa,
+	0xff, 0xd0, 0x5f, 0xe8, 0x4f, 0x75, 0xdd, 0x7f, 0xe8, 0x3e, 0x83, 0x07, 0x1f, 0xb2, 0xe4, 0xec,
+	0x78, 0xce, 0x7d, 0xe0, 0xe2, 0xe0, 0xe2, 0x12, 0xb0, 0x13, 0x72, 0x4c, 0xc3, 0xa3, 0x11, 0xa3,
+	0x92, 0xb3, 0xf8, 0x30, 0xe1, 0x4c, 0x32, 0xd4, 0xd0, 0xa4, 0xd7, 0x33, 0x36, 0x32, 0x4b, 0xe4,
+	0xc2, 0x58, 0xbc, 0x2d, 0x43, 0xe1, 0x24, 0x32, 0x84, 0xff, 0x17, 0xf4, 0x3f, 0x24, 0x21, 0x96,
+	0xe4, 0x38, 0x0c, 0x39, 0x11, 0x22, 0x20, 0x7f, 0xa4, 0x44, 0x48, 0xe4, 0x42, 0x13, 0x1b, 0xc6,
+	0xad, 0xee, 0x55, 0x0f, 0x9c, 0x20, 0x83, 0x68, 0x17, 0x1c, 0xfb, 0x49, 0x84, 0xbb, 0xb1, 0x57,
+	0x3b, 0x70, 0x82, 0x82, 0x40, 0x47, 0xd0, 0x9a, 0x11, 0x89, 0x43, 0x2c, 0xb1, 0x5b, 0xdb, 0xab,
+	0x1e, 0xb4, 0x07, 0x3b, 0x87, 0x7a, 0xcd, 0xc3, 0x5f, 0x59, 0x48, 0xde, 0x59, 0x53, 0x90, 0x3b,
+	0xf9, 0x47, 0x70, 0xff, 0x56, 0x01, 0x22, 0x61, 0x54, 0x10, 0xf4, 0x00, 0x36, 0x3f, 0xe2, 0x28,
+	0x26, 0xa1, 0x5b, 0xd5, 0x8b, 0x58, 0xe4, 0xef, 0x40, 0xef, 0x82, 0x10, 0x7e, 0x29, 0xb1, 0x4c,
+	0xb3, 0x72, 0xfd, 0x7f, 0xab, 0x00, 0x05, 0xfb, 0x99, 0xea, 0xfb, 0xd0, 0x10, 0x12, 0x4b, 0xe2,
+	0x6e, 0x68, 0xde, 0x00, 0xe4, 0x41, 0x4b, 0x65, 0x4f, 0x39, 0x11, 0xba, 0xea, 0x6e, 0x90, 0x63,
+	0xf4, 0x25, 0x74, 0x62, 0x2c, 0xe4, 0x50, 0xa4, 0xa3, 0x91, 0x4a, 0x58, 0xdf, 0xab, 0x1e, 0xd4,
+	0x82, 0xb6, 0xe2, 0x2e, 0x0d, 0x95, 0xbb, 0xd8, 0x18, 0xb7, 0x51, 0xb8, 0xbc, 0x36, 0x14, 0x7a,
+	0x02, 0xa0, 0x5d, 0x08, 0xe7, 0x8c, 0xbb, 0x9b, 0x7a, 0x71, 0x47, 0x31, 0xaf, 0x14, 0x81, 0x1e,
+	0x41, 0x8b, 0x13, 0xc9, 0x17, 0x43, 0x2c, 0xdd, 0xa6, 0x8e, 0x6e, 0x6a, 0x7c, 0x2c, 0xfd, 0xe7,
+	0x80, 0xca, 0xfb, 0xb5, 0xea, 0xec, 0x43, 0x23, 0x21, 0x84, 0x0b, 0x2d, 0x4e, 0x7b, 0xd0, 0xb3,
+	0x22, 0x97, 0x3c, 0x8d, 0xdd, 0xff, 0xa7, 0x0a, 0xdd, 0x53, 0x1a, 0xc9, 0x97, 0x67, 0x6f, 0x2e,
+	0xf0, 0x68, 0x4a, 0x24, 0xfa, 0x0e, 0x9c, 0x70, 0x3a, 0x1e, 0x8e, 0x39, 0x4b, 0x13, 0x2d, 0x4f,
+	0x7b, 0xb0, 0x6d, 0xc3, 0xdf, 0x28, 0xee, 0x94, 0x7e, 0x64, 0x41, 0x2b, 0x9c, 0x8e, 0x35, 0x42,
+	0x8f, 0xc1, 0x89, 0xc4, 0x30, 0x26, 0x38, 0x24, 0x5c, 0xab, 0xd6, 0x0a, 0x5a, 0x91, 0x38, 0xd7,
+	0x58, 0x09, 0x2d, 0xa3, 0x19, 0x61, 0xa9, 0xd4, 0xba, 0x39, 0x41, 0x06, 0xd1, 0x33, 0x68, 0x12,
+	0xd5, 0x93, 0xc9, 0x42, 0x2b, 0xd6, 0x1e, 0x20, 0xbb, 0xc6, 0x2b, 0xc3, 0xea, 0x55, 0x32, 0x17,
+	0xff, 0x18, 0xda, 0x25, 0x5e, 0x9d, 0xbd, 0x18, 0xf1, 0x28, 0x91, 0xf6, 0xf8, 0x2c, 0x52, 0xe7,
+	0x94, 0x0a, 0xc2, 0xdf, 0xd3, 0x78, 0xe1, 0x82, 0x29, 0x25, 0xc3, 0xfe, 0xdf, 0x55, 0xe8, 0xa9,
+	0x8d, 0x06, 0x44, 0x4c, 0x30, 0x27, 0x76, 0xb3, 0x3e, 0xd4, 0x58, 0x1c, 0xde, 0xb9, 0x4d, 0x65,
+	0x54, 0x3e, 0x94, 0xcc, 0xdd, 0x8d, 0xbb, 0x7c, 0x28, 0x99, 0x2f, 0xab, 0x50, 0xbb, 0x5b, 0x85,
+	0xfa, 0x92, 0x0a, 0xfe, 0x7f, 0x1b, 0x70, 0xdf, 0x16, 0xf4, 0x92, 0x2f, 0x82, 0x94, 0xe6, 0x07,
+	0xf8, 0x14, 0x9a, 0x42, 0xe2, 0x45, 0x44, 0xc7, 0xf6, 0x08, 0xdb, 0xa5, 0x7b, 0x12, 0x64, 0x36,
+	0xe5, 0x16, 0x13, 0x7c, 0xa3, 0xdc, 0x36, 0xd6, 0xb8, 0x59, 0x9b, 0x72, 0xfb, 0x9d, 0x45, 0x54,
+	0xb9, 0xd5, 0xd6, 0xb8, 0x59, 0x1b, 0xfa, 0x0a, 0xba, 0x2c, 0x0e, 0x87, 0x72, 0xc2, 0x89, 0x98,
+	0x28, 0x5d, 0xea, 0xba, 0xd9, 0x3b, 0x2c, 0x0e, 0xaf, 0x32, 0x4e, 0x39, 0x51, 0x32, 0x2f, 0x39,
+	0x35, 0x8c, 0x13, 0x25, 0xf3, 0xc2, 0xe9, 0x1b, 0xd8, 0x96, 0x1c, 0x53, 0x11, 0xc9, 0x88, 0xd1,
+	0x21, 0x67, 0x29, 0x0d, 0x75, 0x57, 0xd7, 0x83, 0xad, 0x82, 0x0f, 0x14, 0x8d, 0xf6, 0xa1, 0x44,
+	0x0d, 0x95, 0x32, 0xb6, 0xc5, 0xef, 0x15, 0xf4, 0x55, 0x34, 0xd3, 0xb7, 0x30, 0xe1, 0xec, 0x3a,
+	0x26, 0x33, 0xe1, 0xb6, 0xf4, 0x9d, 0xcf, 0xb1, 0xb2, 0xcd, 0x31, 0x57, 0x9b, 0x10, 0xae, 0x63,
+	0x6c, 0x19, 0xf6, 0x3f, 0x80, 0x93, 0x9f, 0x16, 0xea, 0x43, 0x3d, 0xc1, 0x72, 0x62, 0x1a, 0xe7,
+	0x6d, 0x25, 0xd0, 0x08, 0x21, 0xa8, 0xa5, 0x3c, 0x36, 0x97, 0xfe, 0x6d, 0x25, 0x50, 0x00, 0x21,
+	0xa8, 0x4f, 0xb0, 0x98, 0xd8, 0xc6, 0xd5, 0xdf, 0x2f, 0x00, 0x5a, 0x31, 0x1b, 0x61, 0x55, 0x92,
+	0x7f, 0x0f, 0x3a, 0x97, 0xea, 0xe0, 0xb2, 0x19, 0xf3, 0x13, 0x74, 0x2d, 0xb6, 0x47, 0xd8, 0x87,
+	0x46, 0x44, 0x43, 0xf2, 0xa7, 0x4e, 0xdb, 0x0d, 0x0c, 0x50, 0xac, 0x3e, 0x6f, 0x9d, 0xb7, 0x13,
+	0x18, 0xe0, 0x6f, 0x42, 0xfd, 0x22, 0xa2, 0x63, 0x7f, 0x1f, 0xea, 0x17, 0x8c, 0x8e, 0xd1, 0x17,
+	0xd0, 0x56, 0x97, 0x90, 0xa7, 0x94, 0x9a, 0x16, 0x50, 0x1d, 0x05, 0xe1, 0x74, 0x1c, 0x18, 0xc6,
+	0x47, 0xb0, 0x7d, 0x91, 0x5e, 0xc7, 0xd1, 0xe8, 0x8c, 0x2c, 0xb2, 0x0a, 0xbe, 0x85, 0x5e, 0x89,
+	0x2b, 0xe6, 0x64, 0x92, 0x5e, 0x9f, 0x91, 0x85, 0x2e, 0xa3, 0x13, 0x58, 0xa4, 0xe7, 0x24, 0x8f,
+	0x6e, 0xb0, 0x24, 0xa5, 0x0c, 0xcf, 0x00, 0x95, 0xc9, 0x52, 0x0a, 0x1e, 0x95, 0x53, 0x68, 0xa4,
+	0x14, 0x38, 0x61, 0xd3, 0x22, 0xfa, 0x29, 0x74, 0x2d, 0x2e, 0x14, 0x18, 0xb1, 0x22, 0xce, 0x00,
+	0x55, 0xba, 0x3e, 0x8f, 0xab, 0xf7, 0xef, 0xce, 0xb3, 0xd0, 0x01, 0xf4, 0x4a, 0x9c, 0x0d, 0x7f,
+	0x02, 0xa0, 0xa7, 0xd0, 0x50, 0xb2, 0x59, 0x6c, 0xaf, 0xba, 0xa3, 0x99, 0x2b, 0x36, 0x8b, 0xfd,
+	0x1e, 0x6c, 0x5d, 0x4e, 0x52, 0x19, 0xb2, 0x39, 0xcd, 0xd2, 0x20, 0xd8, 0x2e, 0x28, 0x93, 0x65,
+	0xf0, 0x7f, 0x03, 0x9a, 0x27, 0xe6, 0xff, 0x87, 0xbe, 0x86, 0x96, 0x92, 0x59, 0x4b, 0x9c, 0x5d,
+	0x01, 0x45, 0x78, 0x39, 0x60, 0x74, 0xec, 0x57, 0xd0, 0x11, 0x34, 0xed, 0x50, 0x44, 0x7d, 0x6b,
+	0x59, 0x1a, 0x92, 0x5e, 0x27, 0x9b, 0x56, 0xea, 0xe7, 0xe9, 0x57, 0xd0, 0x8f, 0xd0, 0x2e, 0x0d,
+	0x17, 0xe4, 0x96, 0x82, 0x96, 0x06, 0xce, 0x4a, 0xe0, 0x29, 0x74, 0x97, 0x06, 0xc0, 0x67, 0x42,
+	0x77, 0xad, 0x65, 0xed, 0xc0, 0xf0, 0x2b, 0xe8, 0x07, 0x68, 0xe8, 0x06, 0x44, 0xd9, 0x2f, 0xb5,
+	0xdc, 0x9e, 0x5e, 0x7f, 0x99, 0xcc, 0xa3, 0x7e, 0x01, 0x27, 0x6f, 0x1a, 0xf4, 0x30, 0x93, 0xe1,
+	0x56, 0x6b, 0x79, 0xee, 0xaa, 0x21, 0xcf, 0x70, 0x02, 0x50, 0x34, 0x4d, 0x5e, 0xff, 0x4a, 0x73,
+	0x79, 0x8f, 0xd6, 0x58, 0xf2, 0x24, 0x3f, 0xab, 0xde, 0x89, 0x63, 0x32, 0x92, 0xd1, 0x8d, 0xce,
+	0x93, 0x6d, 0xa2, 0xdc, 0x61, 0x5e, 0x7f, 0x99, 0x2c, 0x6f, 0x42, 0xb7, 0xcf, 0xeb, 0x28, 0x26,
+	0xf9, 0x26, 0x6e, 0x37, 0x99, 0xe7, 0xae, 0x1a, 0xf2, 0x0c, 0xcf, 0xa1, 0x95, 0x75, 0x0e, 0x7a,
+	0x90, 0x4b, 0xb5, 0xd4, 0x5d, 0xde, 0xc3, 0x15, 0x3e, 0x0f, 0x3f, 0x87, 0xee, 0xd2, 0x33, 0x05,
+	0x3d, 0xb6, 0xbe, 0xeb, 0x5e, 0x4f, 0xde, 0xee, 0x7a, 0xe3, 0x92, 0xa2, 0xa5, 0xd7, 0xca, 0xea,
+	0xcf, 0xfb, 0xb6, 0xa2, 0x2b, 0x0f, 0x00, 0xbf, 0xf2, 0xa2, 0xf9, 0x9b, 0x79, 0xe7, 0x5d, 0x6f,
+	0xea, 0xa7, 0xdc, 0xf7, 0x9f, 0x06, 0x00, 0x46, 0xe7, 0x55, 0x73, 0x0c, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -868,6 +1292,10 @@ type ControlClient interface {
 	// InitReshares sends all informations so that the drand node knows how to
 	// proceeed during the next resharing protocol.
 	InitReshare(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*Empty, error)
+	// ReshareDryRun checks the old and new groups of a resharing without
+	// running it and returns the differences between them as well as any
+	// problem that would prevent the resharing to happen safely.
+	ReshareDryRun(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*ReshareDryRunResponse, error)
 	// Share returns the current private share used by the node
 	Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error)
 	// PublicKey returns the longterm public key of the drand node
@@ -881,6 +1309,12 @@ type ControlClient interface {
 	// control functionalities
 	GroupFile(ctx context.Context, in *GroupTOMLRequest, opts ...grpc.CallOption) (*GroupTOMLResponse, error)
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	// UpdateAddress updates the addresses and metadata of the node and
+	// announces them to the other nodes of the group
+	UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error)
+	// PeerStatus returns the health of the connections of the node to the
+	// other nodes of its group
+	PeerStatus(ctx context.Context, in *PeerStatusRequest, opts ...grpc.CallOption) (*PeerStatusResponse, error)
 }
 
 type controlClient struct {
@@ -918,6 +1352,15 @@ func (c *controlClient) InitReshare(ctx context.Context, in *InitResharePacket,
 	return out, nil
 }
 
+func (c *controlClient) ReshareDryRun(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*ReshareDryRunResponse, error) {
+	out := new(ReshareDryRunResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/ReshareDryRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlClient) Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error) {
 	out := new(ShareResponse)
 	err := c.cc.Invoke(ctx, "/drand.Control/Share", in, out, opts...)
@@ -972,6 +1415,24 @@ func (c *controlClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts
 	return out, nil
 }
 
+func (c *controlClient) UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error) {
+	out := new(UpdateAddressResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/UpdateAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) PeerStatus(ctx context.Context, in *PeerStatusRequest, opts ...grpc.CallOption) (*PeerStatusResponse, error) {
+	out := new(PeerStatusResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/PeerStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServer is the server API for Control service.
 type ControlServer interface {
 	// PingPong returns an empty message. Purpose is to test the control port.
@@ -981,6 +1442,10 @@ type ControlServer interface {
 	// InitReshares sends all informations so that the drand node knows how to
 	// proceeed during the next resharing protocol.
 	InitReshare(context.Context, *InitResharePacket) (*Empty, error)
+	// ReshareDryRun checks the old and new groups of a resharing without
+	// running it and returns the differences between them as well as any
+	// problem that would prevent the resharing to happen safely.
+	ReshareDryRun(context.Context, *InitResharePacket) (*ReshareDryRunResponse, error)
 	// Share returns the current private share used by the node
 	Share(context.Context, *ShareRequest) (*ShareResponse, error)
 	// PublicKey returns the longterm public key of the drand node
@@ -994,6 +1459,12 @@ type ControlServer interface {
 	// control functionalities
 	GroupFile(context.Context, *GroupTOMLRequest) (*GroupTOMLResponse, error)
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	// UpdateAddress updates the addresses and metadata of the node and
+	// announces them to the other nodes of the group
+	UpdateAddress(context.Context, *UpdateAddressRequest) (*UpdateAddressResponse, error)
+	// PeerStatus returns the health of the connections of the node to the
+	// other nodes of its group
+	PeerStatus(context.Context, *PeerStatusRequest) (*PeerStatusResponse, error)
 }
 
 // UnimplementedControlServer can be embedded to have forward compatible implementations.
@@ -1009,6 +1480,9 @@ func (*UnimplementedControlServer) InitDKG(ctx context.Context, req *InitDKGPack
 func (*UnimplementedControlServer) InitReshare(ctx context.Context, req *InitResharePacket) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method InitReshare not implemented")
 }
+func (*UnimplementedControlServer) ReshareDryRun(ctx context.Context, req *InitResharePacket) (*ReshareDryRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReshareDryRun not implemented")
+}
 func (*UnimplementedControlServer) Share(ctx context.Context, req *ShareRequest) (*ShareResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Share not implemented")
 }
@@ -1027,6 +1501,12 @@ func (*UnimplementedControlServer) GroupFile(ctx context.Context, req *GroupTOML
 func (*UnimplementedControlServer) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
 }
+func (*UnimplementedControlServer) UpdateAddress(ctx context.Context, req *UpdateAddressRequest) (*UpdateAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAddress not implemented")
+}
+func (*UnimplementedControlServer) PeerStatus(ctx context.Context, req *PeerStatusRequest) (*PeerStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PeerStatus not implemented")
+}
 
 func RegisterControlServer(s *grpc.Server, srv ControlServer) {
 	s.RegisterService(&_Control_serviceDesc, srv)
@@ -1086,6 +1566,24 @@ func _Control_InitReshare_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_ReshareDryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitResharePacket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ReshareDryRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/ReshareDryRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ReshareDryRun(ctx, req.(*InitResharePacket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Control_Share_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ShareRequest)
 	if err := dec(in); err != nil {
@@ -1194,6 +1692,42 @@ func _Control_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_UpdateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).UpdateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/UpdateAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).UpdateAddress(ctx, req.(*UpdateAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_PeerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).PeerStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/PeerStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).PeerStatus(ctx, req.(*PeerStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Control_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "drand.Control",
 	HandlerType: (*ControlServer)(nil),
@@ -1210,6 +1744,10 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "InitReshare",
 			Handler:    _Control_InitReshare_Handler,
 		},
+		{
+			MethodName: "ReshareDryRun",
+			Handler:    _Control_ReshareDryRun_Handler,
+		},
 		{
 			MethodName: "Share",
 			Handler:    _Control_Share_Handler,
@@ -1234,6 +1772,14 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Control_Shutdown_Handler,
 		},
+		{
+			MethodName: "UpdateAddress",
+			Handler:    _Control_UpdateAddress_Handler,
+		},
+		{
+			MethodName: "PeerStatus",
+			Handler:    _Control_PeerStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "drand/control.proto",
@@ -25,6 +25,177 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// AddressAnnouncement contains the new addresses and metadata of a node. It is
+// signed by the longterm key of the node, and identity_signature is the proof
+// of possession of the key for the new address.
+type AddressAnnouncement struct {
+	Key               string        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Address           string        `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Addresses         []string      `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	TLS               bool          `protobuf:"varint,4,opt,name=TLS,proto3" json:"TLS,omitempty"`
+	Metadata          *NodeMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	IdentitySignature []byte        `protobuf:"bytes,6,opt,name=identity_signature,json=identitySignature,proto3" json:"identity_signature,omitempty"`
+	// unix time at which the announcement was made
+	Timestamp int64  `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature []byte `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+	// hash of the public key of the TLS certificate bound to the identity
+	TlsKeyHash           []byte   `protobuf:"bytes,9,opt,name=tls_key_hash,json=tlsKeyHash,proto3" json:"tls_key_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressAnnouncement) Reset()         { *m = AddressAnnouncement{} }
+func (m *AddressAnnouncement) String() string { return proto.CompactTextString(m) }
+func (*AddressAnnouncement) ProtoMessage()    {}
+func (*AddressAnnouncement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{0}
+}
+
+func (m *AddressAnnouncement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressAnnouncement.Unmarshal(m, b)
+}
+func (m *AddressAnnouncement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressAnnouncement.Marshal(b, m, deterministic)
+}
+func (m *AddressAnnouncement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressAnnouncement.Merge(m, src)
+}
+func (m *AddressAnnouncement) XXX_Size() int {
+	return xxx_messageInfo_AddressAnnouncement.Size(m)
+}
+func (m *AddressAnnouncement) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressAnnouncement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressAnnouncement proto.InternalMessageInfo
+
+func (m *AddressAnnouncement) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *AddressAnnouncement) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AddressAnnouncement) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *AddressAnnouncement) GetTLS() bool {
+	if m != nil {
+		return m.TLS
+	}
+	return false
+}
+
+func (m *AddressAnnouncement) GetMetadata() *NodeMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *AddressAnnouncement) GetIdentitySignature() []byte {
+	if m != nil {
+		return m.IdentitySignature
+	}
+	return nil
+}
+
+func (m *AddressAnnouncement) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *AddressAnnouncement) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *AddressAnnouncement) GetTlsKeyHash() []byte {
+	if m != nil {
+		return m.TlsKeyHash
+	}
+	return nil
+}
+
+type GossipPacket struct {
+	PreviousRound        uint64   `protobuf:"varint,1,opt,name=previous_round,json=previousRound,proto3" json:"previous_round,omitempty"`
+	PreviousSig          []byte   `protobuf:"bytes,2,opt,name=previous_sig,json=previousSig,proto3" json:"previous_sig,omitempty"`
+	Round                uint64   `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GossipPacket) Reset()         { *m = GossipPacket{} }
+func (m *GossipPacket) String() string { return proto.CompactTextString(m) }
+func (*GossipPacket) ProtoMessage()    {}
+func (*GossipPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{1}
+}
+
+func (m *GossipPacket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GossipPacket.Unmarshal(m, b)
+}
+func (m *GossipPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GossipPacket.Marshal(b, m, deterministic)
+}
+func (m *GossipPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GossipPacket.Merge(m, src)
+}
+func (m *GossipPacket) XXX_Size() int {
+	return xxx_messageInfo_GossipPacket.Size(m)
+}
+func (m *GossipPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_GossipPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GossipPacket proto.InternalMessageInfo
+
+func (m *GossipPacket) GetPreviousRound() uint64 {
+	if m != nil {
+		return m.PreviousRound
+	}
+	return 0
+}
+
+func (m *GossipPacket) GetPreviousSig() []byte {
+	if m != nil {
+		return m.PreviousSig
+	}
+	return nil
+}
+
+func (m *GossipPacket) GetRound() uint64 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *GossipPacket) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type BeaconPacket struct {
 	// Round is the round for which the beacon will be created from the partial
 	// signatures
@@ -43,7 +214,7 @@ func (m *BeaconPacket) Reset()         { *m = BeaconPacket{} }
 func (m *BeaconPacket) String() string { return proto.CompactTextString(m) }
 func (*BeaconPacket) ProtoMessage()    {}
 func (*BeaconPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{0}
+	return fileDescriptor_e344a98fea1e2f3a, []int{2}
 }
 
 func (m *BeaconPacket) XXX_Unmarshal(b []byte) error {
@@ -103,7 +274,7 @@ func (m *SetupPacket) Reset()         { *m = SetupPacket{} }
 func (m *SetupPacket) String() string { return proto.CompactTextString(m) }
 func (*SetupPacket) ProtoMessage()    {}
 func (*SetupPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{1}
+	return fileDescriptor_e344a98fea1e2f3a, []int{3}
 }
 
 func (m *SetupPacket) XXX_Unmarshal(b []byte) error {
@@ -147,7 +318,7 @@ func (m *ResharePacket) Reset()         { *m = ResharePacket{} }
 func (m *ResharePacket) String() string { return proto.CompactTextString(m) }
 func (*ResharePacket) ProtoMessage()    {}
 func (*ResharePacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{2}
+	return fileDescriptor_e344a98fea1e2f3a, []int{4}
 }
 
 func (m *ResharePacket) XXX_Unmarshal(b []byte) error {
@@ -195,7 +366,7 @@ func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
 func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
 func (*SyncRequest) ProtoMessage()    {}
 func (*SyncRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{3}
+	return fileDescriptor_e344a98fea1e2f3a, []int{5}
 }
 
 func (m *SyncRequest) XXX_Unmarshal(b []byte) error {
@@ -238,7 +409,7 @@ func (m *SyncResponse) Reset()         { *m = SyncResponse{} }
 func (m *SyncResponse) String() string { return proto.CompactTextString(m) }
 func (*SyncResponse) ProtoMessage()    {}
 func (*SyncResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{4}
+	return fileDescriptor_e344a98fea1e2f3a, []int{6}
 }
 
 func (m *SyncResponse) XXX_Unmarshal(b []byte) error {
@@ -288,6 +459,8 @@ func (m *SyncResponse) GetSignature() []byte {
 }
 
 func init() {
+	proto.RegisterType((*AddressAnnouncement)(nil), "drand.AddressAnnouncement")
+	proto.RegisterType((*GossipPacket)(nil), "drand.GossipPacket")
 	proto.RegisterType((*BeaconPacket)(nil), "drand.BeaconPacket")
 	proto.RegisterType((*SetupPacket)(nil), "drand.SetupPacket")
 	proto.RegisterType((*ResharePacket)(nil), "drand.ResharePacket")
@@ -300,32 +473,44 @@ func init() {
 }
 
 var fileDescriptor_e344a98fea1e2f3a = []byte{
-	// 395 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xdf, 0x8a, 0xd3, 0x40,
-	0x18, 0xc5, 0x99, 0xfe, 0xb1, 0xe6, 0x4b, 0x2a, 0x38, 0xcd, 0x45, 0x09, 0x16, 0x6b, 0x40, 0xa8,
-	0xa0, 0xa9, 0x54, 0xf0, 0x01, 0x2a, 0x82, 0x37, 0x4a, 0x49, 0xef, 0xbc, 0x09, 0x63, 0x32, 0x26,
-	0xa1, 0x6d, 0x66, 0x9c, 0x49, 0x5c, 0xfa, 0x0e, 0x0b, 0xfb, 0x62, 0xfb, 0x50, 0xcb, 0xfc, 0xe9,
-	0x26, 0xed, 0xf6, 0x62, 0x2f, 0x0a, 0xcd, 0x39, 0x27, 0x93, 0xf9, 0x7e, 0xdf, 0x01, 0x3f, 0x13,
-	0xa4, 0xca, 0x96, 0x5c, 0xb0, 0x9a, 0xa5, 0x6c, 0x1f, 0xe9, 0x3f, 0x78, 0xa8, 0xd5, 0xc0, 0x4f,
-	0xc5, 0x91, 0xd7, 0x6c, 0x99, 0xed, 0x72, 0xf5, 0x33, 0x66, 0xf0, 0xda, 0xbc, 0x42, 0x0f, 0xbc,
-	0x3e, 0x1a, 0x29, 0xbc, 0x43, 0xe0, 0xad, 0x29, 0x49, 0x59, 0xb5, 0x21, 0xe9, 0x8e, 0xd6, 0xd8,
-	0x87, 0xa1, 0x60, 0x4d, 0x95, 0x4d, 0xd1, 0x1c, 0x2d, 0x06, 0xb1, 0x79, 0xc0, 0xef, 0xe1, 0x15,
-	0x17, 0xf4, 0x7f, 0xc9, 0x1a, 0x99, 0x18, 0xbb, 0xa7, 0xed, 0xf1, 0x49, 0x8d, 0x75, 0xec, 0x2d,
-	0xb8, 0x9c, 0x88, 0xba, 0x24, 0xfb, 0x44, 0x96, 0xf9, 0xb4, 0x3f, 0x47, 0x0b, 0x2f, 0x06, 0x2b,
-	0x6d, 0xcb, 0x1c, 0xbf, 0x03, 0xef, 0xf1, 0x1c, 0x95, 0x18, 0xe8, 0x84, 0x7b, 0xd2, 0xb6, 0x65,
-	0x1e, 0x7e, 0x04, 0x77, 0x4b, 0xeb, 0x86, 0xdb, 0xfb, 0xcc, 0xa0, 0x9f, 0xed, 0x72, 0x7d, 0x1b,
-	0x77, 0xe5, 0x46, 0x6a, 0x18, 0xe3, 0xc4, 0x4a, 0x0f, 0x7f, 0xc2, 0x38, 0xa6, 0xb2, 0x20, 0x82,
-	0x3e, 0x2b, 0x8f, 0x67, 0x00, 0xb9, 0x60, 0x0d, 0x4f, 0x0a, 0x22, 0x0b, 0x3d, 0x84, 0x13, 0x3b,
-	0x5a, 0xf9, 0x41, 0x64, 0xa1, 0x3f, 0x7e, 0xac, 0xd2, 0x98, 0xfe, 0x6b, 0xa8, 0x54, 0x87, 0xc1,
-	0x5f, 0xc1, 0x0e, 0x49, 0x97, 0x88, 0xa3, 0x14, 0x3d, 0x6e, 0x78, 0x8b, 0xc0, 0x33, 0x71, 0xc9,
-	0x59, 0x25, 0xe9, 0x15, 0x4c, 0xe8, 0x1a, 0xa6, 0x4b, 0x0a, 0xbd, 0x27, 0x14, 0xda, 0x35, 0xf4,
-	0xbb, 0x6b, 0x78, 0x03, 0x8e, 0x2c, 0xf3, 0x8a, 0xd4, 0x8d, 0xa0, 0x96, 0x5d, 0x2b, 0xac, 0xee,
-	0x11, 0xbc, 0xdc, 0xd8, 0x3a, 0xe0, 0x0f, 0x30, 0xd4, 0x18, 0x31, 0x8e, 0xf4, 0xd6, 0xa3, 0x0e,
-	0xd4, 0xc0, 0xb3, 0xda, 0x77, 0xd5, 0x04, 0xfc, 0x09, 0x46, 0x96, 0x21, 0xf6, 0xad, 0x71, 0xc6,
-	0xf4, 0x22, 0x1e, 0x81, 0xf3, 0x8b, 0xde, 0x98, 0xd2, 0xe0, 0x89, 0xb5, 0xba, 0x1d, 0xba, 0xc8,
-	0x7f, 0x05, 0x47, 0x41, 0xfa, 0x56, 0x90, 0xb2, 0x6a, 0x6f, 0xd3, 0x52, 0x0e, 0x26, 0x67, 0x9a,
-	0x41, 0xf9, 0x19, 0xad, 0x47, 0xbf, 0x4d, 0x99, 0xff, 0xbc, 0xd0, 0x55, 0xfd, 0xf2, 0x10, 0x00,
-	0x00, 0xff, 0xff, 0x89, 0xe3, 0xb8, 0x5e, 0xf2, 0x02, 0x00, 0x00,
+	// 592 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x95, 0xe3, 0xbc, 0x7c, 0xe3, 0x52, 0x3a, 0xcd, 0xc2, 0x8a, 0xa8, 0x30, 0x91, 0x90, 0x82,
+	0xa0, 0x29, 0x0a, 0x82, 0x0d, 0xab, 0x16, 0x21, 0x90, 0xa0, 0x55, 0x35, 0x61, 0xc5, 0x26, 0x1a,
+	0xec, 0xc1, 0xb1, 0x12, 0xcf, 0x18, 0xcf, 0x18, 0xe4, 0x4f, 0x40, 0x42, 0xe2, 0x53, 0xf8, 0x45,
+	0x34, 0x0f, 0xd7, 0x71, 0xda, 0x05, 0x3b, 0x16, 0x96, 0xc6, 0xe7, 0x1e, 0x5f, 0x9f, 0x39, 0xf7,
+	0x01, 0xe3, 0xb8, 0x20, 0x2c, 0x3e, 0xcb, 0x0b, 0x2e, 0x79, 0xc4, 0xb7, 0x73, 0x7d, 0x40, 0x3d,
+	0x8d, 0x4e, 0xc6, 0x51, 0x51, 0xe5, 0x92, 0x9f, 0xc5, 0x9b, 0x44, 0x3d, 0x26, 0x38, 0x39, 0x32,
+	0x9f, 0xd0, 0x2c, 0x97, 0x95, 0x85, 0x0e, 0x0d, 0x44, 0xf2, 0xd4, 0x00, 0xd3, 0x3f, 0x1d, 0x38,
+	0x3e, 0x8f, 0xe3, 0x82, 0x0a, 0x71, 0xce, 0x18, 0x2f, 0x59, 0x44, 0x33, 0xca, 0x24, 0xba, 0x0f,
+	0xee, 0x86, 0x56, 0x81, 0x13, 0x3a, 0x33, 0x0f, 0xab, 0x23, 0x0a, 0x60, 0x40, 0x0c, 0x31, 0xe8,
+	0x68, 0xb4, 0x7e, 0x45, 0x0f, 0xc0, 0xb3, 0x47, 0x2a, 0x02, 0x37, 0x74, 0x67, 0x1e, 0x6e, 0x00,
+	0x95, 0xe9, 0xd3, 0xc7, 0x65, 0xd0, 0x0d, 0x9d, 0xd9, 0x10, 0xab, 0x23, 0x3a, 0x83, 0x61, 0x46,
+	0x25, 0x89, 0x89, 0x24, 0x41, 0x2f, 0x74, 0x66, 0xa3, 0xc5, 0xf1, 0x5c, 0xeb, 0x9a, 0x5f, 0xf1,
+	0x98, 0x5e, 0xda, 0x10, 0xbe, 0x21, 0xa1, 0x53, 0x40, 0x69, 0x4c, 0x99, 0x4c, 0x65, 0xb5, 0x12,
+	0x69, 0xc2, 0x88, 0x2c, 0x0b, 0x1a, 0xf4, 0x43, 0x67, 0xe6, 0xe3, 0xa3, 0x3a, 0xb2, 0xac, 0x03,
+	0x4a, 0x8f, 0x4c, 0x33, 0x2a, 0x24, 0xc9, 0xf2, 0x60, 0x10, 0x3a, 0x33, 0x17, 0x37, 0x80, 0x8a,
+	0x36, 0x39, 0x86, 0x3a, 0x47, 0x03, 0xa0, 0x10, 0x7c, 0xb9, 0x15, 0xab, 0x0d, 0xad, 0x56, 0x6b,
+	0x22, 0xd6, 0x81, 0xa7, 0x09, 0x20, 0xb7, 0xe2, 0x03, 0xad, 0xde, 0x13, 0xb1, 0x9e, 0xfe, 0x72,
+	0xc0, 0x7f, 0xc7, 0x85, 0x48, 0xf3, 0x6b, 0x12, 0x6d, 0xa8, 0x44, 0x8f, 0xe1, 0x5e, 0x5e, 0xd0,
+	0xef, 0x29, 0x2f, 0xc5, 0xaa, 0xe0, 0x25, 0x8b, 0xb5, 0x6b, 0x5d, 0x7c, 0x50, 0xa3, 0x58, 0x81,
+	0xe8, 0x11, 0xf8, 0x37, 0x34, 0x91, 0x26, 0xda, 0x44, 0x1f, 0x8f, 0x6a, 0x6c, 0x99, 0x26, 0x68,
+	0x0c, 0x3d, 0x93, 0xc0, 0xd5, 0x09, 0xcc, 0x4b, 0x5b, 0x70, 0x77, 0x4f, 0xf0, 0xf4, 0xb7, 0x03,
+	0xfe, 0x05, 0x25, 0x11, 0x67, 0x56, 0xce, 0x4d, 0x12, 0x67, 0x37, 0xc9, 0x6d, 0x91, 0x9d, 0xbb,
+	0x44, 0x3e, 0x84, 0x51, 0x4e, 0x0a, 0x99, 0x92, 0xad, 0xd6, 0xe8, 0x9a, 0xdb, 0x5b, 0x48, 0x49,
+	0xdc, 0xbf, 0x45, 0xf7, 0xd6, 0x2d, 0xa6, 0xcf, 0x60, 0xb4, 0xa4, 0xb2, 0xac, 0xed, 0x39, 0x01,
+	0x37, 0xde, 0x24, 0x5a, 0xcd, 0x68, 0x31, 0x9a, 0xab, 0xf6, 0x34, 0x11, 0xac, 0xf0, 0xe9, 0x25,
+	0x1c, 0x60, 0x2a, 0xd6, 0xa4, 0xa0, 0xff, 0xc4, 0x47, 0x27, 0x00, 0x49, 0xc1, 0xcb, 0xdc, 0x94,
+	0xc7, 0x74, 0xa2, 0xa7, 0x11, 0x5d, 0x1d, 0xf5, 0xf3, 0x8a, 0x45, 0x98, 0x7e, 0x2b, 0xa9, 0x50,
+	0xc9, 0xe0, 0x6b, 0xc1, 0xb3, 0x56, 0x5d, 0x3c, 0x85, 0xe8, 0xeb, 0xea, 0x5a, 0x1a, 0xba, 0xc8,
+	0x39, 0x13, 0xf4, 0xff, 0xd6, 0x72, 0xf1, 0xb3, 0x03, 0xc3, 0x6b, 0x3b, 0xe0, 0xe8, 0x09, 0xf4,
+	0xb4, 0x8d, 0x08, 0xd9, 0xe1, 0xd8, 0x31, 0x75, 0xe2, 0x5b, 0xec, 0xad, 0x9a, 0x6d, 0x74, 0x0a,
+	0x03, 0xeb, 0x21, 0x1a, 0xdb, 0x40, 0xcb, 0xd3, 0x3d, 0xfa, 0x1c, 0xbc, 0x2b, 0xfa, 0xc3, 0x34,
+	0x0d, 0xaa, 0x47, 0x6f, 0xb7, 0x87, 0xf6, 0xf8, 0xaf, 0xc0, 0x53, 0x26, 0xbd, 0x59, 0x93, 0x94,
+	0x35, 0x6a, 0x1a, 0x97, 0x27, 0xc7, 0x2d, 0xcc, 0x58, 0xf9, 0xdc, 0x41, 0xaf, 0xe1, 0xb0, 0xde,
+	0x29, 0x76, 0xc5, 0xa0, 0x89, 0x65, 0xde, 0xb1, 0x72, 0xda, 0x3f, 0x5d, 0xbc, 0x84, 0xbe, 0x99,
+	0x32, 0xf4, 0x14, 0xfa, 0x7b, 0x5a, 0x77, 0xc7, 0xaf, 0xfd, 0xd9, 0xc5, 0xe0, 0xb3, 0x59, 0x89,
+	0x5f, 0xfa, 0x7a, 0xbf, 0xbd, 0xf8, 0x3b, 0x00, 0x25, 0xec, 0x82, 0x38, 0x38, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -347,6 +532,9 @@ type ProtocolClient interface {
 	// NewBeacon asks for a partial signature to another node
 	NewBeacon(ctx context.Context, in *BeaconPacket, opts ...grpc.CallOption) (*Empty, error)
 	SyncChain(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (Protocol_SyncChainClient, error)
+	// AnnounceAddress updates the addresses and metadata of a node of the
+	// group, signed by its longterm key
+	AnnounceAddress(ctx context.Context, in *AddressAnnouncement, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type protocolClient struct {
@@ -416,6 +604,15 @@ func (x *protocolSyncChainClient) Recv() (*SyncResponse, error) {
 	return m, nil
 }
 
+func (c *protocolClient) AnnounceAddress(ctx context.Context, in *AddressAnnouncement, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/drand.Protocol/AnnounceAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProtocolServer is the server API for Protocol service.
 type ProtocolServer interface {
 	// Setup is doing the DKG setup phase
@@ -425,6 +622,9 @@ type ProtocolServer interface {
 	// NewBeacon asks for a partial signature to another node
 	NewBeacon(context.Context, *BeaconPacket) (*Empty, error)
 	SyncChain(*SyncRequest, Protocol_SyncChainServer) error
+	// AnnounceAddress updates the addresses and metadata of a node of the
+	// group, signed by its longterm key
+	AnnounceAddress(context.Context, *AddressAnnouncement) (*Empty, error)
 }
 
 // UnimplementedProtocolServer can be embedded to have forward compatible implementations.
@@ -443,6 +643,9 @@ func (*UnimplementedProtocolServer) NewBeacon(ctx context.Context, req *BeaconPa
 func (*UnimplementedProtocolServer) SyncChain(req *SyncRequest, srv Protocol_SyncChainServer) error {
 	return status.Errorf(codes.Unimplemented, "method SyncChain not implemented")
 }
+func (*UnimplementedProtocolServer) AnnounceAddress(ctx context.Context, req *AddressAnnouncement) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnounceAddress not implemented")
+}
 
 func RegisterProtocolServer(s *grpc.Server, srv ProtocolServer) {
 	s.RegisterService(&_Protocol_serviceDesc, srv)
@@ -523,6 +726,24 @@ func (x *protocolSyncChainServer) Send(m *SyncResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Protocol_AnnounceAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressAnnouncement)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolServer).AnnounceAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Protocol/AnnounceAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolServer).AnnounceAddress(ctx, req.(*AddressAnnouncement))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Protocol_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "drand.Protocol",
 	HandlerType: (*ProtocolServer)(nil),
@@ -539,6 +760,10 @@ var _Protocol_serviceDesc = grpc.ServiceDesc{
 			MethodName: "NewBeacon",
 			Handler:    _Protocol_NewBeacon_Handler,
 		},
+		{
+			MethodName: "AnnounceAddress",
+			Handler:    _Protocol_AnnounceAddress_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -549,3 +774,75 @@ var _Protocol_serviceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "drand/protocol.proto",
 }
+
+// GossipClient is the client API for Gossip service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GossipClient interface {
+	Beacon(ctx context.Context, in *GossipPacket, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type gossipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGossipClient(cc grpc.ClientConnInterface) GossipClient {
+	return &gossipClient{cc}
+}
+
+func (c *gossipClient) Beacon(ctx context.Context, in *GossipPacket, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/drand.Gossip/Beacon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GossipServer is the server API for Gossip service.
+type GossipServer interface {
+	Beacon(context.Context, *GossipPacket) (*Empty, error)
+}
+
+// UnimplementedGossipServer can be embedded to have forward compatible implementations.
+type UnimplementedGossipServer struct {
+}
+
+func (*UnimplementedGossipServer) Beacon(ctx context.Context, req *GossipPacket) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Beacon not implemented")
+}
+
+func RegisterGossipServer(s *grpc.Server, srv GossipServer) {
+	s.RegisterService(&_Gossip_serviceDesc, srv)
+}
+
+func _Gossip_Beacon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipPacket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).Beacon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Gossip/Beacon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).Beacon(ctx, req.(*GossipPacket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Gossip_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "drand.Gossip",
+	HandlerType: (*GossipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Beacon",
+			Handler:    _Gossip_Beacon_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "drand/protocol.proto",
+}
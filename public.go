@@ -1,10 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/core"
+	"github.com/drand/drand/key"
 	"github.com/drand/drand/net"
 	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/tlock"
 	"github.com/nikkolasg/slog"
 	"github.com/urfave/cli/v2"
 )
@@ -59,7 +69,17 @@ func getPublicRandomness(c *cli.Context) error {
 
 	public := group.PublicKey
 	client := core.NewGrpcClientFromCert(defaultManager)
+	if c.Bool(restFlag.Name) {
+		client = core.NewRESTClientFromCert(defaultManager)
+	}
+	if err := client.SetRandomnessHash(group.RandomnessHash); err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	client.SetUnchained(group.Unchained)
 	isTLS := !c.Bool("tls-disable")
+	if c.Bool(watchFlag.Name) {
+		return watchPublicRandomness(c, client, ids[0].Addr, public, isTLS)
+	}
 	var resp *drand.PublicRandResponse
 	var err error
 	var foundCorrect bool
@@ -84,6 +104,21 @@ func getPublicRandomness(c *cli.Context) error {
 	return nil
 }
 
+// watchPublicRandomness prints the randomness of the node as it is produced
+func watchPublicRandomness(c *cli.Context, client *core.Client, addr string, public *key.DistPublic, isTLS bool) error {
+	ch, errCh, err := client.Watch(context.Background(), addr, public, isTLS, c.Int(roundFlag.Name))
+	if err != nil {
+		return err
+	}
+	for resp := range ch {
+		printJSON(resp)
+	}
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("drand: randomness stream ended: %s", err)
+	}
+	return errors.New("drand: randomness stream ended")
+}
+
 func getCokeyCmd(c *cli.Context) error {
 	ids := getNodes(c)
 	defaultManager := net.NewCertManager()
@@ -108,3 +143,118 @@ func getCokeyCmd(c *cli.Context) error {
 	printJSON(dkey)
 	return nil
 }
+
+func encryptCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		slog.Fatal("Encrypt command takes a group file as argument.")
+	}
+	group := getGroup(c)
+	if group.PublicKey == nil {
+		slog.Fatalf("drand: group file must contain the distributed public key!")
+	}
+	data := readInput(c)
+	var prev *beacon.Beacon
+	round := uint64(c.Int(roundFlag.Name))
+	if group.Unchained {
+		if !c.IsSet(roundFlag.Name) {
+			slog.Fatalf("drand: encrypting toward an unchained group requires a round")
+		}
+	} else {
+		// the message of the next round depends on the last signature
+		resp := fetchPublic(c, group, 0)
+		prev = &beacon.Beacon{Round: resp.GetRound(), Signature: resp.GetSignature()}
+		if !c.IsSet(roundFlag.Name) {
+			round = prev.Round + 1
+		}
+	}
+	ciphertext, err := tlock.Encrypt(group, prev, round, data)
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	if c.IsSet(outFlag.Name) {
+		if err := key.Save(c.String(outFlag.Name), ciphertext, false); err != nil {
+			slog.Fatalf("drand: can't save ciphertext: %s", err)
+		}
+		slog.Infof("drand: data encrypted toward round %d", round)
+	} else if err := toml.NewEncoder(os.Stdout).Encode(ciphertext.TOML()); err != nil {
+		slog.Fatalf("drand: can't encode ciphertext: %s", err)
+	}
+	return nil
+}
+
+func decryptCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		slog.Fatal("Decrypt command takes a group file as argument.")
+	}
+	group := getGroup(c)
+	if group.PublicKey == nil {
+		slog.Fatalf("drand: group file must contain the distributed public key!")
+	}
+	ctoml := new(tlock.CiphertextTOML)
+	if _, err := toml.DecodeReader(bytes.NewReader(readInput(c)), ctoml); err != nil {
+		slog.Fatalf("drand: can't decode ciphertext: %s", err)
+	}
+	ciphertext := new(tlock.Ciphertext)
+	if err := ciphertext.FromTOML(ctoml); err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	resp := fetchPublic(c, group, int(ciphertext.Round))
+	data, err := tlock.Decrypt(group, ciphertext, resp.GetSignature())
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	if c.IsSet(outFlag.Name) {
+		if err := ioutil.WriteFile(c.String(outFlag.Name), data, 0600); err != nil {
+			slog.Fatalf("drand: can't write decrypted data: %s", err)
+		}
+		return nil
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// fetchPublic returns the verified beacon of the given round, the last one if
+// the round is 0, from the first node of the group answering.
+func fetchPublic(c *cli.Context, group *key.Group, round int) *drand.PublicRandResponse {
+	defaultManager := net.NewCertManager()
+	if c.IsSet(tlsCertFlag.Name) {
+		defaultManager.Add(c.String(tlsCertFlag.Name))
+	}
+	client := core.NewGrpcClientFromCert(defaultManager)
+	if err := client.SetRandomnessHash(group.RandomnessHash); err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	client.SetUnchained(group.Unchained)
+	isTLS := !c.Bool(insecureFlag.Name)
+	for _, id := range getNodes(c) {
+		var resp *drand.PublicRandResponse
+		var err error
+		if round == 0 {
+			resp, err = client.LastPublic(id.Addr, group.PublicKey, isTLS)
+		} else {
+			resp, err = client.Public(id.Addr, group.PublicKey, isTLS, round)
+		}
+		if err == nil {
+			return resp
+		}
+		slog.Printf("drand: could not get public randomness from %s: %s", id.Addr, err)
+	}
+	slog.Fatalf("drand: could not fetch round %d from any node", round)
+	return nil
+}
+
+// readInput returns the content of the file given by the in flag, of stdin
+// otherwise.
+func readInput(c *cli.Context) []byte {
+	var buff []byte
+	var err error
+	if c.IsSet(inFlag.Name) {
+		buff, err = ioutil.ReadFile(c.String(inFlag.Name))
+	} else {
+		buff, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		slog.Fatalf("drand: can't read input: %s", err)
+	}
+	return buff
+}
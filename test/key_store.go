@@ -3,10 +3,11 @@ package test
 import "github.com/drand/drand/key"
 
 type KeyStore struct {
-	priv  *key.Pair
-	share *key.Share
-	group *key.Group
-	dist  *key.DistPublic
+	priv    *key.Pair
+	pending *key.Pair
+	share   *key.Share
+	group   *key.Group
+	dist    *key.DistPublic
 }
 
 func NewKeyStore() key.Store {
@@ -22,6 +23,41 @@ func (k *KeyStore) LoadKeyPair() (*key.Pair, error) {
 	return k.priv, nil
 }
 
+func (k *KeyStore) SaveIdentity(i *key.Identity) error {
+	if k.priv == nil {
+		k.priv = &key.Pair{}
+	}
+	k.priv.Public = i
+	return nil
+}
+
+func (k *KeyStore) LoadIdentity() (*key.Identity, error) {
+	if k.priv == nil {
+		return nil, key.ErrAbsent
+	}
+	return k.priv.Public, nil
+}
+
+func (k *KeyStore) SavePendingKeyPair(p *key.Pair) error {
+	k.pending = p
+	return nil
+}
+
+func (k *KeyStore) LoadPendingKeyPair() (*key.Pair, error) {
+	if k.pending == nil {
+		return nil, key.ErrAbsent
+	}
+	return k.pending, nil
+}
+
+func (k *KeyStore) CommitPendingKeyPair() error {
+	if k.pending == nil {
+		return key.ErrAbsent
+	}
+	k.priv, k.pending = k.pending, nil
+	return nil
+}
+
 func (k *KeyStore) SaveShare(share *key.Share) error {
 	k.share = share
 	return nil
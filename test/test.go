@@ -95,10 +95,19 @@ func FreePort() string {
 
 // GenerateIDs returns n keys with random port localhost addresses
 func GenerateIDs(n int) []*key.Pair {
+	return GenerateSuiteIDs(n, key.DefaultSuite)
+}
+
+// GenerateSuiteIDs returns n keys of the given suite with random port localhost
+// addresses
+func GenerateSuiteIDs(n int, s *key.Suite) []*key.Pair {
 	keys := make([]*key.Pair, n)
 	addrs := Addresses(n)
 	for i := range addrs {
-		priv := key.NewKeyPair(addrs[i])
+		priv, err := key.NewSuiteKeyPair(addrs[i], s, false)
+		if err != nil {
+			panic(err)
+		}
 		keys[i] = priv
 	}
 	return keys
@@ -117,6 +126,9 @@ func BatchTLSIdentities(n int) ([]*key.Pair, *key.Group) {
 	pairs, group := BatchIdentities(n)
 	for i := 0; i < n; i++ {
 		pairs[i].Public.TLS = true
+		if err := pairs[i].SelfSign(); err != nil {
+			panic(err)
+		}
 	}
 	return pairs, group
 }
@@ -0,0 +1,232 @@
+// Package tlock implements timelock encryption toward future rounds of a drand
+// chain. A beacon is a BLS signature of the distributed key over the message
+// of its round, so the message of a future round can be used as an identity
+// in the Boneh-Franklin identity based encryption scheme: the signature of the
+// round, once published, is the private key of this identity.
+//
+// The data is encrypted with AES-GCM under a key derived from the pairing
+// e(P, H(m))^r where P is the distributed key, m the message of the round and
+// r a random scalar whose commitment U = r * G is part of the ciphertext. The
+// holder of the signature s * H(m) computes the same value as e(U, s * H(m)).
+//
+// The message of a round of an unchained group only depends on the round
+// number so data can be encrypted toward any future round. The message of a
+// round of a chained group depends on the previous signature, so the data can
+// only be encrypted toward the round following the last beacon known.
+package tlock
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/entropy"
+	"github.com/drand/drand/key"
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Ciphertext is data encrypted toward a round of a drand chain
+type Ciphertext struct {
+	// Round is the round whose signature decrypts the data
+	Round uint64
+	// PreviousRound and PreviousSig are the beacon the round is chained to,
+	// empty for the rounds of unchained groups
+	PreviousRound uint64
+	PreviousSig   []byte
+	// U is the commitment to the random scalar of the encryption, on the key
+	// group of the chain
+	U     kyber.Point
+	Nonce []byte
+	Data  []byte
+	// Scheme is the name of the suite of the chain
+	Scheme string
+}
+
+// CiphertextTOML is the TOML representation of a Ciphertext
+type CiphertextTOML struct {
+	Round         uint64
+	PreviousRound uint64 `toml:",omitempty"`
+	PreviousSig   string `toml:",omitempty"`
+	U             string
+	Nonce         string
+	Data          string
+	// Scheme is the name of the suite of the chain, empty for the default
+	Scheme string `toml:",omitempty"`
+}
+
+// Encrypt encrypts the data so it can only be decrypted with the signature of
+// the given round of the chain of the group. The group must contain the
+// distributed key. For a chained group, prev is the last beacon of the chain
+// and the round must be the one following it; prev is ignored for an
+// unchained group.
+func Encrypt(group *key.Group, prev *beacon.Beacon, round uint64, data []byte) (*Ciphertext, error) {
+	if group.PublicKey == nil {
+		return nil, errors.New("tlock: group without distributed key")
+	}
+	suite := group.Suite()
+	c := &Ciphertext{Round: round, Scheme: suite.Name}
+	if !group.Unchained {
+		if prev == nil {
+			return nil, errors.New("tlock: chained group requires the previous beacon")
+		}
+		if prev.Round+1 != round {
+			return nil, fmt.Errorf("tlock: chained group can only encrypt toward round %d following the last beacon", prev.Round+1)
+		}
+		c.PreviousRound = prev.Round
+		c.PreviousSig = prev.Signature
+	}
+	msg := beacon.MessageFor(group.Unchained, c.PreviousSig, c.PreviousRound, c.Round)
+	hm, err := hashToSig(suite, msg)
+	if err != nil {
+		return nil, err
+	}
+	r := suite.KeyGroup.Scalar().Pick(random.New())
+	c.U = suite.KeyGroup.Point().Mul(r, nil)
+	rP := suite.KeyGroup.Point().Mul(r, group.PublicKey.Key())
+	aead, err := newAEAD(suite, rP, hm, c.U)
+	if err != nil {
+		return nil, err
+	}
+	c.Nonce, err = entropy.GetRandom(nil, uint32(aead.NonceSize()))
+	if err != nil {
+		return nil, err
+	}
+	c.Data = aead.Seal(nil, c.Nonce, data, c.associatedData())
+	return c, nil
+}
+
+// Decrypt decrypts the ciphertext with the signature of its round, after
+// checking the signature against the distributed key of the group.
+func Decrypt(group *key.Group, c *Ciphertext, sig []byte) ([]byte, error) {
+	if group.PublicKey == nil {
+		return nil, errors.New("tlock: group without distributed key")
+	}
+	suite := group.Suite()
+	if c.Scheme != suite.Name {
+		return nil, fmt.Errorf("tlock: ciphertext of scheme %s for a group of scheme %s", c.Scheme, suite.Name)
+	}
+	msg := beacon.MessageFor(group.Unchained, c.PreviousSig, c.PreviousRound, c.Round)
+	if err := suite.Scheme.VerifyRecovered(group.PublicKey.Key(), msg, sig); err != nil {
+		return nil, fmt.Errorf("tlock: invalid signature for round %d: %s", c.Round, err)
+	}
+	sigPoint := suite.SigGroup.Point()
+	if err := sigPoint.UnmarshalBinary(sig); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(suite, c.U, sigPoint, c.U)
+	if err != nil {
+		return nil, err
+	}
+	data, err := aead.Open(nil, c.Nonce, c.Data, c.associatedData())
+	if err != nil {
+		return nil, fmt.Errorf("tlock: decryption failed: %s", err)
+	}
+	return data, nil
+}
+
+// associatedData binds the round and the chaining of the ciphertext to the
+// encrypted data
+func (c *Ciphertext) associatedData() []byte {
+	var buff [16]byte
+	binary.BigEndian.PutUint64(buff[:8], c.Round)
+	binary.BigEndian.PutUint64(buff[8:], c.PreviousRound)
+	return append(buff[:], c.PreviousSig...)
+}
+
+// newAEAD returns the cipher keyed by the pairing of the given points, the
+// first one on the key group and the second one on the signature group.
+func newAEAD(suite *key.Suite, keyPoint, sigPoint, u kyber.Point) (cipher.AEAD, error) {
+	var gt kyber.Point
+	if suite.Name == key.SuiteSigsOnG1 {
+		gt = suite.Pairing.Pair(sigPoint, keyPoint)
+	} else {
+		gt = suite.Pairing.Pair(keyPoint, sigPoint)
+	}
+	secret, err := gt.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	info, err := u.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	symKey := make([]byte, 32)
+	if _, err := hkdf.New(sha256.New, secret, nil, info).Read(symKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(symKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// hashToSig maps the message on the signature group the same way the BLS
+// signature scheme does.
+func hashToSig(suite *key.Suite, msg []byte) (kyber.Point, error) {
+	hashable, ok := suite.SigGroup.Point().(hashablePoint)
+	if !ok {
+		return nil, errors.New("tlock: signature group does not support hashing")
+	}
+	return hashable.Hash(msg), nil
+}
+
+// TOML returns a TOML-compatible representation of the ciphertext
+func (c *Ciphertext) TOML() interface{} {
+	ctoml := &CiphertextTOML{
+		Round:         c.Round,
+		PreviousRound: c.PreviousRound,
+		PreviousSig:   hex.EncodeToString(c.PreviousSig),
+		U:             key.PointToString(c.U),
+		Nonce:         hex.EncodeToString(c.Nonce),
+		Data:          hex.EncodeToString(c.Data),
+	}
+	if c.Scheme != key.DefaultSuite.Name {
+		ctoml.Scheme = c.Scheme
+	}
+	return ctoml
+}
+
+// FromTOML initializes the ciphertext from its TOML representation
+func (c *Ciphertext) FromTOML(i interface{}) error {
+	ctoml, ok := i.(*CiphertextTOML)
+	if !ok {
+		return errors.New("tlock: ciphertext can't decode from non CiphertextTOML struct")
+	}
+	suite, err := key.SuiteFromName(ctoml.Scheme)
+	if err != nil {
+		return err
+	}
+	c.Round = ctoml.Round
+	c.Scheme = suite.Name
+	c.PreviousRound = ctoml.PreviousRound
+	if c.PreviousSig, err = hex.DecodeString(ctoml.PreviousSig); err != nil {
+		return fmt.Errorf("tlock: invalid previous signature: %s", err)
+	}
+	if c.U, err = key.StringToPoint(suite.KeyGroup, ctoml.U); err != nil {
+		return fmt.Errorf("tlock: invalid commitment: %s", err)
+	}
+	if c.Nonce, err = hex.DecodeString(ctoml.Nonce); err != nil {
+		return fmt.Errorf("tlock: invalid nonce: %s", err)
+	}
+	if c.Data, err = hex.DecodeString(ctoml.Data); err != nil {
+		return fmt.Errorf("tlock: invalid data: %s", err)
+	}
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the ciphertext
+func (c *Ciphertext) TOMLValue() interface{} {
+	return &CiphertextTOML{}
+}
@@ -0,0 +1,123 @@
+package tlock
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/test"
+	"github.com/drand/kyber/share"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+// localChain simulates the threshold signing of a group locally
+type localChain struct {
+	group  *key.Group
+	shares []*share.PriShare
+	pub    *share.PubPoly
+}
+
+func newLocalChain(t *testing.T, n int, s *key.Suite, unchained bool) *localChain {
+	thr := key.DefaultThreshold(n)
+	priPoly := share.NewPriPoly(s.KeyGroup, thr, nil, random.New())
+	pubPoly := priPoly.Commit(s.KeyGroup.Point().Base())
+	_, commits := pubPoly.Info()
+	group := key.NewGroup(test.ListFromPrivates(test.GenerateSuiteIDs(n, s)), thr, 0)
+	group.PublicKey = &key.DistPublic{Coefficients: commits}
+	group.Unchained = unchained
+	return &localChain{group: group, shares: priPoly.Shares(n), pub: pubPoly}
+}
+
+func (l *localChain) sign(t *testing.T, prev *beacon.Beacon, round uint64) *beacon.Beacon {
+	b := &beacon.Beacon{Round: round}
+	if !l.group.Unchained && prev != nil {
+		b.PreviousRound = prev.Round
+		b.PreviousSig = prev.Signature
+	}
+	msg := beacon.MessageFor(l.group.Unchained, b.PreviousSig, b.PreviousRound, round)
+	scheme := l.group.Suite().Scheme
+	var partials [][]byte
+	for _, s := range l.shares[:l.group.Threshold] {
+		partial, err := scheme.Sign(s, msg)
+		require.NoError(t, err)
+		partials = append(partials, partial)
+	}
+	sig, err := scheme.Recover(l.pub, msg, partials, l.group.Threshold, len(l.shares))
+	require.NoError(t, err)
+	b.Signature = sig
+	return b
+}
+
+func TestTimelockChained(t *testing.T) {
+	for _, name := range []string{key.SuiteSigsOnG2, key.SuiteSigsOnG1} {
+		s, err := key.SuiteFromName(name)
+		require.NoError(t, err)
+		chain := newLocalChain(t, 5, s, false)
+		data := []byte("sealed until the next round")
+
+		b1 := chain.sign(t, nil, 1)
+		c, err := Encrypt(chain.group, b1, 2, data)
+		require.NoError(t, err)
+		require.False(t, bytes.Contains(c.Data, data))
+
+		// chained groups only allow the round following the last beacon
+		_, err = Encrypt(chain.group, b1, 3, data)
+		require.Error(t, err)
+		_, err = Encrypt(chain.group, nil, 2, data)
+		require.Error(t, err)
+
+		// the signature of the current round does not decrypt
+		_, err = Decrypt(chain.group, c, b1.Signature)
+		require.Error(t, err)
+
+		b2 := chain.sign(t, b1, 2)
+		plain, err := Decrypt(chain.group, c, b2.Signature)
+		require.NoError(t, err)
+		require.Equal(t, data, plain)
+
+		// a ciphertext of another scheme is rejected
+		other := *c
+		other.Scheme = "other"
+		_, err = Decrypt(chain.group, &other, b2.Signature)
+		require.Error(t, err)
+
+		// a ciphertext moved to another round is rejected
+		c.Round = 3
+		b3 := chain.sign(t, b2, 3)
+		_, err = Decrypt(chain.group, c, b3.Signature)
+		require.Error(t, err)
+	}
+}
+
+func TestTimelockUnchained(t *testing.T) {
+	chain := newLocalChain(t, 4, key.DefaultSuite, true)
+	data := []byte("sealed for ten rounds")
+	c, err := Encrypt(chain.group, nil, 10, data)
+	require.NoError(t, err)
+
+	_, err = Decrypt(chain.group, c, chain.sign(t, nil, 9).Signature)
+	require.Error(t, err)
+
+	// tampered data is rejected
+	sig := chain.sign(t, nil, 10).Signature
+	c.Data[0] ^= 1
+	_, err = Decrypt(chain.group, c, sig)
+	require.Error(t, err)
+	c.Data[0] ^= 1
+
+	// the ciphertext survives its TOML encoding
+	var buff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&buff).Encode(c.TOML()))
+	ctoml := new(CiphertextTOML)
+	_, err = toml.DecodeReader(&buff, ctoml)
+	require.NoError(t, err)
+	c2 := new(Ciphertext)
+	require.NoError(t, c2.FromTOML(ctoml))
+
+	plain, err := Decrypt(chain.group, c2, sig)
+	require.NoError(t, err)
+	require.Equal(t, data, plain)
+}
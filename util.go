@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/drand/drand/core"
+	"github.com/drand/drand/key"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// passphraseEnv is the environment variable from which the passphrase
+// protecting the private key and share is read
+const passphraseEnv = "DRAND_PASSPHRASE"
+
+var passphraseFileFlag = &cli.StringFlag{
+	Name: "passphrase-file",
+	Usage: "Read the passphrase encrypting the private key and share from this file. " +
+		"The passphrase can also be given with the " + passphraseEnv + " environment variable. " +
+		"If none is given and the files are encrypted, drand prompts for it.",
+}
+
+var piecesFlag = &cli.IntFlag{
+	Name:  "pieces",
+	Usage: "Number of pieces the share is split into, one per custodian.",
+}
+
+var backupThresholdFlag = &cli.IntFlag{
+	Name:  "threshold",
+	Usage: "Number of pieces needed to restore the share.",
+}
+
+var piecePassphraseFilesFlag = &cli.StringSliceFlag{
+	Name: "piece-passphrase-files",
+	Usage: "Files holding the passphrases encrypting the backup pieces, one per " +
+		"piece in order. The passphrases are prompted for if not given.",
+}
+
+// getPassphrase returns the passphrase given through the environment or the
+// passphrase file, nil if there is none.
+func getPassphrase(c *cli.Context) ([]byte, error) {
+	if pass := os.Getenv(passphraseEnv); pass != "" {
+		return []byte(pass), nil
+	}
+	if c.IsSet(passphraseFileFlag.Name) {
+		buff, err := ioutil.ReadFile(c.String(passphraseFileFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		pass := bytes.TrimRight(buff, "\r\n")
+		if len(pass) == 0 {
+			return nil, errors.New("empty passphrase file")
+		}
+		return pass, nil
+	}
+	return nil, nil
+}
+
+// promptPassphrase asks the passphrase on the terminal, twice if confirm is
+// true.
+func promptPassphrase(confirm bool) ([]byte, error) {
+	fmt.Print("Passphrase: ")
+	pass, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	if len(pass) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	if !confirm {
+		return pass, nil
+	}
+	fmt.Print("Confirm passphrase: ")
+	again, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pass, again) {
+		return nil, errors.New("passphrases don't match")
+	}
+	return pass, nil
+}
+
+// keyStore returns the store of the node. If a passphrase is given, or if the
+// private files are encrypted in which case the passphrase is prompted for,
+// the store encrypts the private key and share.
+func keyStore(c *cli.Context, conf *core.Config) key.Store {
+	pass, err := getPassphrase(c)
+	if err != nil {
+		fatal("drand: can't read passphrase: %s", err)
+	}
+	folder := conf.ConfigFolder()
+	if pass == nil && (key.IsEncrypted(key.PrivateKeyFile(folder)) || key.IsEncrypted(key.ShareFile(folder))) {
+		fmt.Println("drand: private files are encrypted")
+		if pass, err = promptPassphrase(false); err != nil {
+			fatal("drand: can't read passphrase: %s", err)
+		}
+	}
+	if pass == nil {
+		return key.NewFileStore(folder)
+	}
+	return key.NewEncryptedFileStore(folder, pass)
+}
+
+// encryptKeysCmd encrypts the existing plain private key and share of the
+// node with a passphrase.
+func encryptKeysCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	folder := conf.ConfigFolder()
+	pass, err := getPassphrase(c)
+	if err != nil {
+		fatal("drand: can't read passphrase: %s", err)
+	}
+	if pass == nil {
+		if pass, err = promptPassphrase(true); err != nil {
+			fatal("drand: can't read passphrase: %s", err)
+		}
+	}
+	plain := key.NewFileStore(folder)
+	encrypted := key.NewEncryptedFileStore(folder, pass)
+	pair, err := plain.LoadKeyPair()
+	if err == key.ErrPassphrase {
+		fatal("drand: private key already encrypted")
+	} else if err != nil {
+		fatal("drand: can't load private key: %s", err)
+	}
+	if err := encrypted.SaveKeyPair(pair); err != nil {
+		fatal("drand: can't save encrypted private key: %s", err)
+	}
+	fmt.Println("drand: private key encrypted")
+	if _, err := os.Stat(key.ShareFile(folder)); err != nil {
+		// no share yet, the daemon encrypts it once created
+		return nil
+	}
+	share, err := plain.LoadShare()
+	if err == key.ErrPassphrase {
+		fmt.Println("drand: share already encrypted")
+		return nil
+	} else if err != nil {
+		fatal("drand: can't load share: %s", err)
+	}
+	if err := encrypted.SaveShare(share); err != nil {
+		fatal("drand: can't save encrypted share: %s", err)
+	}
+	fmt.Println("drand: share encrypted")
+	return nil
+}
+
+// selfSignCmd adds the proof of possession of the private key to the public
+// identity of the node, for keys generated before it existed.
+func selfSignCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	fs := keyStore(c, conf)
+	pair, err := fs.LoadKeyPair()
+	if err != nil {
+		fatal("drand: can't load keypair: %s", err)
+	}
+	if pair.Public.ValidSignature() == nil {
+		fmt.Println("drand: public identity already signed")
+		return nil
+	}
+	if err := pair.SelfSign(); err != nil {
+		fatal("drand: can't sign identity: %s", err)
+	}
+	if err := fs.SaveKeyPair(pair); err != nil {
+		fatal("drand: can't save keypair: %s", err)
+	}
+	fmt.Println("drand: public identity signed")
+	return nil
+}
+
+// piecePassphrase returns the passphrase of the i-th backup piece, read from
+// the passphrase files flag or prompted for.
+func piecePassphrase(c *cli.Context, i int, name string, confirm bool) ([]byte, error) {
+	files := c.StringSlice(piecePassphraseFilesFlag.Name)
+	if len(files) > 0 {
+		if i >= len(files) {
+			return nil, fmt.Errorf("no passphrase file for piece %s", name)
+		}
+		buff, err := ioutil.ReadFile(files[i])
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimRight(buff, "\r\n"), nil
+	}
+	fmt.Printf("drand: passphrase of the custodian of %s\n", name)
+	return promptPassphrase(confirm)
+}
+
+// backupShareCmd splits the share of the node in encrypted pieces, saved in the
+// given folder, to give to custodians.
+func backupShareCmd(c *cli.Context) error {
+	if c.NArg() != 1 {
+		fatal("drand: backup-share needs the folder where to save the pieces")
+	}
+	if !c.IsSet(piecesFlag.Name) || !c.IsSet(backupThresholdFlag.Name) {
+		fatal("drand: backup-share needs --%s and --%s", piecesFlag.Name, backupThresholdFlag.Name)
+	}
+	conf := contextToConfig(c)
+	share, err := keyStore(c, conf).LoadShare()
+	if err != nil {
+		fatal("drand: can't load share: %s", err)
+	}
+	pieces, err := key.BackupShare(share, c.Int(backupThresholdFlag.Name), c.Int(piecesFlag.Name))
+	if err != nil {
+		fatal("drand: can't split share: %s", err)
+	}
+	folder := c.Args().First()
+	if err := os.MkdirAll(folder, 0700); err != nil {
+		fatal("drand: can't create backup folder: %s", err)
+	}
+	for i, piece := range pieces {
+		name := fmt.Sprintf("share_backup_%d.toml", i+1)
+		pass, err := piecePassphrase(c, i, name, true)
+		if err != nil {
+			fatal("drand: can't read passphrase: %s", err)
+		}
+		if err := key.SaveEncrypted(path.Join(folder, name), piece, pass); err != nil {
+			fatal("drand: can't save backup piece: %s", err)
+		}
+	}
+	fmt.Printf("drand: share split in %d pieces in %s, %d of them restore it\n", len(pieces), folder, c.Int(backupThresholdFlag.Name))
+	return nil
+}
+
+// restoreShareCmd reconstructs the share of the node from the given backup
+// pieces and saves it.
+func restoreShareCmd(c *cli.Context) error {
+	if c.NArg() == 0 {
+		fatal("drand: restore-share needs the backup pieces")
+	}
+	conf := contextToConfig(c)
+	if _, err := os.Stat(key.ShareFile(conf.ConfigFolder())); err == nil {
+		fatal("drand: a share already exists, remove it first")
+	}
+	pieces := make([]*key.ShareBackup, c.NArg())
+	for i, file := range c.Args().Slice() {
+		pass, err := piecePassphrase(c, i, file, false)
+		if err != nil {
+			fatal("drand: can't read passphrase: %s", err)
+		}
+		pieces[i] = new(key.ShareBackup)
+		if err := key.LoadEncrypted(file, pieces[i], pass); err != nil {
+			fatal("drand: can't load backup piece %s: %s", file, err)
+		}
+	}
+	share, err := key.RestoreShare(pieces)
+	if err != nil {
+		fatal("drand: can't restore share: %s", err)
+	}
+	fs := keyStore(c, conf)
+	if group, err := fs.LoadGroup(); err == nil && group.PublicKey != nil {
+		if !group.PublicKey.Key().Equal(share.Public().Key()) {
+			fatal("drand: restored share does not match the distributed key of the group")
+		}
+	}
+	if err := fs.SaveShare(share); err != nil {
+		fatal("drand: can't save share: %s", err)
+	}
+	fmt.Println("drand: share restored")
+	return nil
+}